@@ -1,21 +1,70 @@
 package main
 
 import (
-	"bufio"
 	"flag"
 	"fmt"
 	"os"
 	"strings"
 
 	"imapsync/internal/app"
+	"imapsync/internal/i18n"
 	"imapsync/internal/ui"
 )
 
 func main() {
+	// Never leave the terminal in raw mode, even on a panic
+	defer ui.RestoreTerminal()
+
 	// Default to TUI mode, but allow CLI mode with -cli flag
 	cliMode := flag.Bool("cli", false, "Enable CLI mode (default is TUI)")
+	configPath := flag.String("config", app.DefaultConfigPath(), "Path to the JSON configuration file")
+	lang := flag.String("lang", "", "Interface language, e.g. en or tr (default from the config file or LANG)")
+	color := flag.String("color", "", "Colors: auto, never, always, 16, 256 or truecolor (default from the config file)")
+	palette := flag.String("theme", "", "Color palette: default or high-contrast (default from the config file)")
+	ascii := flag.Bool("ascii", false, "Plain ASCII symbols instead of emoji and box drawing")
 	flag.Parse()
 
+	// The environment decides until the configuration is read, so errors
+	// loading it are already translated
+	if detected := i18n.Detect(); detected != "" {
+		i18n.SetLanguage(detected)
+	}
+
+	cfg, err := app.LoadConfig(*configPath)
+	if err != nil {
+		fmt.Println(ui.Red(i18n.T("error.prefix")), err)
+		os.Exit(1)
+	}
+	app.SetConfig(cfg)
+
+	// The flag overrides the configuration file
+	if *lang == "" {
+		*lang = cfg.Language
+	}
+	if *lang != "" {
+		if err := i18n.SetLanguage(*lang); err != nil {
+			fmt.Println(ui.Yellow(i18n.T("error.warning")), err)
+		}
+	}
+
+	themeCfg := cfg.Theme
+	if *color != "" {
+		themeCfg.Color = *color
+	}
+	if *palette != "" {
+		themeCfg.Palette = *palette
+	}
+	themeCfg.ASCII = themeCfg.ASCII || *ascii
+	if theme, err := ui.NewTheme(themeCfg); err != nil {
+		fmt.Println(ui.Yellow(i18n.T("error.warning")), err)
+	} else {
+		ui.SetTheme(theme)
+	}
+
+	if err := app.ConfigureLogging(&cfg.Logging); err != nil {
+		fmt.Println(ui.Yellow(i18n.T("error.logging_disabled")), err)
+	}
+
 	if !*cliMode {
 		// Start TUI mode by default
 		app.StartSimpleInterface()
@@ -23,19 +72,19 @@ func main() {
 	}
 
 	// Original CLI mode (only when -cli flag is used)
-	reader := bufio.NewReader(os.Stdin)
+	reader := ui.Stdin()
 
 	for {
-		fmt.Println(ui.Cyan("Please select an option:"))
-		fmt.Println("1 - Setup System")
-		fmt.Println("2 - Transfer Mail")
-		fmt.Println("3 - Parallel Transfer")
-		fmt.Println("4 - Performance Stats")
-		fmt.Println("5 - Developer")
-		fmt.Println("6 - Modern TUI Interface")
-		fmt.Println("7 - Exit")
-
-		fmt.Print(ui.Green("Choice (1-7): "))
+		fmt.Println(ui.Cyan(i18n.T("cli.select")))
+		fmt.Println("1 - " + i18n.T("menu.main.setup"))
+		fmt.Println("2 - " + i18n.T("menu.main.transfer"))
+		fmt.Println("3 - " + i18n.T("menu.main.parallel"))
+		fmt.Println("4 - " + i18n.T("menu.main.stats"))
+		fmt.Println("5 - " + i18n.T("menu.main.developer"))
+		fmt.Println("6 - " + i18n.T("cli.tui"))
+		fmt.Println("7 - " + i18n.T("ui.menu.exit"))
+
+		fmt.Print(ui.Green(i18n.T("cli.choice_range", 7) + " "))
 		choice, _ := reader.ReadString('\n')
 		choice = strings.TrimSpace(choice)
 
@@ -53,10 +102,10 @@ func main() {
 		case "6":
 			app.StartSimpleInterface()
 		case "7":
-			fmt.Println(ui.Yellow("Exiting program..."))
+			fmt.Println(ui.Yellow(i18n.T("cli.exiting")))
 			return
 		default:
-			fmt.Println(ui.Red("Invalid choice. Please enter 1-7."))
+			fmt.Println(ui.Red(i18n.T("cli.invalid_range", 7)))
 		}
 	}
 }
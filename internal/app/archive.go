@@ -0,0 +1,895 @@
+package app
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ArchiveFormat is the layout of a local mailbox archive
+type ArchiveFormat string
+
+const (
+	ArchiveMbox    ArchiveFormat = "mbox"    // One mboxrd file per folder
+	ArchiveMaildir ArchiveFormat = "maildir" // One Maildir per folder, flags in the file names
+)
+
+const (
+	archiveManifestName = "manifest.json"
+	archiveCheckpoint   = 100 // Messages between manifest updates
+)
+
+// archiveFormats lists the archive formats, for choosing one in a form
+func archiveFormats() []string {
+	return []string{string(ArchiveMbox), string(ArchiveMaildir)}
+}
+
+// ParseArchiveFormat parses "mbox" or "maildir"
+func ParseArchiveFormat(s string) (ArchiveFormat, error) {
+	switch f := ArchiveFormat(strings.ToLower(strings.TrimSpace(s))); f {
+	case ArchiveMbox, ArchiveMaildir:
+		return f, nil
+	}
+	return "", fmt.Errorf("unknown archive format %q (available: mbox, maildir)", s)
+}
+
+// ArchiveTarget is the local destination of a job that archives a mailbox
+// instead of migrating it. The zero value means the job migrates to DestHost.
+type ArchiveTarget struct {
+	Format ArchiveFormat
+	Dir    string // Holds one mbox file or Maildir per folder and the manifest
+}
+
+// IsZero reports whether the target is unset
+func (t ArchiveTarget) IsZero() bool {
+	return t.Format == "" && t.Dir == ""
+}
+
+// Validate checks the format and directory
+func (t ArchiveTarget) Validate() error {
+	if _, err := ParseArchiveFormat(string(t.Format)); err != nil {
+		return err
+	}
+	if strings.TrimSpace(t.Dir) == "" {
+		return fmt.Errorf("archive directory is required")
+	}
+	return nil
+}
+
+// String describes the target for display, e.g. "/srv/archive/jdoe (mbox)"
+func (t ArchiveTarget) String() string {
+	return fmt.Sprintf("%s (%s)", t.Dir, t.Format)
+}
+
+// IsArchive reports whether the job exports the source mailbox to local files
+func (job *TransferJob) IsArchive() bool {
+	return !job.Archive.IsZero()
+}
+
+// Destination describes where the job copies to: the destination mailbox or
+// the archive
+func (job *TransferJob) Destination() string {
+	if job.IsArchive() {
+		return job.Archive.String()
+	}
+	return job.DestEmail
+}
+
+// ArchiveManifest lists the folders of an archive and how far each one got.
+// It is rewritten at every checkpoint; a later run resumes after LastUID.
+type ArchiveManifest struct {
+	Source    string           `json:"source"`
+	Host      string           `json:"host"`
+	Format    ArchiveFormat    `json:"format"`
+	UpdatedAt time.Time        `json:"updated_at"`
+	Folders   []*ArchiveFolder `json:"folders"`
+}
+
+// ArchiveFolder is a folder in the manifest
+type ArchiveFolder struct {
+	Name        string `json:"name"`
+	Path        string `json:"path"` // mbox file or Maildir, relative to the archive directory
+	UIDValidity uint32 `json:"uid_validity"`
+	LastUID     uint32 `json:"last_uid"` // Highest UID archived or skipped by the filter
+	Messages    int    `json:"messages"`
+	Bytes       int64  `json:"bytes"`
+	Size        int64  `json:"size,omitempty"` // Length of the mbox file at the checkpoint; later writes are dropped on resume
+}
+
+// loadArchiveManifest reads the manifest of an archive directory. A missing
+// manifest is an empty archive.
+func loadArchiveManifest(dir string) (*ArchiveManifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, archiveManifestName))
+	if errors.Is(err, os.ErrNotExist) {
+		return &ArchiveManifest{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive manifest: %w", err)
+	}
+
+	var m ArchiveManifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("invalid archive manifest: %w", err)
+	}
+	return &m, nil
+}
+
+// save writes the manifest through a temporary file so a crash never leaves
+// it half written
+func (m *ArchiveManifest) save(dir string) error {
+	m.UpdatedAt = time.Now()
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(dir, archiveManifestName)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("failed to write archive manifest: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write archive manifest: %w", err)
+	}
+	return nil
+}
+
+// lookup returns the manifest entry of a folder, or nil
+func (m *ArchiveManifest) lookup(name string) *ArchiveFolder {
+	for _, f := range m.Folders {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// archiveFolderPath returns the path of a folder inside the archive, one
+// directory per level of the folder hierarchy, e.g. "Archive/2020.mbox"
+func archiveFolderPath(folder imapFolder, format ArchiveFormat) string {
+	name := decodeFolderName(folder.Name)
+	segments := []string{name}
+	if folder.Delimiter != "" {
+		segments = strings.Split(name, folder.Delimiter)
+	}
+	for i, s := range segments {
+		segments[i] = sanitizePathSegment(s, format)
+	}
+
+	p := path.Join(segments...)
+	if format == ArchiveMbox {
+		p += ".mbox"
+	}
+	return p
+}
+
+// unsafePathRe matches characters that are not allowed in file names on some platforms
+var unsafePathRe = regexp.MustCompile(`[/\\:*?"<>|\x00-\x1f]`)
+
+// sanitizePathSegment makes a folder name safe as a file name
+func sanitizePathSegment(s string, format ArchiveFormat) string {
+	s = strings.TrimRight(unsafePathRe.ReplaceAllString(s, "_"), ". ")
+	switch {
+	case s == "":
+		return "_"
+	case format == ArchiveMaildir && (s == "cur" || s == "new" || s == "tmp"):
+		// A subfolder must not clash with the Maildir of its parent
+		return "_" + s
+	}
+	return s
+}
+
+// selectArchiveFolders returns the folders of a job's options and filter.
+// Unlike imapsync jobs, archives keep Trash and Junk unless excluded.
+func selectArchiveFolders(folders []imapFolder, job *TransferJob) ([]imapFolder, error) {
+	compile := func(patterns []string) ([]*regexp.Regexp, error) {
+		var res []*regexp.Regexp
+		for _, p := range patterns {
+			re, err := regexp.Compile(p)
+			if err != nil {
+				return nil, fmt.Errorf("invalid folder regex %q: %w", p, err)
+			}
+			res = append(res, re)
+		}
+		return res, nil
+	}
+	includes, err := compile(job.Filter.IncludeFolders)
+	if err != nil {
+		return nil, err
+	}
+	excludes, err := compile(job.Options.ExcludeFolders)
+	if err != nil {
+		return nil, err
+	}
+	matchAny := func(res []*regexp.Regexp, name string) bool {
+		for _, re := range res {
+			if re.MatchString(name) {
+				return true
+			}
+		}
+		return false
+	}
+
+	var selected []imapFolder
+	for _, f := range folders {
+		name := decodeFolderName(f.Name)
+		if f.NoSelect {
+			continue
+		}
+		if len(job.Options.Folders) > 0 && !containsString(job.Options.Folders, name) && !containsString(job.Options.Folders, f.Name) {
+			continue
+		}
+		if len(includes) > 0 && !matchAny(includes, name) {
+			continue
+		}
+		if matchAny(excludes, name) {
+			continue
+		}
+		selected = append(selected, f)
+	}
+	return selected, nil
+}
+
+// containsString reports whether list holds s
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// archiveCriteria builds the IMAP SEARCH criteria for the messages after
+// lastUID that pass the filter. Ages count from the internal date, like
+// imapsync's --minage and --maxage. Headers are matched after fetching.
+func archiveCriteria(f MessageFilter, lastUID uint32, now time.Time) string {
+	keys := []string{fmt.Sprintf("UID %d:*", lastUID+1)}
+	if f.MinAge > 0 {
+		keys = append(keys, "BEFORE "+imapDate(now.AddDate(0, 0, -f.MinAge)))
+	}
+	if f.MaxAge > 0 {
+		keys = append(keys, "SINCE "+imapDate(now.AddDate(0, 0, -f.MaxAge)))
+	}
+	if f.MaxSize > 0 {
+		keys = append(keys, fmt.Sprintf("SMALLER %d", f.MaxSize+1))
+	}
+	return strings.Join(append(keys, searchKeys(f)...), " ")
+}
+
+// newerUIDs drops the UIDs up to last. "UID n:*" always matches the highest
+// UID, even when it is below n.
+func newerUIDs(uids []uint32, last uint32) []uint32 {
+	var newer []uint32
+	for _, uid := range uids {
+		if uid > last {
+			newer = append(newer, uid)
+		}
+	}
+	return newer
+}
+
+// headerMatcher compiles the header regex of a filter so that ^ and $ match
+// at every header line; nil matches every message
+func headerMatcher(f MessageFilter) (*regexp.Regexp, error) {
+	if f.HeaderRegex == "" {
+		return nil, nil
+	}
+	return regexp.Compile("(?m)" + f.HeaderRegex)
+}
+
+// messageHeader returns the header block of a message with LF line endings
+func messageHeader(body []byte) []byte {
+	header := body
+	if i := bytes.Index(body, []byte("\r\n\r\n")); i >= 0 {
+		header = body[:i+2]
+	} else if i := bytes.Index(body, []byte("\n\n")); i >= 0 {
+		header = body[:i+1]
+	}
+	return bytes.ReplaceAll(header, []byte("\r\n"), []byte("\n"))
+}
+
+// archiveError classifies a failure to talk to the source server. The
+// archive has no destination server, so failures always belong to host 1.
+func archiveError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	te := &TransferError{Reason: FailureUnknown, ExitCode: -1, Host: 1, Err: err, Op: "archive"}
+	var statusErr *imapStatusError
+	var certErr *tls.CertificateVerificationError
+	var recordErr tls.RecordHeaderError
+	var alertErr tls.AlertError
+	var netErr net.Error
+	switch {
+	case throttleRe.MatchString(err.Error()):
+		te.Reason = FailureThrottled
+	case errors.As(err, &statusErr) && statusErr.command == "LOGIN":
+		te.Reason = FailureAuth
+	case errors.As(err, &certErr) || errors.As(err, &recordErr) || errors.As(err, &alertErr):
+		te.Reason = FailureTLS
+	case errors.As(err, &netErr) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF):
+		te.Reason = FailureUnreachable
+	default:
+		for _, p := range failurePatterns {
+			if p.re.MatchString(err.Error()) {
+				te.Reason = p.reason
+				break
+			}
+		}
+	}
+	return te
+}
+
+// openArchiveSource connects and logs in to the source server of a job
+func openArchiveSource(ctx context.Context, job *TransferJob) (*imapClient, error) {
+	client, err := dialIMAP(ctx, job.SourceHost, !job.Options.NoSSL)
+	if err != nil {
+		return nil, archiveError(err)
+	}
+	if err := client.Login(job.SourceEmail, job.SourcePass); err != nil {
+		client.Close()
+		return nil, archiveError(err)
+	}
+	return client, nil
+}
+
+// checkArchiveLogin checks the source login of an archive job
+func checkArchiveLogin(ctx context.Context, job *TransferJob) error {
+	client, err := openArchiveSource(ctx, job)
+	if err != nil {
+		return err
+	}
+	client.Logout()
+	return nil
+}
+
+// archiveWriter appends messages to the archive of one folder
+type archiveWriter interface {
+	Write(msg *imapMessage) error
+	Sync() (int64, error) // Flushes to disk and returns the size to resume from
+	Close() error
+}
+
+// mboxWriter writes an mboxrd file: lines of the message that look like a
+// From_ line, after any number of '>', get one more '>'
+type mboxWriter struct {
+	f    *os.File
+	w    *bufio.Writer
+	size int64 // End of the last complete message
+}
+
+// fromLineRe matches lines that mboxrd quotes
+var fromLineRe = regexp.MustCompile(`^>*From `)
+
+// openMbox opens an mbox file to append at size, dropping anything after it
+func openMbox(path string, size int64) (*mboxWriter, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.Truncate(size); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if _, err := f.Seek(size, io.SeekStart); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &mboxWriter{f: f, w: bufio.NewWriter(f), size: size}, nil
+}
+
+// Write appends a message with its From_ line and a trailing empty line
+func (m *mboxWriter) Write(msg *imapMessage) error {
+	date := msg.InternalDate
+	if date.IsZero() {
+		date = time.Now()
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From MAILER-DAEMON %s\n", date.UTC().Format(time.ANSIC))
+	body := bytes.ReplaceAll(msg.Body, []byte("\r\n"), []byte("\n"))
+	for len(body) > 0 {
+		line := body
+		if i := bytes.IndexByte(body, '\n'); i >= 0 {
+			line = body[:i+1]
+		}
+		body = body[len(line):]
+		if fromLineRe.Match(line) {
+			b.WriteByte('>')
+		}
+		b.Write(line)
+	}
+	if !bytes.HasSuffix(b.Bytes(), []byte("\n")) {
+		b.WriteByte('\n')
+	}
+	b.WriteByte('\n')
+
+	n, err := m.w.Write(b.Bytes())
+	if err != nil {
+		return err
+	}
+	m.size += int64(n)
+	return nil
+}
+
+// Sync flushes the buffered messages
+func (m *mboxWriter) Sync() (int64, error) {
+	if err := m.w.Flush(); err != nil {
+		return 0, err
+	}
+	return m.size, m.f.Sync()
+}
+
+// Close flushes and closes the file
+func (m *mboxWriter) Close() error {
+	m.w.Flush()
+	return m.f.Close()
+}
+
+// maildirWriter writes one file per message into cur, named
+// "<time>.U<uid>V<uidvalidity>.<host>:2,<flags>". The names are stable, so a
+// message written again after a crash replaces its earlier copy.
+type maildirWriter struct {
+	dir         string
+	host        string
+	uidValidity uint32
+}
+
+// openMaildir creates the cur, new and tmp directories of a Maildir
+func openMaildir(dir string, uidValidity uint32) (*maildirWriter, error) {
+	for _, sub := range []string{"cur", "new", "tmp"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0700); err != nil {
+			return nil, err
+		}
+	}
+
+	host, _ := os.Hostname()
+	if host == "" {
+		host = "localhost"
+	}
+	// The Maildir spec encodes / and : in host names as octal escapes
+	host = strings.NewReplacer("/", `\057`, ":", `\072`).Replace(host)
+	return &maildirWriter{dir: dir, host: host, uidValidity: uidValidity}, nil
+}
+
+// maildirFlags maps IMAP flags to Maildir info flags in ASCII order
+func maildirFlags(flags []string) string {
+	letters := map[string]byte{
+		`\draft`: 'D', `\flagged`: 'F', `$forwarded`: 'P', `\answered`: 'R', `\seen`: 'S', `\deleted`: 'T',
+	}
+	var set [128]bool
+	for _, flag := range flags {
+		if c, ok := letters[strings.ToLower(flag)]; ok {
+			set[c] = true
+		}
+	}
+
+	var b strings.Builder
+	for _, c := range "DFPRST" {
+		if set[c] {
+			b.WriteRune(c)
+		}
+	}
+	return b.String()
+}
+
+// Write stores a message in tmp and moves it to cur
+func (m *maildirWriter) Write(msg *imapMessage) error {
+	date := msg.InternalDate
+	if date.IsZero() {
+		date = time.Now()
+	}
+	base := fmt.Sprintf("%d.U%dV%d.%s", date.Unix(), msg.UID, m.uidValidity, m.host)
+
+	// The flags may have changed since an earlier copy was written
+	earlier, _ := filepath.Glob(filepath.Join(m.dir, "cur", base+":*"))
+	for _, path := range earlier {
+		os.Remove(path)
+	}
+
+	tmp := filepath.Join(m.dir, "tmp", base)
+	if err := os.WriteFile(tmp, bytes.ReplaceAll(msg.Body, []byte("\r\n"), []byte("\n")), 0600); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	os.Chtimes(tmp, date, date)
+	return os.Rename(tmp, filepath.Join(m.dir, "cur", base+":2,"+maildirFlags(msg.Flags)))
+}
+
+// Sync is a no-op; every message is complete once it is in cur
+func (m *maildirWriter) Sync() (int64, error) {
+	return 0, nil
+}
+
+// Close is a no-op
+func (m *maildirWriter) Close() error {
+	return nil
+}
+
+// openArchiveWriter opens the archive of a folder for appending
+func openArchiveWriter(target ArchiveTarget, entry *ArchiveFolder) (archiveWriter, error) {
+	path := filepath.Join(target.Dir, filepath.FromSlash(entry.Path))
+	if target.Format == ArchiveMaildir {
+		return openMaildir(path, entry.UIDValidity)
+	}
+	return openMbox(path, entry.Size)
+}
+
+// archiveFolderWork is a folder to export and the messages it still needs
+type archiveFolderWork struct {
+	folder      imapFolder
+	name        string
+	uidValidity uint32
+	exists      int
+	uids        []uint32
+}
+
+// scanArchiveFolders lists the selected folders of a job with the UIDs that
+// are not yet in the archive. Folders whose UIDVALIDITY changed start over.
+func scanArchiveFolders(client *imapClient, job *TransferJob, manifest *ArchiveManifest, foldersOnly bool) ([]archiveFolderWork, error) {
+	folders, err := client.List()
+	if err != nil {
+		return nil, archiveError(err)
+	}
+	folders, err = selectArchiveFolders(folders, job)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var work []archiveFolderWork
+	for _, folder := range folders {
+		w := archiveFolderWork{folder: folder, name: decodeFolderName(folder.Name)}
+		w.uidValidity, w.exists, err = client.Examine(folder.Name)
+		if err != nil {
+			return nil, archiveError(fmt.Errorf("folder %s: %w", w.name, err))
+		}
+
+		if !foldersOnly && w.exists > 0 {
+			var last uint32
+			if entry := manifest.lookup(w.name); entry != nil && entry.UIDValidity == w.uidValidity {
+				last = entry.LastUID
+			}
+			uids, err := client.SearchUIDs(archiveCriteria(job.Filter, last, now))
+			if err != nil {
+				return nil, archiveError(fmt.Errorf("folder %s: %w", w.name, err))
+			}
+			w.uids = newerUIDs(uids, last)
+		}
+		work = append(work, w)
+	}
+	return work, nil
+}
+
+// previewArchive plans an archive job without writing anything. Messages
+// excluded by a header filter are still counted, as headers are only
+// matched after fetching.
+func previewArchive(ctx context.Context, job *TransferJob, foldersOnly bool) (*TransferPlan, error) {
+	plan := &TransferPlan{
+		JobID:       job.ID,
+		Source:      job.SourceEmail,
+		Destination: job.Destination(),
+		FoldersOnly: foldersOnly || job.Options.JustFolders,
+		Filter:      job.Filter.String(),
+		Excludes:    job.Options.ExcludeFolders,
+		CreatedAt:   time.Now(),
+	}
+
+	err := planArchive(ctx, job, plan)
+	if err != nil {
+		if ctx.Err() != nil {
+			err = ctx.Err()
+		}
+		plan.Error = err.Error()
+		return plan, err
+	}
+	return plan, nil
+}
+
+// planArchive fills in the folders of an archive plan
+func planArchive(ctx context.Context, job *TransferJob, plan *TransferPlan) error {
+	manifest, err := loadArchiveManifest(job.Archive.Dir)
+	if err != nil {
+		return err
+	}
+	client, err := openArchiveSource(ctx, job)
+	if err != nil {
+		return err
+	}
+	defer client.Logout()
+
+	work, err := scanArchiveFolders(client, job, manifest, plan.FoldersOnly)
+	if err != nil {
+		return err
+	}
+	for _, w := range work {
+		f := PlannedFolder{
+			Source:         w.name,
+			Destination:    archiveFolderPath(w.folder, job.Archive.Format),
+			Create:         manifest.lookup(w.name) == nil,
+			SourceMessages: w.exists,
+			Messages:       len(w.uids),
+		}
+		if len(w.uids) > 0 {
+			if _, _, err := client.Examine(w.folder.Name); err != nil {
+				return archiveError(err)
+			}
+			sizes, err := client.FetchSizes(w.uids)
+			if err != nil {
+				return archiveError(err)
+			}
+			for _, size := range sizes {
+				f.Bytes += size
+			}
+		}
+		plan.Folders = append(plan.Folders, f)
+		plan.Messages += f.Messages
+		plan.Bytes += f.Bytes
+	}
+	return nil
+}
+
+// archiveThrottle paces an export to a bandwidth limit
+type archiveThrottle struct {
+	limit    BandwidthLimit
+	start    time.Time
+	bytes    int64
+	messages int
+}
+
+// wait counts a message and sleeps until the limit allows the next one
+func (t *archiveThrottle) wait(ctx context.Context, size int64) error {
+	t.bytes += size
+	t.messages++
+
+	var due time.Duration
+	if t.limit.BytesPerSecond > 0 {
+		due = time.Duration(float64(t.bytes) / float64(t.limit.BytesPerSecond) * float64(time.Second))
+	}
+	if t.limit.MessagesPerSecond > 0 {
+		if d := time.Duration(float64(t.messages) / t.limit.MessagesPerSecond * float64(time.Second)); d > due {
+			due = d
+		}
+	}
+
+	delay := time.Until(t.start.Add(due))
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// runArchive exports the source mailbox of a job to its archive directory.
+// A dry run only makes the plan.
+func (ptm *ParallelTransferManager) runArchive(ctx context.Context, job *TransferJob) error {
+	if job.Options.DryRun {
+		plan, err := previewArchive(ctx, job, job.Options.JustFolders)
+		ptm.mu.Lock()
+		job.Plan = plan
+		ptm.mu.Unlock()
+		return err
+	}
+
+	logger := ptm.logger.WithJob(job)
+	jobLog, err := openJobLog(job.ID)
+	if err != nil {
+		logger.Warn("Failed to open job log: %v", err)
+	}
+	logf := func(format string, args ...interface{}) {
+		if jobLog != nil {
+			fmt.Fprintf(jobLog, "%s %s\n", time.Now().Format("2006-01-02 15:04:05"), fmt.Sprintf(format, args...))
+		}
+	}
+	if jobLog != nil {
+		defer jobLog.Close()
+		ptm.mu.Lock()
+		job.LogFile = jobLog.Path()
+		ptm.mu.Unlock()
+		fmt.Fprintf(jobLog, "=== %s archive started (%s) ===\n", time.Now().Format("2006-01-02 15:04:05"), job.Destination())
+	}
+
+	err = ptm.exportArchive(ctx, job, logf)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err != nil && jobLog != nil {
+		fmt.Fprintf(jobLog, "=== %s %v ===\n", time.Now().Format("2006-01-02 15:04:05"), err)
+	}
+	return err
+}
+
+// exportArchive copies the new messages of every selected folder, resuming
+// each folder after the last UID in the manifest
+func (ptm *ParallelTransferManager) exportArchive(ctx context.Context, job *TransferJob, logf func(string, ...interface{})) error {
+	dir := job.Archive.Dir
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create archive directory: %w", err)
+	}
+	manifest, err := loadArchiveManifest(dir)
+	if err != nil {
+		return err
+	}
+	if manifest.Format != "" && manifest.Format != job.Archive.Format {
+		return fmt.Errorf("archive %s holds %s, not %s", dir, manifest.Format, job.Archive.Format)
+	}
+	manifest.Source, manifest.Host, manifest.Format = job.SourceEmail, job.SourceHost, job.Archive.Format
+
+	header, err := headerMatcher(job.Filter)
+	if err != nil {
+		return err
+	}
+
+	client, err := openArchiveSource(ctx, job)
+	if err != nil {
+		return err
+	}
+	defer client.Logout()
+
+	work, err := scanArchiveFolders(client, job, manifest, job.Options.JustFolders)
+	if err != nil {
+		return err
+	}
+	total := 0
+	for _, w := range work {
+		total += len(w.uids)
+	}
+	logf("%d folders, %d new messages", len(work), total)
+
+	p := &archiveProgress{total: total}
+	var failed []string
+	for _, w := range work {
+		ptm.setJobFolder(job, w.name)
+		err := ptm.archiveFolder(ctx, job, client, manifest, w, header, p)
+		if err == nil {
+			logf("Folder %s archived to %s", w.name, archiveFolderPath(w.folder, job.Archive.Format))
+			continue
+		}
+
+		// A folder the server refuses is skipped; anything else stops the export
+		var statusErr *imapStatusError
+		if ctx.Err() != nil || !errors.As(err, &statusErr) {
+			return err
+		}
+		logf("Folder %s failed: %v", w.name, err)
+		failed = append(failed, w.name)
+	}
+
+	if len(failed) > 0 {
+		return &TransferError{
+			Reason:   FailurePartial,
+			ExitCode: -1,
+			Host:     1,
+			Op:       "archive",
+			Detail:   fmt.Sprintf("%d of %d folders failed: %s", len(failed), len(work), strings.Join(failed, ", ")),
+		}
+	}
+	ptm.updateJobProgress(job, 100)
+	return nil
+}
+
+// archiveProgress counts the messages of an export across folders
+type archiveProgress struct {
+	done  int
+	total int
+}
+
+// archiveFolder exports the new messages of one folder. The manifest is saved
+// every archiveCheckpoint messages and when the folder ends, even on failure.
+func (ptm *ParallelTransferManager) archiveFolder(ctx context.Context, job *TransferJob, client *imapClient, manifest *ArchiveManifest, w archiveFolderWork, header *regexp.Regexp, p *archiveProgress) (err error) {
+	dir := job.Archive.Dir
+	entry := manifest.lookup(w.name)
+	if entry == nil {
+		entry = &ArchiveFolder{Name: w.name, Path: archiveFolderPath(w.folder, job.Archive.Format)}
+		// Never append to or truncate files the manifest does not know about
+		if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(entry.Path))); err == nil {
+			return fmt.Errorf("archive %s already exists but is not in the manifest", entry.Path)
+		}
+		manifest.Folders = append(manifest.Folders, entry)
+	}
+
+	uidValidity, _, err := client.Examine(w.folder.Name)
+	if err != nil {
+		return archiveError(err)
+	}
+	if uidValidity != w.uidValidity {
+		return archiveError(fmt.Errorf("folder %s changed UIDVALIDITY during the export", w.name))
+	}
+	if entry.UIDValidity != 0 && entry.UIDValidity != uidValidity {
+		// The server renumbered the folder; keep the old copy and start over
+		old := filepath.Join(dir, filepath.FromSlash(entry.Path))
+		if err := os.Rename(old, fmt.Sprintf("%s.uidvalidity-%d", old, entry.UIDValidity)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to move aside renumbered archive: %w", err)
+		}
+		*entry = ArchiveFolder{Name: entry.Name, Path: entry.Path}
+	}
+	entry.UIDValidity = uidValidity
+
+	writer, err := openArchiveWriter(job.Archive, entry)
+	if err != nil {
+		return fmt.Errorf("failed to open archive %s: %w", entry.Path, err)
+	}
+	defer writer.Close()
+
+	checkpoint := func() error {
+		size, err := writer.Sync()
+		if err != nil {
+			return fmt.Errorf("failed to write archive %s: %w", entry.Path, err)
+		}
+		entry.Size = size
+		return manifest.save(dir)
+	}
+	defer func() {
+		if cerr := checkpoint(); err == nil {
+			err = cerr
+		}
+	}()
+
+	limit := ptm.allocateBandwidth(job)
+	ptm.mu.Lock()
+	job.bandwidth = limit
+	controller := ptm.controller
+	ptm.mu.Unlock()
+	throttle := archiveThrottle{limit: limit, start: time.Now()}
+
+	lastCopy := time.Now()
+	for i, uid := range w.uids {
+		msg, err := client.FetchMessage(uid)
+		if err != nil {
+			return archiveError(fmt.Errorf("folder %s: %w", w.name, err))
+		}
+		size := int64(len(msg.Body))
+
+		if header == nil || header.Match(messageHeader(msg.Body)) {
+			if err := writer.Write(msg); err != nil {
+				return fmt.Errorf("failed to write archive %s: %w", entry.Path, err)
+			}
+			entry.Messages++
+			entry.Bytes += size
+		}
+		entry.LastUID = uid
+
+		p.done++
+		ptm.addJobBytes(job, size)
+		ptm.updateJobMessages(job, p.done, p.total)
+		ptm.updateJobProgress(job, float64(p.done)*100/float64(p.total))
+		if controller != nil {
+			now := time.Now()
+			controller.RecordMessage(size, now.Sub(lastCopy))
+			lastCopy = now
+		}
+
+		if (i+1)%archiveCheckpoint == 0 {
+			if err := checkpoint(); err != nil {
+				return err
+			}
+		}
+		if err := throttle.wait(ctx, size); err != nil {
+			return err
+		}
+	}
+	return nil
+}
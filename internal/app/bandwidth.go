@@ -0,0 +1,496 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ByteRate is a transfer rate in bytes per second. In JSON it may be a number
+// or a string with a unit, e.g. "500KB" or "2MiB".
+type ByteRate int64
+
+// byteUnits maps unit suffixes to their size in bytes
+var byteUnits = map[string]int64{
+	"":    1,
+	"b":   1,
+	"k":   1000,
+	"kb":  1000,
+	"kib": 1024,
+	"m":   1000 * 1000,
+	"mb":  1000 * 1000,
+	"mib": 1024 * 1024,
+	"g":   1000 * 1000 * 1000,
+	"gb":  1000 * 1000 * 1000,
+	"gib": 1024 * 1024 * 1024,
+}
+
+// ParseByteRate parses a rate such as "750KB", "2MiB" or "100000"
+func ParseByteRate(s string) (ByteRate, error) {
+	s = strings.TrimSpace(s)
+	size, ok := parseByteSize(strings.TrimSuffix(strings.TrimSuffix(strings.ToLower(s), "/s"), "ps"))
+	if !ok {
+		return 0, fmt.Errorf("invalid byte rate %q", s)
+	}
+	return ByteRate(size), nil
+}
+
+// parseByteSize parses a size such as "25MB" or "1048576"
+func parseByteSize(s string) (int64, bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if i < 0 {
+		i = len(s)
+	}
+
+	value, err := strconv.ParseFloat(s[:i], 64)
+	unit, ok := byteUnits[strings.TrimSpace(s[i:])]
+	if err != nil || !ok || value < 0 {
+		return 0, false
+	}
+	return int64(value * float64(unit)), true
+}
+
+// UnmarshalJSON accepts either a number of bytes or a string with a unit
+func (r *ByteRate) UnmarshalJSON(data []byte) error {
+	var raw interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	switch v := raw.(type) {
+	case float64:
+		*r = ByteRate(v)
+	case string:
+		parsed, err := ParseByteRate(v)
+		if err != nil {
+			return err
+		}
+		*r = parsed
+	default:
+		return fmt.Errorf("invalid byte rate %s", string(data))
+	}
+	return nil
+}
+
+// String formats the rate for display
+func (r ByteRate) String() string {
+	switch {
+	case r >= 1024*1024:
+		return fmt.Sprintf("%.1f MiB/s", float64(r)/(1024*1024))
+	case r >= 1024:
+		return fmt.Sprintf("%.1f KiB/s", float64(r)/1024)
+	default:
+		return fmt.Sprintf("%d B/s", int64(r))
+	}
+}
+
+// BandwidthLimit caps bytes and messages per second; zero means unlimited
+type BandwidthLimit struct {
+	BytesPerSecond    ByteRate `json:"bytes_per_second"`
+	MessagesPerSecond float64  `json:"messages_per_second"`
+}
+
+// IsZero reports whether the limit leaves both rates unlimited
+func (bl BandwidthLimit) IsZero() bool {
+	return bl.BytesPerSecond <= 0 && bl.MessagesPerSecond <= 0
+}
+
+// String formats the limit for display
+func (bl BandwidthLimit) String() string {
+	if bl.IsZero() {
+		return "unlimited"
+	}
+
+	var parts []string
+	if bl.BytesPerSecond > 0 {
+		parts = append(parts, bl.BytesPerSecond.String())
+	}
+	if bl.MessagesPerSecond > 0 {
+		parts = append(parts, fmt.Sprintf("%.2f msg/s", bl.MessagesPerSecond))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// minLimit returns the tighter of two limits per rate
+func minLimit(a, b BandwidthLimit) BandwidthLimit {
+	if b.BytesPerSecond > 0 && (a.BytesPerSecond <= 0 || b.BytesPerSecond < a.BytesPerSecond) {
+		a.BytesPerSecond = b.BytesPerSecond
+	}
+	if b.MessagesPerSecond > 0 && (a.MessagesPerSecond <= 0 || b.MessagesPerSecond < a.MessagesPerSecond) {
+		a.MessagesPerSecond = b.MessagesPerSecond
+	}
+	return a
+}
+
+// share divides a limit evenly between n jobs
+func (bl BandwidthLimit) share(n int) BandwidthLimit {
+	if n <= 1 {
+		return bl
+	}
+
+	shared := BandwidthLimit{MessagesPerSecond: bl.MessagesPerSecond / float64(n)}
+	if bl.BytesPerSecond > 0 {
+		shared.BytesPerSecond = bl.BytesPerSecond / ByteRate(n)
+		if shared.BytesPerSecond < 1 {
+			shared.BytesPerSecond = 1
+		}
+	}
+	return shared
+}
+
+// BandwidthSchedule overrides the caps during a time of day, e.g. business hours
+type BandwidthSchedule struct {
+	Name   string                    `json:"name"`
+	Days   string                    `json:"days"`  // Cron day-of-week field, e.g. "1-5"; empty means every day
+	Start  string                    `json:"start"` // "HH:MM"
+	End    string                    `json:"end"`   // "HH:MM"; earlier than start wraps past midnight
+	Global BandwidthLimit            `json:"global"`
+	PerJob BandwidthLimit            `json:"per_job"`
+	Hosts  map[string]BandwidthLimit `json:"hosts"`
+}
+
+// BandwidthConfig holds bandwidth caps
+type BandwidthConfig struct {
+	Global    BandwidthLimit            `json:"global"`  // Shared fairly by all running jobs
+	PerJob    BandwidthLimit            `json:"per_job"` // Applies to every job
+	Hosts     map[string]BandwidthLimit `json:"hosts"`   // Shared by the running jobs on each host
+	Schedules []BandwidthSchedule       `json:"schedules"`
+}
+
+// bandwidthSchedule is a parsed time-of-day schedule
+type bandwidthSchedule struct {
+	config *BandwidthSchedule
+	days   uint64
+	start  int // minutes after midnight
+	end    int
+}
+
+// activeAt reports whether the schedule applies at t
+func (bs *bandwidthSchedule) activeAt(t time.Time) bool {
+	minute := t.Hour()*60 + t.Minute()
+
+	if bs.start <= bs.end {
+		return bs.days&(1<<uint(t.Weekday())) != 0 && minute >= bs.start && minute < bs.end
+	}
+
+	// Overnight window: the part after midnight belongs to the previous day
+	if minute >= bs.start {
+		return bs.days&(1<<uint(t.Weekday())) != 0
+	}
+	if minute < bs.end {
+		return bs.days&(1<<uint((t.Weekday()+6)%7)) != 0
+	}
+	return false
+}
+
+// parseClock parses "HH:MM" into minutes after midnight
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q: expected HH:MM", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// BandwidthManager allocates bandwidth caps to running jobs
+type BandwidthManager struct {
+	config    *BandwidthConfig
+	schedules []bandwidthSchedule
+
+	mu      sync.Mutex
+	running map[string]*TransferJob
+}
+
+// NewBandwidthManager validates the configuration and creates a manager
+func NewBandwidthManager(config *BandwidthConfig) (*BandwidthManager, error) {
+	if config == nil {
+		config = &BandwidthConfig{}
+	}
+
+	bm := &BandwidthManager{
+		config:  config,
+		running: make(map[string]*TransferJob),
+	}
+
+	for i := range config.Schedules {
+		sc := &config.Schedules[i]
+
+		days := "*"
+		if sc.Days != "" {
+			days = sc.Days
+		}
+		dow, err := parseCronField(days, 0, 7, dayNames)
+		if err != nil {
+			return nil, fmt.Errorf("bandwidth schedule %d: invalid days %q: %w", i+1, sc.Days, err)
+		}
+		if dow&(1<<7) != 0 {
+			dow |= 1
+		}
+
+		start, err := parseClock(sc.Start)
+		if err != nil {
+			return nil, fmt.Errorf("bandwidth schedule %d: %w", i+1, err)
+		}
+		end, err := parseClock(sc.End)
+		if err != nil {
+			return nil, fmt.Errorf("bandwidth schedule %d: %w", i+1, err)
+		}
+
+		bm.schedules = append(bm.schedules, bandwidthSchedule{
+			config: sc,
+			days:   dow,
+			start:  start,
+			end:    end,
+		})
+	}
+
+	return bm, nil
+}
+
+// Enabled reports whether any cap is configured
+func (bm *BandwidthManager) Enabled() bool {
+	return !bm.config.Global.IsZero() || !bm.config.PerJob.IsZero() ||
+		len(bm.config.Hosts) > 0 || len(bm.schedules) > 0
+}
+
+// caps returns the global, per-job and host caps in effect at t.
+// The first active schedule overrides the base caps it sets.
+func (bm *BandwidthManager) caps(t time.Time) (global, perJob BandwidthLimit, hosts map[string]BandwidthLimit) {
+	global, perJob, hosts = bm.config.Global, bm.config.PerJob, bm.config.Hosts
+
+	for i := range bm.schedules {
+		if !bm.schedules[i].activeAt(t) {
+			continue
+		}
+		sc := bm.schedules[i].config
+		if !sc.Global.IsZero() {
+			global = sc.Global
+		}
+		if !sc.PerJob.IsZero() {
+			perJob = sc.PerJob
+		}
+		if len(sc.Hosts) > 0 {
+			hosts = sc.Hosts
+		}
+		break
+	}
+	return global, perJob, hosts
+}
+
+// Register marks a job as running so it takes part in the fair share
+func (bm *BandwidthManager) Register(job *TransferJob) {
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
+
+	bm.running[job.ID] = job
+}
+
+// Unregister removes a finished job from the fair share
+func (bm *BandwidthManager) Unregister(job *TransferJob) {
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
+
+	delete(bm.running, job.ID)
+}
+
+// Allocate returns the caps for a job at t: its share of the global and host
+// caps, the per-job cap and the job's own limit, whichever is tighter
+func (bm *BandwidthManager) Allocate(job *TransferJob, t time.Time) BandwidthLimit {
+	global, perJob, hosts := bm.caps(t)
+
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
+
+	running := len(bm.running)
+	if _, ok := bm.running[job.ID]; !ok {
+		running++
+	}
+
+	limit := minLimit(perJob, global.share(running))
+	limit = minLimit(limit, job.Bandwidth)
+
+	for _, host := range []string{job.SourceHost, job.DestHost} {
+		hostLimit, ok := lookupHostBandwidth(hosts, host)
+		if !ok {
+			continue
+		}
+		limit = minLimit(limit, hostLimit.share(bm.jobsOnHost(host, job)))
+	}
+
+	return limit
+}
+
+// jobsOnHost counts the running jobs using a host, including job. Callers must hold bm.mu.
+func (bm *BandwidthManager) jobsOnHost(host string, job *TransferJob) int {
+	host = normalizeHost(host)
+
+	n := 0
+	counted := false
+	for _, other := range bm.running {
+		if normalizeHost(other.SourceHost) == host || normalizeHost(other.DestHost) == host {
+			n++
+			if other.ID == job.ID {
+				counted = true
+			}
+		}
+	}
+	if !counted {
+		n++
+	}
+	return n
+}
+
+// lookupHostBandwidth finds the cap for a host by exact name or "*.domain" pattern
+func lookupHostBandwidth(hosts map[string]BandwidthLimit, host string) (BandwidthLimit, bool) {
+	host = normalizeHost(host)
+	if host == "" {
+		return BandwidthLimit{}, false
+	}
+
+	for pattern, limit := range hosts {
+		if normalizeHost(pattern) == host {
+			return limit, true
+		}
+	}
+	for pattern, limit := range hosts {
+		if suffix, ok := strings.CutPrefix(pattern, "*"); ok && strings.HasSuffix(host, strings.ToLower(suffix)) {
+			return limit, true
+		}
+	}
+	return BandwidthLimit{}, false
+}
+
+// Running returns the running jobs
+func (bm *BandwidthManager) Running() []*TransferJob {
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
+
+	jobs := make([]*TransferJob, 0, len(bm.running))
+	for _, job := range bm.running {
+		jobs = append(jobs, job)
+	}
+	return jobs
+}
+
+// significantChange reports whether moving from one limit to another is worth
+// restarting imapsync for; small drifts are ignored
+func significantChange(from, to BandwidthLimit) bool {
+	changed := func(a, b float64) bool {
+		if a <= 0 || b <= 0 {
+			return (a <= 0) != (b <= 0)
+		}
+		ratio := a / b
+		return ratio < 0.8 || ratio > 1.25
+	}
+	return changed(float64(from.BytesPerSecond), float64(to.BytesPerSecond)) ||
+		changed(from.MessagesPerSecond, to.MessagesPerSecond)
+}
+
+// rebalanceDelay coalesces rebalancing when several jobs start or finish together
+const rebalanceDelay = 5 * time.Second
+
+// rebalanceSkipDone is the fraction of a run after which a job keeps its caps:
+// restarting it would cost more than finishing at the old rate
+const rebalanceSkipDone = 0.9
+
+// SetBandwidthLimits replaces the bandwidth caps.
+// It should be called before the first batch starts.
+func (ptm *ParallelTransferManager) SetBandwidthLimits(config *BandwidthConfig) error {
+	bm, err := NewBandwidthManager(config)
+	if err != nil {
+		return err
+	}
+
+	ptm.mu.Lock()
+	defer ptm.mu.Unlock()
+
+	ptm.bandwidth = bm
+	if ptm.bandwidthStop != nil {
+		close(ptm.bandwidthStop)
+		ptm.bandwidthStop = nil
+	}
+
+	// Schedules change the caps by time of day; re-evaluate every minute
+	if len(bm.schedules) > 0 {
+		ptm.bandwidthStop = make(chan struct{})
+		go ptm.watchBandwidthSchedules(ptm.bandwidthStop)
+	}
+	return nil
+}
+
+// watchBandwidthSchedules rebalances running jobs when a schedule starts or ends
+func (ptm *ParallelTransferManager) watchBandwidthSchedules(stop <-chan struct{}) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			ptm.rebalanceBandwidth()
+		}
+	}
+}
+
+// allocateBandwidth returns the caps a job should run with now
+func (ptm *ParallelTransferManager) allocateBandwidth(job *TransferJob) BandwidthLimit {
+	ptm.mu.RLock()
+	bm := ptm.bandwidth
+	ptm.mu.RUnlock()
+
+	return bm.Allocate(job, time.Now())
+}
+
+// scheduleRebalance rebalances running jobs shortly, coalescing bursts of starts and finishes
+func (ptm *ParallelTransferManager) scheduleRebalance() {
+	ptm.mu.Lock()
+	defer ptm.mu.Unlock()
+
+	if ptm.rebalancePending || !ptm.bandwidth.Enabled() {
+		return
+	}
+	ptm.rebalancePending = true
+
+	time.AfterFunc(rebalanceDelay, func() {
+		ptm.mu.Lock()
+		ptm.rebalancePending = false
+		ptm.mu.Unlock()
+
+		ptm.rebalanceBandwidth()
+	})
+}
+
+// rebalanceBandwidth restarts the running jobs whose own cap would change
+// significantly. Jobs whose cap stays close to the one they run with, and jobs
+// close to completion, keep running undisturbed.
+func (ptm *ParallelTransferManager) rebalanceBandwidth() {
+	ptm.mu.RLock()
+	bm := ptm.bandwidth
+	ptm.mu.RUnlock()
+
+	now := time.Now()
+	for _, job := range bm.Running() {
+		limit := bm.Allocate(job, now)
+
+		ptm.mu.Lock()
+		if job.restartRun == nil || job.restartRequested || !significantChange(job.bandwidth, limit) {
+			ptm.mu.Unlock()
+			continue
+		}
+		if done := job.fractionDone(); done >= rebalanceSkipDone {
+			ptm.logger.WithJob(job).Debug("Job %s is %.0f%% done; keeping bandwidth %s instead of %s", job.ID, done*100, job.bandwidth, limit)
+		} else {
+			ptm.logger.WithJob(job).Info("Bandwidth for job %s: %s -> %s", job.ID, job.bandwidth, limit)
+			job.restartRequested = true
+			job.restartRun()
+		}
+		ptm.mu.Unlock()
+	}
+}
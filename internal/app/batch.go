@@ -0,0 +1,222 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"imapsync/internal/i18n"
+	"imapsync/internal/ui"
+)
+
+// StartBatch runs the pending jobs of a batch and waits until they finish.
+// Pending jobs they depend on run as well, even when they belong to another batch.
+// It refuses to start while admission is closed instead of waiting for the next window.
+func (ptm *ParallelTransferManager) StartBatch(batchID string) error {
+	if len(ptm.batchJobs(batchID)) == 0 {
+		return fmt.Errorf("batch %s not found", batchID)
+	}
+	if !ptm.Admitting() {
+		return ErrAdmissionClosed
+	}
+
+	ptm.logger.Info("Starting batch %s", batchID)
+	ptm.runJobs(func(job *TransferJob) bool {
+		return job.BatchID == batchID
+	})
+	return nil
+}
+
+// CancelBatch cancels the running, waiting and pending jobs of a batch
+func (ptm *ParallelTransferManager) CancelBatch(batchID string) (int, error) {
+	jobs := ptm.batchJobs(batchID)
+	if len(jobs) == 0 {
+		return 0, fmt.Errorf("batch %s not found", batchID)
+	}
+
+	ptm.mu.Lock()
+	cancelled := 0
+	for _, job := range jobs {
+		switch job.Status {
+		case StatusRunning, StatusWaiting, StatusPending:
+			job.Status = StatusCancelled
+			if job.cancel != nil {
+				job.cancel()
+			}
+			ptm.notify(EventJobCancelled, job)
+			cancelled++
+		}
+	}
+	ptm.logger.Info("Cancelled %d jobs of batch %s", cancelled, batchID)
+	ptm.mu.Unlock()
+
+	// Cancelled jobs still in the queue are dropped by the workers
+	ptm.qmu.Lock()
+	ptm.qcond.Broadcast()
+	ptm.qmu.Unlock()
+	return cancelled, nil
+}
+
+// GetBatchSummary returns the status counts of the jobs in a batch
+func (ptm *ParallelTransferManager) GetBatchSummary(batchID string) map[TransferStatus]int {
+	summary := make(map[TransferStatus]int)
+	for _, job := range ptm.batchJobs(batchID) {
+		summary[job.Status]++
+	}
+	return summary
+}
+
+// BatchIDs returns the IDs of all batches in sorted order
+func (ptm *ParallelTransferManager) BatchIDs() []string {
+	ptm.mu.RLock()
+	defer ptm.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	var ids []string
+	for _, job := range ptm.jobs {
+		if job.BatchID != "" && !seen[job.BatchID] {
+			seen[job.BatchID] = true
+			ids = append(ids, job.BatchID)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// batchJobs returns the jobs of a batch
+func (ptm *ParallelTransferManager) batchJobs(batchID string) []*TransferJob {
+	ptm.mu.RLock()
+	defer ptm.mu.RUnlock()
+
+	var jobs []*TransferJob
+	for _, job := range ptm.jobs {
+		if batchID != "" && job.BatchID == batchID {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs
+}
+
+// withPendingDependencies adds the pending jobs that the given jobs depend on,
+// directly or indirectly. Callers must hold ptm.mu.
+func (ptm *ParallelTransferManager) withPendingDependencies(jobs []*TransferJob) []*TransferJob {
+	included := make(map[string]bool, len(jobs))
+	for _, job := range jobs {
+		included[job.ID] = true
+	}
+
+	for i := 0; i < len(jobs); i++ {
+		for _, depID := range jobs[i].DependsOn {
+			dep, exists := ptm.jobs[depID]
+			if exists && !included[depID] && dep.Status == StatusPending {
+				included[depID] = true
+				jobs = append(jobs, dep)
+			}
+		}
+	}
+	return jobs
+}
+
+// dependencyOrder reorders jobs so that each one follows the jobs of the list it
+// depends on, keeping the given order otherwise. Queued by priority alone, high
+// priority dependents could fill a bounded queue and leave no room for the
+// jobs they wait for.
+func dependencyOrder(jobs []*TransferJob) []*TransferJob {
+	byID := make(map[string]*TransferJob, len(jobs))
+	for _, job := range jobs {
+		byID[job.ID] = job
+	}
+
+	placed := make(map[string]bool, len(jobs))
+	ordered := make([]*TransferJob, 0, len(jobs))
+	var place func(job *TransferJob)
+	place = func(job *TransferJob) {
+		if placed[job.ID] {
+			return
+		}
+		placed[job.ID] = true
+		for _, depID := range job.DependsOn {
+			if dep, ok := byID[depID]; ok {
+				place(dep)
+			}
+		}
+		ordered = append(ordered, job)
+	}
+	for _, job := range jobs {
+		place(job)
+	}
+	return ordered
+}
+
+// dependenciesReady reports whether every dependency of a job has completed.
+// It returns an error when a dependency failed or was cancelled, or when it is
+// paused or waiting outside the job's batch with nothing queued to run it,
+// since the job can then never run. Callers must hold ptm.qmu and ptm.mu.
+func (ptm *ParallelTransferManager) dependenciesReady(job *TransferJob, batch *jobBatch) (bool, error) {
+	ready := true
+	for _, depID := range job.DependsOn {
+		dep, exists := ptm.jobs[depID]
+		if !exists {
+			return false, fmt.Errorf("dependency %s not found", depID)
+		}
+		switch dep.Status {
+		case StatusCompleted:
+		case StatusFailed, StatusCancelled:
+			return false, fmt.Errorf("dependency %s %s", depID, dep.Status)
+		case StatusPending, StatusRunning:
+			ready = false
+		default:
+			if !ptm.scheduled(depID, batch) {
+				return false, fmt.Errorf("dependency %s is %s and not part of this batch", depID, dep.Status)
+			}
+			ready = false
+		}
+	}
+	return ready, nil
+}
+
+// scheduled reports whether a job is queued or running in the given batch or
+// the batch that is currently running. Callers must hold ptm.qmu.
+func (ptm *ParallelTransferManager) scheduled(jobID string, batch *jobBatch) bool {
+	return batch != nil && batch.members[jobID] || ptm.batch != nil && ptm.batch.members[jobID]
+}
+
+// summaryStatuses is the order statuses are listed in summaries
+var summaryStatuses = []TransferStatus{
+	StatusPending, StatusWaiting, StatusRunning, StatusPaused,
+	StatusCompleted, StatusFailed, StatusCancelled,
+}
+
+// formatSummary renders status counts on one line, e.g. "2 completed, 1 failed"
+func formatSummary(summary map[TransferStatus]int) string {
+	var parts []string
+	for _, status := range summaryStatuses {
+		if count := summary[status]; count > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", count, ui.StatusLabel(string(status))))
+		}
+	}
+	if len(parts) == 0 {
+		return i18n.T("summary.no_jobs")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// summaryLines renders status counts one per line, e.g. "Pending: 2"
+func summaryLines(summary map[TransferStatus]int) string {
+	var sb strings.Builder
+	for _, status := range summaryStatuses {
+		sb.WriteString(i18n.T("summary.line", i18n.T("summary.status."+string(status)), summary[status]) + "\n")
+	}
+	return sb.String()
+}
+
+// splitList parses a comma separated list, dropping empty entries
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,347 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned when a job's server is considered down
+var ErrCircuitOpen = errors.New("circuit breaker open: server is unavailable")
+
+// CircuitState is the state of a per-host circuit breaker
+type CircuitState string
+
+const (
+	CircuitClosed   CircuitState = "closed"    // Jobs run normally
+	CircuitOpen     CircuitState = "open"      // Jobs wait until a probe succeeds
+	CircuitHalfOpen CircuitState = "half-open" // One job may run as a trial
+)
+
+// CircuitBreakerConfig holds circuit breaker settings
+type CircuitBreakerConfig struct {
+	Threshold     int      `json:"threshold"`      // Consecutive connection failures that open a breaker; 0 disables
+	ProbeInterval Duration `json:"probe_interval"` // How often an open breaker probes with --justlogin
+}
+
+// DefaultCircuitBreakerConfig returns default circuit breaker settings
+func DefaultCircuitBreakerConfig() CircuitBreakerConfig {
+	return CircuitBreakerConfig{
+		Threshold:     3,
+		ProbeInterval: Duration{time.Minute},
+	}
+}
+
+// BreakerStatus describes one host's circuit breaker for display
+type BreakerStatus struct {
+	Host      string
+	State     CircuitState
+	Failures  int
+	OpenedAt  time.Time
+	NextProbe time.Time
+	LastError string
+}
+
+// hostBreaker is the state of a single host
+type hostBreaker struct {
+	state     CircuitState
+	failures  int
+	openedAt  time.Time
+	nextProbe time.Time
+	lastError string
+	trial     bool // A half-open trial job is in flight
+}
+
+// BreakerRegistry keeps a circuit breaker per IMAP host
+type BreakerRegistry struct {
+	config *CircuitBreakerConfig
+
+	mu    sync.Mutex
+	hosts map[string]*hostBreaker
+}
+
+// NewBreakerRegistry creates a registry; a nil config uses the defaults
+func NewBreakerRegistry(config *CircuitBreakerConfig) *BreakerRegistry {
+	if config == nil {
+		defaults := DefaultCircuitBreakerConfig()
+		config = &defaults
+	}
+	if config.ProbeInterval.Duration <= 0 {
+		config.ProbeInterval = DefaultCircuitBreakerConfig().ProbeInterval
+	}
+
+	return &BreakerRegistry{
+		config: config,
+		hosts:  make(map[string]*hostBreaker),
+	}
+}
+
+// breaker returns the breaker of a normalized host. Callers must hold br.mu.
+func (br *BreakerRegistry) breaker(host string) *hostBreaker {
+	b, ok := br.hosts[host]
+	if !ok {
+		b = &hostBreaker{state: CircuitClosed}
+		br.hosts[host] = b
+	}
+	return b
+}
+
+// Blocked reports whether jobs for the host must wait
+func (br *BreakerRegistry) Blocked(host string) bool {
+	br.mu.Lock()
+	defer br.mu.Unlock()
+
+	b, ok := br.hosts[normalizeHost(host)]
+	if !ok {
+		return false
+	}
+	return b.state == CircuitOpen || (b.state == CircuitHalfOpen && b.trial)
+}
+
+// Allow reports whether a job for both hosts may run now.
+// In the half-open state the first caller becomes the trial job.
+func (br *BreakerRegistry) Allow(sourceHost, destHost string) bool {
+	br.mu.Lock()
+	defer br.mu.Unlock()
+
+	hosts := []string{normalizeHost(sourceHost), normalizeHost(destHost)}
+	for _, host := range hosts {
+		if b, ok := br.hosts[host]; ok && (b.state == CircuitOpen || (b.state == CircuitHalfOpen && b.trial)) {
+			return false
+		}
+	}
+	for _, host := range hosts {
+		if b, ok := br.hosts[host]; ok && b.state == CircuitHalfOpen {
+			b.trial = true
+		}
+	}
+	return true
+}
+
+// RecordSuccess closes the host's breaker; it reports whether the breaker was not closed before
+func (br *BreakerRegistry) RecordSuccess(host string) bool {
+	br.mu.Lock()
+	defer br.mu.Unlock()
+
+	b, ok := br.hosts[normalizeHost(host)]
+	if !ok {
+		return false
+	}
+
+	reopened := b.state != CircuitClosed
+	b.state = CircuitClosed
+	b.failures = 0
+	b.trial = false
+	b.lastError = ""
+	return reopened
+}
+
+// RecordFailure counts a connection failure; it reports whether the breaker just opened
+func (br *BreakerRegistry) RecordFailure(host, reason string) bool {
+	if br.config.Threshold <= 0 {
+		return false
+	}
+
+	br.mu.Lock()
+	defer br.mu.Unlock()
+
+	b := br.breaker(normalizeHost(host))
+	b.failures++
+	b.lastError = reason
+
+	switch b.state {
+	case CircuitOpen:
+		b.nextProbe = time.Now().Add(br.config.ProbeInterval.Duration)
+		return false
+	case CircuitHalfOpen:
+		// The trial failed; wait for the next probe
+	default:
+		if b.failures < br.config.Threshold {
+			return false
+		}
+		b.openedAt = time.Now()
+	}
+
+	b.state = CircuitOpen
+	b.trial = false
+	b.nextProbe = time.Now().Add(br.config.ProbeInterval.Duration)
+	return true
+}
+
+// halfOpen lets one trial job through an open breaker
+func (br *BreakerRegistry) halfOpen(host string) {
+	br.mu.Lock()
+	defer br.mu.Unlock()
+
+	if b, ok := br.hosts[normalizeHost(host)]; ok && b.state == CircuitOpen {
+		b.state = CircuitHalfOpen
+		b.trial = false
+	}
+}
+
+// EndTrial gives up the half-open trial of both hosts without a result,
+// e.g. when the trial job was cancelled
+func (br *BreakerRegistry) EndTrial(sourceHost, destHost string) {
+	br.mu.Lock()
+	defer br.mu.Unlock()
+
+	for _, host := range []string{normalizeHost(sourceHost), normalizeHost(destHost)} {
+		if b, ok := br.hosts[host]; ok {
+			b.trial = false
+		}
+	}
+}
+
+// Reset closes the breaker of a host by hand
+func (br *BreakerRegistry) Reset(host string) {
+	br.mu.Lock()
+	defer br.mu.Unlock()
+
+	delete(br.hosts, normalizeHost(host))
+}
+
+// Status returns the breakers that are not closed or have recent failures
+func (br *BreakerRegistry) Status() []BreakerStatus {
+	br.mu.Lock()
+	defer br.mu.Unlock()
+
+	var statuses []BreakerStatus
+	for host, b := range br.hosts {
+		if b.state == CircuitClosed && b.failures == 0 {
+			continue
+		}
+		statuses = append(statuses, BreakerStatus{
+			Host:      host,
+			State:     b.state,
+			Failures:  b.failures,
+			OpenedAt:  b.openedAt,
+			NextProbe: b.nextProbe,
+			LastError: b.lastError,
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Host < statuses[j].Host
+	})
+	return statuses
+}
+
+// SetCircuitBreaker replaces the circuit breaker settings.
+// It should be called before the first batch starts.
+func (ptm *ParallelTransferManager) SetCircuitBreaker(config *CircuitBreakerConfig) {
+	ptm.mu.Lock()
+	defer ptm.mu.Unlock()
+
+	ptm.breakers = NewBreakerRegistry(config)
+}
+
+// BreakerStatus returns the state of every host with an open breaker or recent failures
+func (ptm *ParallelTransferManager) BreakerStatus() []BreakerStatus {
+	return ptm.breakers.Status()
+}
+
+// ResetBreaker closes a host's breaker and releases its waiting jobs
+func (ptm *ParallelTransferManager) ResetBreaker(host string) {
+	ptm.breakers.Reset(host)
+	ptm.releaseWaitingJobs()
+}
+
+// breakerBlocks reports whether a job must wait for one of its hosts
+func (ptm *ParallelTransferManager) breakerBlocks(job *TransferJob) bool {
+	return ptm.breakers.Blocked(job.SourceHost) || ptm.breakers.Blocked(job.DestHost)
+}
+
+// recordConnectionResult feeds the outcome of an imapsync run to the breakers.
+// Any failure other than a connection failure proves the server is reachable.
+func (ptm *ParallelTransferManager) recordConnectionResult(job *TransferJob, err error) {
+	var te *TransferError
+	if err == nil || !errors.As(err, &te) || !isConnectionFailure(te.Reason) {
+		closedSrc := ptm.breakers.RecordSuccess(job.SourceHost)
+		closedDst := ptm.breakers.RecordSuccess(job.DestHost)
+		if closedSrc || closedDst {
+			ptm.logger.WithJob(job).Info("Server recovered, resuming waiting jobs")
+			ptm.releaseWaitingJobs()
+		}
+		return
+	}
+
+	var hosts []string
+	switch te.Host {
+	case 1:
+		hosts = []string{job.SourceHost}
+	case 2:
+		hosts = []string{job.DestHost}
+	default:
+		hosts = []string{job.SourceHost, job.DestHost}
+	}
+
+	for _, host := range hosts {
+		if ptm.breakers.RecordFailure(host, te.Error()) {
+			ptm.logger.WithField("host", host).Warn("Circuit breaker opened for %s after repeated connection failures", host)
+			ptm.scheduleProbe(host)
+		}
+	}
+}
+
+// releaseWaitingJobs moves waiting jobs whose servers are available and whose
+// retry delay has passed back to pending, and wakes the workers
+func (ptm *ParallelTransferManager) releaseWaitingJobs() {
+	ptm.mu.Lock()
+	for _, job := range ptm.jobs {
+		if job.Status == StatusWaiting && !job.retryDelayed() && !ptm.breakerBlocks(job) {
+			job.Status = StatusPending
+			job.Error = nil
+		}
+	}
+	ptm.mu.Unlock()
+
+	ptm.qmu.Lock()
+	ptm.qcond.Broadcast()
+	ptm.qmu.Unlock()
+}
+
+// scheduleProbe checks an open breaker's host after the probe interval
+func (ptm *ParallelTransferManager) scheduleProbe(host string) {
+	time.AfterFunc(ptm.breakers.config.ProbeInterval.Duration, func() {
+		ptm.probeHost(host)
+	})
+}
+
+// probeHost runs imapsync --justlogin with the credentials of a waiting job.
+// Without such a job the breaker goes half-open and the next job is the trial.
+func (ptm *ParallelTransferManager) probeHost(host string) {
+	if ptm.ctx.Err() != nil {
+		return
+	}
+
+	target := normalizeHost(host)
+	var probe *TransferJob
+	ptm.mu.RLock()
+	for _, job := range ptm.jobs {
+		if job.Status == StatusWaiting &&
+			(normalizeHost(job.SourceHost) == target || normalizeHost(job.DestHost) == target) {
+			probe = job
+			break
+		}
+	}
+	ptm.mu.RUnlock()
+
+	if probe == nil {
+		ptm.breakers.halfOpen(host)
+		ptm.releaseWaitingJobs()
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ptm.ctx, 2*time.Minute)
+	defer cancel()
+
+	err := checkLogins(ctx, probe)
+
+	ptm.logger.WithField("host", host).Info("Probed %s: %v", host, err)
+	ptm.recordConnectionResult(probe, err)
+
+	if ptm.breakers.Blocked(host) {
+		ptm.scheduleProbe(host)
+	}
+}
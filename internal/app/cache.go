@@ -1,14 +1,45 @@
 package app
 
 import (
+	"container/list"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
 	"sync"
 	"time"
 )
 
-// CacheItem represents a cached item with expiration
+// CacheConfig holds cache size and persistence settings
+type CacheConfig struct {
+	MaxEntries int    `json:"max_entries"` // Least recently used entries are evicted beyond this; 0 means unbounded
+	Persist    bool   `json:"persist"`     // Keep the cache across restarts
+	Path       string `json:"path"`        // Cache file; empty means the user's cache directory
+}
+
+// DefaultCacheConfig returns default cache settings
+func DefaultCacheConfig() CacheConfig {
+	return CacheConfig{
+		MaxEntries: 1000,
+		Persist:    true,
+	}
+}
+
+// DefaultCachePath returns the default location of the cache file
+func DefaultCachePath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "imapsync-cache.json"
+	}
+	return filepath.Join(dir, "imapsync", "cache.json")
+}
+
+// CacheItem represents a cached item with expiration.
+// Values are stored JSON encoded so the cache can be written to disk.
 type CacheItem struct {
-	Value      interface{}
-	Expiration time.Time
+	Key        string          `json:"key"`
+	Value      json.RawMessage `json:"value"`
+	Expiration time.Time       `json:"expiration"`
 }
 
 // IsExpired checks if the cache item has expired
@@ -16,50 +47,134 @@ func (ci *CacheItem) IsExpired() bool {
 	return time.Now().After(ci.Expiration)
 }
 
-// Cache provides a simple in-memory cache with expiration
+// CacheStats holds cache metrics
+type CacheStats struct {
+	Entries    int
+	MaxEntries int
+	Hits       int64
+	Misses     int64
+	Evictions  int64 // Entries dropped to stay within MaxEntries
+	Expired    int64 // Entries dropped because they expired
+}
+
+// HitRate returns the share of lookups that found an entry, in percent
+func (s CacheStats) HitRate() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Misses) * 100
+}
+
+// Cache is an in-memory LRU cache with expiration. A janitor goroutine
+// removes expired items and, when persistence is enabled, saves the cache.
 type Cache struct {
-	items map[string]*CacheItem
-	mu    sync.RWMutex
+	items map[string]*list.Element
+	order list.List // Front is the most recently used item
+	mu    sync.Mutex
+
+	maxEntries int
+	path       string // Cache file, empty when not persisted
+	dirty      bool
+	stats      CacheStats
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewCache creates a new cache. A positive cleanupInterval starts the janitor.
+func NewCache(cleanupInterval time.Duration) *Cache {
+	c := &Cache{
+		items: make(map[string]*list.Element),
+	}
+
+	if cleanupInterval > 0 {
+		c.stop = make(chan struct{})
+		c.done = make(chan struct{})
+		go c.janitor(cleanupInterval, c.stop, c.done)
+	}
+	return c
+}
+
+// janitor removes expired items and saves the cache until stop is closed
+func (c *Cache) janitor(interval time.Duration, stop, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			c.Cleanup()
+			if err := c.Save(); err != nil {
+				NewLogger().WithField("component", "cache").Warn("Failed to save cache: %v", err)
+			}
+		}
+	}
 }
 
-// NewCache creates a new cache instance
-func NewCache() *Cache {
-	return &Cache{
-		items: make(map[string]*CacheItem),
+// Close stops the janitor and saves the cache
+func (c *Cache) Close() error {
+	if c.stop != nil {
+		close(c.stop)
+		<-c.done
+		c.stop = nil
 	}
+	return c.Save()
 }
 
-// Set stores a value in the cache with expiration
-func (c *Cache) Set(key string, value interface{}, expiration time.Duration) {
+// SetMaxEntries bounds the number of items, evicting the least recently used ones
+func (c *Cache) SetMaxEntries(n int) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	c.items[key] = &CacheItem{
+	c.maxEntries = n
+	c.evict()
+}
+
+// Set stores a JSON encoded value in the cache with expiration
+func (c *Cache) Set(key string, value json.RawMessage, expiration time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	item := &CacheItem{
+		Key:        key,
 		Value:      value,
 		Expiration: time.Now().Add(expiration),
 	}
+	if elem, exists := c.items[key]; exists {
+		elem.Value = item
+		c.order.MoveToFront(elem)
+	} else {
+		c.items[key] = c.order.PushFront(item)
+	}
+	c.dirty = true
+	c.evict()
 }
 
-// Get retrieves a value from the cache
-func (c *Cache) Get(key string) (interface{}, bool) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+// Get retrieves a JSON encoded value from the cache
+func (c *Cache) Get(key string) (json.RawMessage, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
-	item, exists := c.items[key]
+	elem, exists := c.items[key]
 	if !exists {
+		c.stats.Misses++
 		return nil, false
 	}
 
+	item := elem.Value.(*CacheItem)
 	if item.IsExpired() {
-		// Remove expired item
-		c.mu.RUnlock()
-		c.mu.Lock()
-		delete(c.items, key)
-		c.mu.Unlock()
-		c.mu.RLock()
+		c.remove(elem)
+		c.stats.Expired++
+		c.stats.Misses++
 		return nil, false
 	}
 
+	c.order.MoveToFront(elem)
+	c.stats.Hits++
 	return item.Value, true
 }
 
@@ -67,31 +182,156 @@ func (c *Cache) Get(key string) (interface{}, bool) {
 func (c *Cache) Delete(key string) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	delete(c.items, key)
+
+	if elem, exists := c.items[key]; exists {
+		c.remove(elem)
+	}
 }
 
 // Flush removes all items from the cache
 func (c *Cache) Flush() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	c.items = make(map[string]*CacheItem)
+
+	c.items = make(map[string]*list.Element)
+	c.order.Init()
+	c.dirty = true
 }
 
 // ItemCount returns the number of items in the cache
 func (c *Cache) ItemCount() int {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	return len(c.items)
 }
 
+// Stats returns the cache metrics
+func (c *Cache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.stats
+	stats.Entries = len(c.items)
+	stats.MaxEntries = c.maxEntries
+	return stats
+}
+
 // Cleanup removes expired items from the cache
 func (c *Cache) Cleanup() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	for key, item := range c.items {
+	for _, elem := range c.items {
+		if elem.Value.(*CacheItem).IsExpired() {
+			c.remove(elem)
+			c.stats.Expired++
+		}
+	}
+}
+
+// remove deletes an element. Callers must hold c.mu.
+func (c *Cache) remove(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.items, elem.Value.(*CacheItem).Key)
+	c.dirty = true
+}
+
+// evict drops the least recently used items beyond maxEntries. Callers must hold c.mu.
+func (c *Cache) evict() {
+	for c.maxEntries > 0 && len(c.items) > c.maxEntries {
+		c.remove(c.order.Back())
+		c.stats.Evictions++
+	}
+}
+
+// Load enables persistence to path and reads the items saved there.
+// A missing file is not an error.
+func (c *Cache) Load(path string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.path = path
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read cache: %w", err)
+	}
+
+	var items []*CacheItem
+	if err := json.Unmarshal(data, &items); err != nil {
+		return fmt.Errorf("parse cache %s: %w", path, err)
+	}
+
+	// The file lists the most recently used item first
+	for i := len(items) - 1; i >= 0; i-- {
+		item := items[i]
 		if item.IsExpired() {
-			delete(c.items, key)
+			continue
 		}
+		if elem, exists := c.items[item.Key]; exists {
+			c.order.Remove(elem)
+		}
+		c.items[item.Key] = c.order.PushFront(item)
+	}
+	c.evict()
+	return nil
+}
+
+// Save writes the cache to its file if persistence is enabled and it changed
+func (c *Cache) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.path == "" || !c.dirty {
+		return nil
+	}
+
+	items := make([]*CacheItem, 0, len(c.items))
+	for elem := c.order.Front(); elem != nil; elem = elem.Next() {
+		items = append(items, elem.Value.(*CacheItem))
+	}
+	data, err := json.Marshal(items)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0700); err != nil {
+		return fmt.Errorf("create cache directory: %w", err)
+	}
+	// Write a temporary file first so a crash never leaves a truncated cache
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("write cache: %w", err)
+	}
+	if err := os.Rename(tmp, c.path); err != nil {
+		return fmt.Errorf("write cache: %w", err)
+	}
+
+	c.dirty = false
+	return nil
+}
+
+// CacheGet returns a cached value decoded as T
+func CacheGet[T any](c *Cache, key string) (T, bool) {
+	var value T
+	data, found := c.Get(key)
+	if !found {
+		return value, false
+	}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return value, false
+	}
+	return value, true
+}
+
+// CacheSet stores a value of type T in the cache
+func CacheSet[T any](c *Cache, key string, value T, expiration time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
 	}
+	c.Set(key, data, expiration)
+	return nil
 }
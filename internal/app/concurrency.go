@@ -0,0 +1,269 @@
+package app
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// AdaptiveConcurrencyConfig holds settings for the adaptive concurrency controller
+type AdaptiveConcurrencyConfig struct {
+	Enabled        bool     `json:"enabled"`
+	Min            int      `json:"min"`             // Lower bound for concurrent transfers
+	Max            int      `json:"max"`             // Upper bound for concurrent transfers
+	Interval       Duration `json:"interval"`        // How often the limit is re-evaluated
+	IncreaseStep   int      `json:"increase_step"`   // Additive increase while all slots are busy
+	DecreaseFactor float64  `json:"decrease_factor"` // Multiplicative decrease on throttling, e.g. 0.5
+	LatencyFactor  float64  `json:"latency_factor"`  // Back off when message latency exceeds the best seen by this factor
+}
+
+// DefaultAdaptiveConcurrencyConfig returns default controller settings
+func DefaultAdaptiveConcurrencyConfig() AdaptiveConcurrencyConfig {
+	return AdaptiveConcurrencyConfig{
+		Min:            1,
+		Max:            10,
+		Interval:       Duration{30 * time.Second},
+		IncreaseStep:   1,
+		DecreaseFactor: 0.5,
+		LatencyFactor:  2.0,
+	}
+}
+
+// Reasons recorded with concurrency decisions, one per branch of evaluate
+const (
+	ReasonThrottled      = "throttling errors"                 // Decrease: the server pushed back
+	ReasonLatency        = "latency grew"                      // Decrease: time per message exceeded LatencyFactor
+	ReasonThroughputDrop = "throughput dropped after increase" // Undo of the last increase
+	ReasonSaturated      = "all slots busy"                    // Increase: every slot in use and throughput held
+)
+
+// ConcurrencyDecision records one change made by the controller
+type ConcurrencyDecision struct {
+	Time       time.Time
+	From       int
+	To         int
+	Reason     string
+	Throughput float64       // Bytes per second during the interval
+	Latency    time.Duration // Average time per copied message
+	Throttled  int           // Throttling errors seen during the interval
+}
+
+// String formats the decision for display
+func (d ConcurrencyDecision) String() string {
+	return fmt.Sprintf("%s %d -> %d (%s, %.1f KB/s, %s/msg, %d throttled)",
+		d.Time.Format("15:04:05"), d.From, d.To, d.Reason,
+		d.Throughput/1024, d.Latency.Round(time.Millisecond), d.Throttled)
+}
+
+// copiedRe matches a copied message line, e.g. "msg INBOX/12 {5321}  copied to INBOX/40"
+var copiedRe = regexp.MustCompile(`msg\s+\S.*?\{(\d+)\}\s+copied to`)
+
+// ConcurrencyController resizes the transfer semaphore using additive increase,
+// multiplicative decrease: it grows while throughput improves and backs off on
+// throttling errors or rising latency.
+type ConcurrencyController struct {
+	pm     *PerformanceManager
+	config *AdaptiveConcurrencyConfig
+	logger *Logger
+
+	mu             sync.Mutex
+	bytes          int64
+	messages       int64
+	latency        time.Duration
+	throttled      int
+	lastThroughput float64
+	lastIncreased  bool
+	bestLatency    time.Duration
+	stop           chan struct{}
+	done           chan struct{}
+}
+
+// NewConcurrencyController creates a controller and clamps the current limit to its bounds
+func NewConcurrencyController(pm *PerformanceManager, config *AdaptiveConcurrencyConfig) *ConcurrencyController {
+	defaults := DefaultAdaptiveConcurrencyConfig()
+	if config == nil {
+		config = &defaults
+	}
+	if config.Min <= 0 {
+		config.Min = defaults.Min
+	}
+	if config.Max < config.Min {
+		config.Max = config.Min
+	}
+	if config.Interval.Duration <= 0 {
+		config.Interval = defaults.Interval
+	}
+	if config.IncreaseStep <= 0 {
+		config.IncreaseStep = defaults.IncreaseStep
+	}
+	if config.DecreaseFactor <= 0 || config.DecreaseFactor >= 1 {
+		config.DecreaseFactor = defaults.DecreaseFactor
+	}
+	if config.LatencyFactor <= 1 {
+		config.LatencyFactor = defaults.LatencyFactor
+	}
+
+	cc := &ConcurrencyController{
+		pm:     pm,
+		config: config,
+		logger: NewLogger().WithField("component", "concurrency"),
+	}
+
+	limit := pm.ConcurrencyLimit()
+	if clamped := cc.clamp(limit); clamped != limit {
+		pm.SetConcurrencyLimit(clamped)
+	}
+	return cc
+}
+
+// clamp keeps a limit within the configured bounds
+func (cc *ConcurrencyController) clamp(limit int) int {
+	if limit < cc.config.Min {
+		return cc.config.Min
+	}
+	if limit > cc.config.Max {
+		return cc.config.Max
+	}
+	return limit
+}
+
+// Start begins evaluating the limit in the background
+func (cc *ConcurrencyController) Start() {
+	cc.mu.Lock()
+	if cc.stop != nil {
+		cc.mu.Unlock()
+		return
+	}
+	cc.stop = make(chan struct{})
+	cc.done = make(chan struct{})
+	stop, done := cc.stop, cc.done
+	cc.mu.Unlock()
+
+	go cc.run(stop, done)
+	cc.logger.Info("Adaptive concurrency enabled (%d-%d)", cc.config.Min, cc.config.Max)
+}
+
+// Stop stops the controller; the current limit stays in effect
+func (cc *ConcurrencyController) Stop() {
+	cc.mu.Lock()
+	stop, done := cc.stop, cc.done
+	cc.stop = nil
+	cc.mu.Unlock()
+
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+}
+
+// run evaluates the limit every interval until stopped
+func (cc *ConcurrencyController) run(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(cc.config.Interval.Duration)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			cc.evaluate(now)
+		}
+	}
+}
+
+// RecordMessage records a copied message and the time it took
+func (cc *ConcurrencyController) RecordMessage(size int64, latency time.Duration) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	cc.bytes += size
+	cc.messages++
+	cc.latency += latency
+}
+
+// RecordThrottle records a throttling or connection limit error
+func (cc *ConcurrencyController) RecordThrottle() {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	cc.throttled++
+}
+
+// ObserveLine feeds an imapsync output line to the controller
+func (cc *ConcurrencyController) ObserveLine(line string) {
+	if throttleRe.MatchString(line) {
+		cc.RecordThrottle()
+	}
+}
+
+// evaluate applies one AIMD step based on the signals collected since the last call
+func (cc *ConcurrencyController) evaluate(now time.Time) {
+	cc.mu.Lock()
+	bytes, messages, totalLatency, throttled := cc.bytes, cc.messages, cc.latency, cc.throttled
+	cc.bytes, cc.messages, cc.latency, cc.throttled = 0, 0, 0, 0
+
+	throughput := float64(bytes) / cc.config.Interval.Seconds()
+	var latency time.Duration
+	if messages > 0 {
+		latency = totalLatency / time.Duration(messages)
+		if cc.bestLatency == 0 || latency < cc.bestLatency {
+			cc.bestLatency = latency
+		}
+	}
+	bestLatency := cc.bestLatency
+	lastThroughput, lastIncreased := cc.lastThroughput, cc.lastIncreased
+	cc.lastThroughput = throughput
+	cc.lastIncreased = false
+	cc.mu.Unlock()
+
+	// Nothing ran during the interval; keep the current limit
+	if messages == 0 && throttled == 0 {
+		return
+	}
+
+	limit := cc.pm.ConcurrencyLimit()
+	next, reason := limit, ""
+
+	switch {
+	case throttled > 0:
+		next, reason = cc.decrease(limit), ReasonThrottled
+	case latency > 0 && bestLatency > 0 && float64(latency) > float64(bestLatency)*cc.config.LatencyFactor:
+		next, reason = cc.decrease(limit), ReasonLatency
+	case lastIncreased && throughput < lastThroughput*0.9:
+		// The last increase made things worse; undo it
+		next, reason = cc.clamp(limit-cc.config.IncreaseStep), ReasonThroughputDrop
+	case cc.pm.ConnectionsInUse() >= limit:
+		next, reason = cc.clamp(limit+cc.config.IncreaseStep), ReasonSaturated
+	}
+
+	if next == limit {
+		return
+	}
+
+	cc.pm.SetConcurrencyLimit(next)
+	cc.pm.recordConcurrencyDecision(ConcurrencyDecision{
+		Time:       now,
+		From:       limit,
+		To:         next,
+		Reason:     reason,
+		Throughput: throughput,
+		Latency:    latency,
+		Throttled:  throttled,
+	})
+
+	if next > limit {
+		cc.mu.Lock()
+		cc.lastIncreased = true
+		cc.mu.Unlock()
+	}
+	cc.logger.Info("Concurrency limit %d -> %d (%s)", limit, next, reason)
+}
+
+// decrease applies the multiplicative decrease
+func (cc *ConcurrencyController) decrease(limit int) int {
+	return cc.clamp(int(float64(limit) * cc.config.DecreaseFactor))
+}
@@ -0,0 +1,136 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"imapsync/internal/ui"
+)
+
+// Duration wraps time.Duration so it can be written as "30s" or "5m" in JSON
+type Duration struct {
+	time.Duration
+}
+
+// MarshalJSON encodes the duration as a string such as "1m30s"
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON accepts either a duration string or a number of seconds
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var raw interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	switch v := raw.(type) {
+	case string:
+		parsed, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q: %w", v, err)
+		}
+		d.Duration = parsed
+	case float64:
+		d.Duration = time.Duration(v * float64(time.Second))
+	default:
+		return fmt.Errorf("invalid duration %s", string(data))
+	}
+	return nil
+}
+
+// Config holds the settings loaded from the configuration file
+type Config struct {
+	Language            string                        `json:"language"` // Interface language, e.g. "en" or "tr"; empty follows the environment
+	Theme               ui.ThemeConfig                `json:"theme"`    // Colors and symbols; empty fields follow the terminal
+	Logging             LoggingConfig                 `json:"logging"`
+	Scheduler           SchedulerConfig               `json:"scheduler"`
+	HostLimits          HostLimitsConfig              `json:"host_limits"`
+	AdaptiveConcurrency AdaptiveConcurrencyConfig     `json:"adaptive_concurrency"`
+	Bandwidth           BandwidthConfig               `json:"bandwidth"`
+	Retry               map[FailureReason]RetryPolicy `json:"retry"`
+	CircuitBreaker      CircuitBreakerConfig          `json:"circuit_breaker"`
+	Cache               CacheConfig                   `json:"cache"`
+	Webhooks            []WebhookConfig               `json:"webhooks"`
+	SMTP                *SMTPConfig                   `json:"smtp"`
+}
+
+// DefaultConfig returns the default configuration with all optional features disabled
+func DefaultConfig() *Config {
+	return &Config{
+		Logging:             DefaultLoggingConfig(),
+		Scheduler:           DefaultSchedulerConfig(),
+		AdaptiveConcurrency: DefaultAdaptiveConcurrencyConfig(),
+		CircuitBreaker:      DefaultCircuitBreakerConfig(),
+		Cache:               DefaultCacheConfig(),
+	}
+}
+
+// DefaultConfigPath returns the default location of the configuration file
+func DefaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "imapsync.json"
+	}
+	return filepath.Join(dir, "imapsync", "config.json")
+}
+
+// LoadConfig reads the configuration file at path.
+// A missing file is not an error; the default configuration is returned instead.
+func LoadConfig(path string) (*Config, error) {
+	cfg := DefaultConfig()
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+var (
+	configMu     sync.RWMutex
+	activeConfig = DefaultConfig()
+)
+
+// SetConfig replaces the configuration used by the interactive modes
+func SetConfig(cfg *Config) {
+	if cfg == nil {
+		cfg = DefaultConfig()
+	}
+
+	configMu.Lock()
+	defer configMu.Unlock()
+	activeConfig = cfg
+}
+
+// CurrentConfig returns the configuration used by the interactive modes
+func CurrentConfig() *Config {
+	configMu.RLock()
+	defer configMu.RUnlock()
+	return activeConfig
+}
+
+// Notifiers builds the notifiers enabled in the configuration
+func (c *Config) Notifiers() []Notifier {
+	var notifiers []Notifier
+	for i := range c.Webhooks {
+		notifiers = append(notifiers, NewWebhookNotifier(&c.Webhooks[i]))
+	}
+	if c.SMTP != nil && c.SMTP.Host != "" {
+		notifiers = append(notifiers, NewSMTPNotifier(c.SMTP))
+	}
+	return notifiers
+}
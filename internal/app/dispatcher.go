@@ -0,0 +1,314 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+	"time"
+)
+
+// ErrShuttingDown is returned when a job is submitted after Shutdown
+var ErrShuttingDown = errors.New("transfer manager is shutting down")
+
+// ErrAdmissionClosed is returned when a batch is started outside a maintenance window
+var ErrAdmissionClosed = errors.New("job admission is closed until the next maintenance window")
+
+// DefaultShutdownTimeout is how long Close waits for running jobs before cancelling them
+const DefaultShutdownTimeout = 30 * time.Second
+
+// queueItem is a job waiting for a worker
+type queueItem struct {
+	job      *TransferJob
+	priority int
+	order    uint64
+	batch    *jobBatch
+}
+
+// jobQueue is a bounded priority queue. Higher priority goes first;
+// equal priorities keep the order in which jobs were added.
+type jobQueue struct {
+	items    []*queueItem
+	capacity int
+}
+
+// newJobQueue creates a queue holding at most capacity items
+func newJobQueue(capacity int) *jobQueue {
+	return &jobQueue{capacity: capacity}
+}
+
+// full reports whether the queue has reached its capacity
+func (q *jobQueue) full() bool {
+	return q.capacity > 0 && len(q.items) >= q.capacity
+}
+
+// push inserts an item at its priority position
+func (q *jobQueue) push(item *queueItem) {
+	i := sort.Search(len(q.items), func(i int) bool {
+		other := q.items[i]
+		if other.priority != item.priority {
+			return other.priority < item.priority
+		}
+		return other.order > item.order
+	})
+
+	q.items = append(q.items, nil)
+	copy(q.items[i+1:], q.items[i:])
+	q.items[i] = item
+}
+
+// popFirst removes and returns the highest priority item accepted by take.
+// Items that take rejects stay queued in order.
+func (q *jobQueue) popFirst(take func(*queueItem) bool) *queueItem {
+	for i, item := range q.items {
+		if take(item) {
+			copy(q.items[i:], q.items[i+1:])
+			q.items[len(q.items)-1] = nil
+			q.items = q.items[:len(q.items)-1]
+			return item
+		}
+	}
+	return nil
+}
+
+// drain removes and returns every queued item
+func (q *jobQueue) drain() []*queueItem {
+	items := q.items
+	q.items = nil
+	return items
+}
+
+// jobBatch tracks the jobs of one StartAllJobs call, including jobs added while it runs
+type jobBatch struct {
+	pending int
+	jobs    []*TransferJob
+	members map[string]bool // Jobs queued or running in the batch
+	done    chan struct{}
+}
+
+// newJobBatch creates a batch counting the given jobs as pending
+func newJobBatch(jobs []*TransferJob) *jobBatch {
+	batch := &jobBatch{
+		pending: len(jobs),
+		jobs:    append([]*TransferJob(nil), jobs...),
+		members: make(map[string]bool, len(jobs)),
+		done:    make(chan struct{}),
+	}
+	for _, job := range jobs {
+		batch.members[job.ID] = true
+	}
+	return batch
+}
+
+// startWorkers launches the fixed worker pool once
+func (ptm *ParallelTransferManager) startWorkers() {
+	ptm.qmu.Lock()
+	defer ptm.qmu.Unlock()
+
+	if ptm.workersStarted || ptm.shuttingDown {
+		return
+	}
+	ptm.workersStarted = true
+
+	workers := ptm.perfManager.config.WorkerPoolSize
+	if workers <= 0 {
+		workers = ptm.perfManager.config.MaxConcurrentTransfers
+	}
+	if workers <= 0 {
+		workers = 1
+	}
+	// The adaptive controller may raise the limit; keep enough workers to use it
+	ptm.mu.RLock()
+	controller := ptm.controller
+	ptm.mu.RUnlock()
+	if controller != nil && controller.config.Max > workers {
+		workers = controller.config.Max
+	}
+
+	ptm.workerWg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go ptm.worker()
+	}
+	ptm.logger.Info("Started %d transfer workers (queue depth %d)", workers, ptm.queue.capacity)
+}
+
+// worker runs queued jobs until the manager shuts down.
+// Jobs whose hosts are at their limit are skipped so other hosts keep workers busy.
+func (ptm *ParallelTransferManager) worker() {
+	defer ptm.workerWg.Done()
+
+	for {
+		ptm.qmu.Lock()
+		item := ptm.queue.popFirst(ptm.reserveHosts)
+		for item == nil && !ptm.shuttingDown {
+			ptm.qcond.Wait()
+			item = ptm.queue.popFirst(ptm.reserveHosts)
+		}
+		if item == nil {
+			ptm.qmu.Unlock()
+			return
+		}
+		// Wake producers blocked on a full queue
+		ptm.qcond.Broadcast()
+		ptm.qmu.Unlock()
+
+		ptm.executeJob(item.job)
+
+		ptm.mu.RLock()
+		held := item.job.Status == StatusWaiting || item.job.Status == StatusPending
+		ptm.mu.RUnlock()
+
+		ptm.qmu.Lock()
+		ptm.hostLimiter.Release(item.job.SourceHost, item.job.DestHost)
+		if held && !ptm.shuttingDown {
+			// The server went down or asked to retry later, or admission closed
+			// before the job started; hold the job until it can start
+			ptm.queue.push(item)
+		} else {
+			// A requeued or resumed job may join the batch again
+			if item.batch != nil {
+				delete(item.batch.members, item.job.ID)
+			}
+			ptm.finishQueued(item.batch)
+		}
+		// Host slots were freed; waiting workers may now take a skipped job
+		ptm.qcond.Broadcast()
+		ptm.qmu.Unlock()
+	}
+}
+
+// reserveHosts takes host slots for a queued job. Jobs are skipped while
+// admission is closed, their dependencies have not completed yet or their
+// retry delay has not passed, and jobs for a host with an open circuit breaker
+// are marked waiting and skipped. Callers must hold ptm.qmu.
+func (ptm *ParallelTransferManager) reserveHosts(item *queueItem) bool {
+	job := item.job
+
+	ptm.mu.Lock()
+	if job.Status == StatusPending || job.Status == StatusWaiting {
+		ready, err := ptm.dependenciesReady(job, item.batch)
+		if err != nil {
+			// The job can never run; a worker takes it off the queue
+			ptm.mu.Unlock()
+			ptm.updateJobStatus(job, StatusFailed, err)
+			return ptm.hostLimiter.TryAcquire(job.SourceHost, job.DestHost)
+		}
+		if !ready || job.retryDelayed() || !ptm.admitting {
+			ptm.mu.Unlock()
+			return false
+		}
+
+		if ptm.breakerBlocks(job) {
+			if job.Status != StatusWaiting {
+				job.Status = StatusWaiting
+				ptm.logger.WithJob(job).Info("Job %s status: %s", job.ID, StatusWaiting)
+			}
+			ptm.mu.Unlock()
+			return false
+		}
+		job.Status = StatusPending
+	}
+	ptm.mu.Unlock()
+
+	return ptm.hostLimiter.TryAcquire(job.SourceHost, job.DestHost)
+}
+
+// enqueue adds a job to the queue, blocking while the queue is full.
+// The batch must already count the job as pending.
+func (ptm *ParallelTransferManager) enqueue(job *TransferJob, batch *jobBatch) error {
+	ptm.mu.RLock()
+	priority, order := job.Priority, job.order
+	ptm.mu.RUnlock()
+
+	ptm.qmu.Lock()
+	defer ptm.qmu.Unlock()
+
+	for ptm.queue.full() && !ptm.shuttingDown {
+		ptm.qcond.Wait()
+	}
+	if ptm.shuttingDown {
+		ptm.finishQueued(batch)
+		return ErrShuttingDown
+	}
+
+	ptm.queue.push(&queueItem{
+		job:      job,
+		priority: priority,
+		order:    order,
+		batch:    batch,
+	})
+	ptm.qcond.Broadcast()
+	return nil
+}
+
+// submitToRunningBatch queues a job into the batch that is currently running, if any
+func (ptm *ParallelTransferManager) submitToRunningBatch(job *TransferJob) {
+	ptm.qmu.Lock()
+	batch := ptm.batch
+	if batch == nil || ptm.shuttingDown || batch.members[job.ID] {
+		ptm.qmu.Unlock()
+		return
+	}
+	batch.pending++
+	if !slices.Contains(batch.jobs, job) {
+		batch.jobs = append(batch.jobs, job)
+	}
+	batch.members[job.ID] = true
+	ptm.qmu.Unlock()
+
+	if err := ptm.enqueue(job, batch); err != nil {
+		ptm.logger.WithJob(job).Warn("Job %s not queued: %v", job.ID, err)
+	}
+}
+
+// finishQueued marks a job of the batch as done. Callers must hold ptm.qmu.
+func (ptm *ParallelTransferManager) finishQueued(batch *jobBatch) {
+	if batch == nil {
+		return
+	}
+	batch.pending--
+	if batch.pending == 0 {
+		close(batch.done)
+		if ptm.batch == batch {
+			ptm.batch = nil
+		}
+	}
+}
+
+// Shutdown stops workers from taking new jobs and waits for running jobs to finish.
+// Queued jobs stay pending. If ctx expires first, running jobs are cancelled.
+func (ptm *ParallelTransferManager) Shutdown(ctx context.Context) {
+	ptm.qmu.Lock()
+	if ptm.shuttingDown {
+		ptm.qmu.Unlock()
+		ptm.workerWg.Wait()
+		return
+	}
+	ptm.shuttingDown = true
+	for _, item := range ptm.queue.drain() {
+		ptm.finishQueued(item.batch)
+	}
+	ptm.qcond.Broadcast()
+	ptm.qmu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		ptm.workerWg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		ptm.logger.Warn("Shutdown timed out, cancelling running jobs")
+		ptm.CancelAllJobs()
+		<-done
+	}
+}
+
+// QueueLength returns the number of jobs waiting for a worker
+func (ptm *ParallelTransferManager) QueueLength() int {
+	ptm.qmu.Lock()
+	defer ptm.qmu.Unlock()
+	return len(ptm.queue.items)
+}
@@ -0,0 +1,270 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"os/exec"
+	"regexp"
+	"time"
+)
+
+// FailureReason classifies why a transfer failed
+type FailureReason string
+
+const (
+	FailureNone        FailureReason = ""
+	FailureAuth        FailureReason = "auth"        // Wrong credentials; retrying locks accounts
+	FailureUnreachable FailureReason = "unreachable" // DNS, refused or timed out connections
+	FailureTLS         FailureReason = "tls"         // Handshake or certificate errors
+	FailureQuota       FailureReason = "quota"       // Destination mailbox is full
+	FailureThrottled   FailureReason = "throttled"   // Server rate limiting or too many connections
+	FailurePartial     FailureReason = "partial"     // Some messages or folders failed
+	FailureUnknown     FailureReason = "unknown"
+)
+
+// failureReasons lists the failure classes, for choosing one in a form
+func failureReasons() []string {
+	return []string{
+		string(FailureAuth), string(FailureUnreachable), string(FailureTLS), string(FailureQuota),
+		string(FailureThrottled), string(FailurePartial), string(FailureUnknown),
+	}
+}
+
+// imapsync exit codes, see EXIT_* in the imapsync source
+const (
+	exitConnectionFailure      = 10
+	exitConnectionFailureHost1 = 101
+	exitConnectionFailureHost2 = 102
+	exitTLSFailure             = 12
+	exitAuthFailure            = 16
+	exitAuthFailureUser1       = 161
+	exitAuthFailureUser2       = 162
+	exitWithErrors             = 111
+	exitWithErrorsMax          = 112
+	exitOverQuota              = 113
+	exitErrAppend              = 114
+	exitErrFlags               = 120
+	exitTransferExceeded       = 118
+)
+
+// TransferError is a classified failure of imapsync or an archive export
+type TransferError struct {
+	Reason   FailureReason
+	ExitCode int    // imapsync exit code, -1 if it did not exit normally
+	Host     int    // 1 for the source, 2 for the destination, 0 if unknown
+	Detail   string // Output line that identified the failure, if any
+	Err      error
+	Op       string // What failed, e.g. "archive"; empty means imapsync
+}
+
+// Error implements the error interface
+func (e *TransferError) Error() string {
+	op := e.Op
+	if op == "" {
+		op = "imapsync"
+	}
+	msg := fmt.Sprintf("%s failed (%s", op, e.Reason)
+	if e.ExitCode >= 0 {
+		msg += fmt.Sprintf(", exit code %d", e.ExitCode)
+	}
+	msg += ")"
+	if e.Detail != "" {
+		msg += ": " + e.Detail
+	} else if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+// Unwrap returns the underlying error
+func (e *TransferError) Unwrap() error {
+	return e.Err
+}
+
+var (
+	// throttleRe matches server responses that signal too many connections or rate limiting
+	throttleRe = regexp.MustCompile(`(?i)too many (simultaneous |concurrent )?connections|connection limit|` +
+		`throttl|rate limit|server unavailable\. 15|\[UNAVAILABLE\]|\[LIMIT\]|try again later`)
+
+	// failurePatterns identify a failure from imapsync output when the exit code is not specific
+	failurePatterns = []struct {
+		reason FailureReason
+		re     *regexp.Regexp
+	}{
+		{FailureAuth, regexp.MustCompile(`(?i)authentication failed|login failed|AUTHENTICATIONFAILED|invalid credentials|LOGIN.*NO `)},
+		{FailureTLS, regexp.MustCompile(`(?i)SSL connect attempt failed|certificate verify failed|TLS handshake|SSL routines|STARTTLS failed`)},
+		{FailureQuota, regexp.MustCompile(`(?i)OVERQUOTA|quota exceeded|over quota|mailbox is full`)},
+		{FailureUnreachable, regexp.MustCompile(`(?i)can not open imap connection|connection refused|no route to host|name or service not known|network is unreachable|timed out`)},
+	}
+)
+
+// hostRe finds which side a failure line refers to, e.g. "Host2 failure: ..."
+var hostRe = regexp.MustCompile(`\bHost([12])\b`)
+
+// failureDetector collects failure hints from imapsync output lines
+type failureDetector struct {
+	throttled string
+	matched   map[FailureReason]string
+}
+
+// Observe records a line that looks like a failure
+func (fd *failureDetector) Observe(line string) {
+	if fd.throttled == "" && throttleRe.MatchString(line) {
+		fd.throttled = line
+	}
+	for _, p := range failurePatterns {
+		if _, seen := fd.matched[p.reason]; !seen && p.re.MatchString(line) {
+			if fd.matched == nil {
+				fd.matched = make(map[FailureReason]string)
+			}
+			fd.matched[p.reason] = line
+		}
+	}
+}
+
+// Classify turns a failed imapsync run into a TransferError
+func (fd *failureDetector) Classify(err error) *TransferError {
+	te := &TransferError{Reason: FailureUnknown, ExitCode: -1, Err: err}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		te.ExitCode = exitErr.ExitCode()
+		te.Reason = classifyExitCode(te.ExitCode)
+	}
+
+	fd.explain(te)
+	te.Host = failureHost(te.ExitCode, te.Detail)
+	return te
+}
+
+// explain refines the reason using the output and records the line that explains it
+func (fd *failureDetector) explain(te *TransferError) {
+	// Throttling often surfaces as a connection or partial failure
+	if fd.throttled != "" && te.Reason != FailureAuth {
+		te.Reason, te.Detail = FailureThrottled, fd.throttled
+		return
+	}
+
+	if detail, ok := fd.matched[te.Reason]; ok {
+		te.Detail = detail
+		return
+	}
+	if te.Reason == FailureUnknown {
+		for _, p := range failurePatterns {
+			if detail, ok := fd.matched[p.reason]; ok {
+				te.Reason, te.Detail = p.reason, detail
+				return
+			}
+		}
+	}
+}
+
+// classifyExitCode maps an imapsync exit code to a failure reason
+func classifyExitCode(code int) FailureReason {
+	switch {
+	case code == exitAuthFailure || code == exitAuthFailureUser1 || code == exitAuthFailureUser2:
+		return FailureAuth
+	case code == exitConnectionFailure || code == exitConnectionFailureHost1 || code == exitConnectionFailureHost2:
+		return FailureUnreachable
+	case code == exitTLSFailure:
+		return FailureTLS
+	case code == exitOverQuota:
+		return FailureQuota
+	case code == exitTransferExceeded:
+		return FailureThrottled
+	case code == exitWithErrors || code == exitWithErrorsMax || (code >= exitErrAppend && code <= exitErrFlags):
+		return FailurePartial
+	default:
+		return FailureUnknown
+	}
+}
+
+// failureHost determines which host a failure belongs to
+func failureHost(exitCode int, detail string) int {
+	switch exitCode {
+	case exitConnectionFailureHost1, exitAuthFailureUser1:
+		return 1
+	case exitConnectionFailureHost2, exitAuthFailureUser2:
+		return 2
+	}
+	if m := hostRe.FindStringSubmatch(detail); len(m) == 2 {
+		return int(m[1][0] - '0')
+	}
+	return 0
+}
+
+// isConnectionFailure reports whether an error means a server could not be reached
+func isConnectionFailure(reason FailureReason) bool {
+	return reason == FailureUnreachable || reason == FailureTLS
+}
+
+// ClassifyError returns the failure reason of an error
+func ClassifyError(err error) FailureReason {
+	if err == nil {
+		return FailureNone
+	}
+
+	var te *TransferError
+	if errors.As(err, &te) {
+		return te.Reason
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return classifyExitCode(exitErr.ExitCode())
+	}
+	return FailureUnknown
+}
+
+// RetryStrategy selects how a failure class is retried
+type RetryStrategy string
+
+const (
+	RetryNone        RetryStrategy = "none"        // Fail immediately
+	RetryExponential RetryStrategy = "exponential" // Exponential backoff with jitter
+	RetryDelayed     RetryStrategy = "delay"       // Wait a fixed delay, e.g. for throttling windows
+)
+
+// RetryPolicy describes how one failure class is retried
+type RetryPolicy struct {
+	Strategy    RetryStrategy `json:"strategy"`
+	MaxAttempts int           `json:"max_attempts"` // Including the first; 0 means RetryAttempts
+	Delay       Duration      `json:"delay"`        // Base delay; 0 means RetryDelay
+	MaxDelay    Duration      `json:"max_delay"`    // Upper bound for exponential backoff
+}
+
+// DefaultRetryPolicies returns the retry policy for each failure class
+func DefaultRetryPolicies() map[FailureReason]RetryPolicy {
+	return map[FailureReason]RetryPolicy{
+		FailureAuth:        {Strategy: RetryNone},
+		FailureTLS:         {Strategy: RetryNone},
+		FailureQuota:       {Strategy: RetryNone},
+		FailureUnreachable: {Strategy: RetryExponential, MaxDelay: Duration{5 * time.Minute}},
+		FailurePartial:     {Strategy: RetryExponential, MaxDelay: Duration{5 * time.Minute}},
+		FailureUnknown:     {Strategy: RetryExponential, MaxDelay: Duration{5 * time.Minute}},
+		FailureThrottled:   {Strategy: RetryDelayed, MaxAttempts: 4, Delay: Duration{10 * time.Minute}},
+	}
+}
+
+// backoff returns how long to wait after the given failed attempt (1-based)
+func (p RetryPolicy) backoff(attempt int, baseDelay time.Duration) time.Duration {
+	base := p.Delay.Duration
+	if base <= 0 {
+		base = baseDelay
+	}
+
+	if p.Strategy != RetryExponential {
+		return base
+	}
+
+	delay := base << uint(attempt-1)
+	if max := p.MaxDelay.Duration; max > 0 && (delay > max || delay <= 0) {
+		delay = max
+	}
+	// Equal jitter: half fixed, half random, so parallel jobs do not retry in lockstep
+	half := delay / 2
+	if half <= 0 {
+		return delay
+	}
+	return half + rand.N(half)
+}
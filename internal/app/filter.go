@@ -0,0 +1,312 @@
+package app
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// MessageFilter selects the messages a job copies. The zero value copies all
+// of them. Filters are written as space separated terms, e.g.
+// "max-age=730 max-size=25MB unseen header='^From:.*@example\.com'".
+type MessageFilter struct {
+	MinAge         int       // Only messages older than this many days
+	MaxAge         int       // Only messages newer than this many days
+	Since          time.Time // Only messages sent on or after this day
+	Before         time.Time // Only messages sent before this day
+	MaxSize        int64     // Skip messages larger than this many bytes
+	Unseen         bool      // Only unread messages
+	Flagged        bool      // Only flagged messages
+	HeaderRegex    string    // Only messages with a header line matching this regex, e.g. "^Subject:.*invoice"
+	IncludeFolders []string  // Only folders matching one of these regexes
+}
+
+// ParseFilter parses filter terms:
+//
+//	min-age=DAYS  max-age=DAYS  since=YYYY-MM-DD  before=YYYY-MM-DD
+//	max-size=SIZE  unseen  flagged  header=REGEX  include=REGEX
+//
+// include may be repeated. Values containing spaces are quoted with ' or ".
+func ParseFilter(s string) (MessageFilter, error) {
+	var f MessageFilter
+	terms, err := splitFilterTerms(s)
+	if err != nil {
+		return f, err
+	}
+
+	for _, term := range terms {
+		key, value, hasValue := strings.Cut(term, "=")
+		if (key == "unseen" || key == "flagged") == hasValue {
+			return f, fmt.Errorf("invalid filter term %q", term)
+		}
+
+		switch key {
+		case "min-age", "max-age":
+			days, err := strconv.Atoi(value)
+			if err != nil || days <= 0 {
+				return f, fmt.Errorf("invalid %s %q: expected a number of days", key, value)
+			}
+			if key == "min-age" {
+				f.MinAge = days
+			} else {
+				f.MaxAge = days
+			}
+		case "since", "before":
+			day, err := time.ParseInLocation("2006-01-02", value, time.Local)
+			if err != nil {
+				return f, fmt.Errorf("invalid %s date %q: expected YYYY-MM-DD", key, value)
+			}
+			if key == "since" {
+				f.Since = day
+			} else {
+				f.Before = day
+			}
+		case "max-size":
+			size, ok := parseByteSize(value)
+			if !ok || size <= 0 {
+				return f, fmt.Errorf("invalid max-size %q: expected a size such as 25MB", value)
+			}
+			f.MaxSize = size
+		case "unseen":
+			f.Unseen = true
+		case "flagged":
+			f.Flagged = true
+		case "header":
+			f.HeaderRegex = value
+		case "include":
+			f.IncludeFolders = append(f.IncludeFolders, value)
+		default:
+			return f, fmt.Errorf("unknown filter term %q", key)
+		}
+	}
+	if err := f.Validate(); err != nil {
+		return MessageFilter{}, err
+	}
+	return f, nil
+}
+
+// splitFilterTerms splits filter terms at spaces, keeping quoted values whole
+func splitFilterTerms(s string) ([]string, error) {
+	var terms []string
+	var term strings.Builder
+	var quote rune
+	started := false
+
+	for _, r := range s {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			term.WriteRune(r)
+		case r == '\'' || r == '"':
+			quote, started = r, true
+		case unicode.IsSpace(r):
+			if started {
+				terms = append(terms, term.String())
+				term.Reset()
+				started = false
+			}
+		default:
+			term.WriteRune(r)
+			started = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote in filter %q", s)
+	}
+	if started {
+		terms = append(terms, term.String())
+	}
+	return terms, nil
+}
+
+// Validate checks that the filter can select any message and that its regexes compile
+func (f MessageFilter) Validate() error {
+	if f.MinAge < 0 || f.MaxAge < 0 || f.MaxSize < 0 {
+		return fmt.Errorf("filter ages and sizes cannot be negative")
+	}
+	if f.MinAge > 0 && f.MaxAge > 0 && f.MinAge >= f.MaxAge {
+		return fmt.Errorf("min-age %d must be less than max-age %d", f.MinAge, f.MaxAge)
+	}
+	if !f.Since.IsZero() && !f.Before.IsZero() && !f.Since.Before(f.Before) {
+		return fmt.Errorf("since %s must be before %s", f.Since.Format("2006-01-02"), f.Before.Format("2006-01-02"))
+	}
+	if f.HeaderRegex != "" {
+		if _, err := regexp.Compile(f.HeaderRegex); err != nil {
+			return fmt.Errorf("invalid header regex: %w", err)
+		}
+		// imapsync gets the regex inside m{...}; a stray brace would end it early
+		if !balancedBraces(f.HeaderRegex) {
+			return fmt.Errorf("invalid header regex: unbalanced { or }; escape literal braces as \\{ and \\}")
+		}
+	}
+	for _, folder := range f.IncludeFolders {
+		if _, err := regexp.Compile(folder); err != nil {
+			return fmt.Errorf("invalid include regex: %w", err)
+		}
+	}
+	return nil
+}
+
+// IsZero reports whether the filter selects every message
+func (f MessageFilter) IsZero() bool {
+	return f.MinAge == 0 && f.MaxAge == 0 && f.Since.IsZero() && f.Before.IsZero() && f.MaxSize == 0 &&
+		!f.Unseen && !f.Flagged && f.HeaderRegex == "" && len(f.IncludeFolders) == 0
+}
+
+// String returns the filter in the syntax ParseFilter reads
+func (f MessageFilter) String() string {
+	var terms []string
+	if f.MinAge > 0 {
+		terms = append(terms, fmt.Sprintf("min-age=%d", f.MinAge))
+	}
+	if f.MaxAge > 0 {
+		terms = append(terms, fmt.Sprintf("max-age=%d", f.MaxAge))
+	}
+	if !f.Since.IsZero() {
+		terms = append(terms, "since="+f.Since.Format("2006-01-02"))
+	}
+	if !f.Before.IsZero() {
+		terms = append(terms, "before="+f.Before.Format("2006-01-02"))
+	}
+	if f.MaxSize > 0 {
+		terms = append(terms, "max-size="+formatByteSize(f.MaxSize))
+	}
+	if f.Unseen {
+		terms = append(terms, "unseen")
+	}
+	if f.Flagged {
+		terms = append(terms, "flagged")
+	}
+	if f.HeaderRegex != "" {
+		terms = append(terms, "header="+quoteFilterValue(f.HeaderRegex))
+	}
+	for _, folder := range f.IncludeFolders {
+		terms = append(terms, "include="+quoteFilterValue(folder))
+	}
+	return strings.Join(terms, " ")
+}
+
+// quoteFilterValue quotes a value that contains spaces or quotes
+func quoteFilterValue(value string) string {
+	if strings.IndexFunc(value, func(r rune) bool { return unicode.IsSpace(r) || r == '\'' || r == '"' }) < 0 {
+		return value
+	}
+	if strings.Contains(value, "'") {
+		return `"` + value + `"`
+	}
+	return "'" + value + "'"
+}
+
+// formatByteSize writes a size with the largest unit that divides it
+func formatByteSize(size int64) string {
+	for _, u := range []struct {
+		suffix string
+		size   int64
+	}{{"GB", 1000 * 1000 * 1000}, {"MB", 1000 * 1000}, {"KB", 1000}} {
+		if size%u.size == 0 {
+			return strconv.FormatInt(size/u.size, 10) + u.suffix
+		}
+	}
+	return strconv.FormatInt(size, 10)
+}
+
+// filterArgs translates a filter to imapsync options. Dates, flags and the
+// header go through IMAP SEARCH and --skipmess, which imapsync applies on the
+// server and to each fetched message.
+func filterArgs(f MessageFilter) []string {
+	var args []string
+	if f.MinAge > 0 {
+		args = append(args, "--minage", strconv.Itoa(f.MinAge))
+	}
+	if f.MaxAge > 0 {
+		args = append(args, "--maxage", strconv.Itoa(f.MaxAge))
+	}
+	if f.MaxSize > 0 {
+		args = append(args, "--maxsize", strconv.FormatInt(f.MaxSize, 10))
+	}
+
+	if search := searchKeys(f); len(search) > 0 {
+		args = append(args, "--search", strings.Join(search, " "))
+	}
+
+	if f.HeaderRegex != "" {
+		args = append(args, "--skipmess", headerSkipRegex(f.HeaderRegex))
+	}
+	for _, folder := range f.IncludeFolders {
+		args = append(args, "--include", folder)
+	}
+	return args
+}
+
+// searchKeys returns the IMAP SEARCH keys for the dates and flags of a
+// filter. The keys are ANDed; dates use the English month names of RFC 3501.
+func searchKeys(f MessageFilter) []string {
+	var search []string
+	if !f.Since.IsZero() {
+		search = append(search, "SENTSINCE "+imapDate(f.Since))
+	}
+	if !f.Before.IsZero() {
+		search = append(search, "SENTBEFORE "+imapDate(f.Before))
+	}
+	if f.Unseen {
+		search = append(search, "UNSEEN")
+	}
+	if f.Flagged {
+		search = append(search, "FLAGGED")
+	}
+	return search
+}
+
+// imapDate formats a day for IMAP SEARCH
+func imapDate(t time.Time) string {
+	return t.Format("2-Jan-2006")
+}
+
+// balancedBraces reports whether every unescaped { in re has a matching }
+func balancedBraces(re string) bool {
+	depth := 0
+	escaped := false
+	for _, r := range re {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '{':
+			depth++
+		case r == '}':
+			depth--
+			if depth < 0 {
+				return false
+			}
+		}
+	}
+	return depth == 0
+}
+
+// headerSkipRegex builds the Perl regex for --skipmess that skips messages
+// without a header line matching re. It walks the header lines up to the
+// first empty line and fails when one of them matches. A regex anchored with ^
+// matches at the start of a header line, any other anywhere in it. imapsync
+// evaluates the regex as Perl code, so a bare @ would be read as an array.
+func headerSkipRegex(re string) string {
+	lineStart := `[^\r\n]*?`
+	if anchored := strings.TrimPrefix(re, "^"); anchored != re {
+		re, lineStart = anchored, ""
+	}
+
+	var b strings.Builder
+	escaped := false
+	for _, r := range re {
+		if r == '@' && !escaped {
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+		escaped = r == '\\' && !escaped
+	}
+	return `m{\A(?!(?:[^\r\n]+\r?\n)*?` + lineStart + `(?:` + b.String() + `))}`
+}
@@ -0,0 +1,155 @@
+package app
+
+import (
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseFilterRoundTrip(t *testing.T) {
+	tests := []string{
+		"",
+		"max-age=730",
+		"min-age=30 max-age=365",
+		"since=2024-01-01 before=2024-07-01",
+		"max-size=25MB unseen flagged",
+		"max-size=1500",
+		`header='^Subject:.*weekly report'`,
+		`header="it's done"`,
+		`header=^From:.*@example\.com include=^INBOX include=^Archive/20(19|20)`,
+	}
+	for _, s := range tests {
+		f, err := ParseFilter(s)
+		if err != nil {
+			t.Errorf("ParseFilter(%q): %v", s, err)
+			continue
+		}
+		if got := f.String(); got != s {
+			t.Errorf("ParseFilter(%q).String() = %q", s, got)
+		}
+		again, err := ParseFilter(f.String())
+		if err != nil {
+			t.Errorf("ParseFilter(%q) of String(): %v", f.String(), err)
+			continue
+		}
+		if !reflect.DeepEqual(again, f) {
+			t.Errorf("round trip of %q: got %+v, want %+v", s, again, f)
+		}
+	}
+}
+
+func TestParseFilterErrors(t *testing.T) {
+	tests := []string{
+		"max-age=0",
+		"max-age=abc",
+		"min-age=30 max-age=30",
+		"since=2024-07-01 before=2024-01-01",
+		"since=01.02.2024",
+		"max-size=big",
+		"unseen=yes",
+		"flagged=1",
+		"header",
+		"header=(unclosed",
+		"header=invoice}",
+		"header=a{2",
+		"colour=red",
+		`header='unterminated`,
+	}
+	for _, s := range tests {
+		if _, err := ParseFilter(s); err == nil {
+			t.Errorf("ParseFilter(%q) succeeded", s)
+		}
+	}
+
+	// Escaped and balanced braces are fine
+	for _, s := range []string{`header=\}`, `header=^X-Ticket:\s*\d{4,6}`} {
+		if _, err := ParseFilter(s); err != nil {
+			t.Errorf("ParseFilter(%q): %v", s, err)
+		}
+	}
+}
+
+func TestFilterArgs(t *testing.T) {
+	f := MessageFilter{
+		MinAge:         7,
+		MaxAge:         730,
+		Since:          time.Date(2024, 3, 5, 0, 0, 0, 0, time.Local),
+		MaxSize:        25 * 1000 * 1000,
+		Unseen:         true,
+		HeaderRegex:    "^From:.*@example.com",
+		IncludeFolders: []string{"^INBOX", "^Sent"},
+	}
+	want := []string{
+		"--minage", "7",
+		"--maxage", "730",
+		"--maxsize", "25000000",
+		"--search", "SENTSINCE 5-Mar-2024 UNSEEN",
+		"--skipmess", `m{\A(?!(?:[^\r\n]+\r?\n)*?(?:From:.*\@example.com))}`,
+		"--include", "^INBOX",
+		"--include", "^Sent",
+	}
+	if got := filterArgs(f); !reflect.DeepEqual(got, want) {
+		t.Errorf("filterArgs() =\n%q\nwant\n%q", got, want)
+	}
+
+	if got := filterArgs(MessageFilter{}); len(got) != 0 {
+		t.Errorf("filterArgs of the zero filter = %q, want none", got)
+	}
+}
+
+func TestHeaderSkipRegex(t *testing.T) {
+	tests := []struct {
+		re   string
+		want string
+	}{
+		{"^Subject:.*invoice", `m{\A(?!(?:[^\r\n]+\r?\n)*?(?:Subject:.*invoice))}`},
+		{"invoice", `m{\A(?!(?:[^\r\n]+\r?\n)*?[^\r\n]*?(?:invoice))}`},
+		{"a|b", `m{\A(?!(?:[^\r\n]+\r?\n)*?[^\r\n]*?(?:a|b))}`},
+		{`^From:.*\@x`, `m{\A(?!(?:[^\r\n]+\r?\n)*?(?:From:.*\@x))}`},
+	}
+	for _, tt := range tests {
+		if got := headerSkipRegex(tt.re); got != tt.want {
+			t.Errorf("headerSkipRegex(%q) = %q, want %q", tt.re, got, tt.want)
+		}
+	}
+}
+
+// TestHeaderSkipRegexPerl runs the generated regexes through perl the way
+// imapsync evaluates --skipmess
+func TestHeaderSkipRegexPerl(t *testing.T) {
+	perl, err := exec.LookPath("perl")
+	if err != nil {
+		t.Skip("perl not installed")
+	}
+
+	message := "From: Billing <billing@example.com>\r\n" +
+		"Subject: your invoice 12\r\n" +
+		"\r\n" +
+		"Body mentions a receipt\r\n"
+
+	tests := []struct {
+		re   string
+		skip bool
+	}{
+		{"invoice", false},
+		{"^Subject:.*invoice", false},
+		{"^From:.*@example\\.com", false},
+		{"^invoice", true},
+		{"receipt", true}, // Only header lines count
+		{"^To:", true},
+		{"refund|invoice", false},
+	}
+	for _, tt := range tests {
+		cmd := exec.Command(perl, "-e", `local $/; my $s = <STDIN>; my $re = shift; my $hit = eval "\$s =~ $re"; die $@ if $@; print $hit ? "skip" : "keep"`, headerSkipRegex(tt.re))
+		cmd.Stdin = strings.NewReader(message)
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("perl with %q: %v: %s", tt.re, err, out)
+		}
+		if skip := string(out) == "skip"; skip != tt.skip {
+			t.Errorf("header=%q: perl says %s, want skip=%v", tt.re, out, tt.skip)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package app
+
+import (
+	"net"
+	"strings"
+	"sync"
+)
+
+// HostLimitsConfig holds per-host connection limits
+type HostLimitsConfig struct {
+	Default        int            `json:"default"`         // Limit for hosts without a preset or entry; 0 means unlimited
+	Hosts          map[string]int `json:"hosts"`           // Host name or "*.domain" pattern -> max concurrent jobs
+	DisablePresets bool           `json:"disable_presets"` // Ignore the built-in provider presets
+}
+
+// hostLimitPresets are conservative limits for well-known providers, matched by host suffix
+var hostLimitPresets = map[string]int{
+	"outlook.office365.com": 8,  // Microsoft 365 throttles per tenant
+	"imap-mail.outlook.com": 8,  // Outlook.com
+	"imap.gmail.com":        10, // Gmail allows 15 connections per account
+	"imap.mail.yahoo.com":   5,
+	"imap.mail.me.com":      5, // iCloud
+	"imap.zoho.com":         5,
+	"imap.gmx.net":          4,
+	"imap.web.de":           4,
+	"imap.yandex.com":       5,
+}
+
+// HostLimiter caps the number of concurrent jobs talking to each IMAP host.
+// It sits in front of the global semaphore so one slow server cannot hold
+// every worker while jobs for other servers wait.
+type HostLimiter struct {
+	config *HostLimitsConfig
+
+	mu     sync.Mutex
+	active map[string]int
+}
+
+// NewHostLimiter creates a host limiter; a nil config applies the provider presets only
+func NewHostLimiter(config *HostLimitsConfig) *HostLimiter {
+	if config == nil {
+		config = &HostLimitsConfig{}
+	}
+
+	return &HostLimiter{
+		config: config,
+		active: make(map[string]int),
+	}
+}
+
+// normalizeHost lowercases a host and strips the port
+func normalizeHost(host string) string {
+	host = strings.TrimSpace(host)
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.TrimSuffix(strings.ToLower(host), ".")
+}
+
+// Limit returns the maximum number of concurrent jobs for a host; 0 means unlimited
+func (hl *HostLimiter) Limit(host string) int {
+	host = normalizeHost(host)
+	if host == "" {
+		return 0
+	}
+
+	if limit, ok := hl.lookup(host); ok {
+		return limit
+	}
+	return hl.config.Default
+}
+
+// lookup finds an explicit or preset limit for a normalized host
+func (hl *HostLimiter) lookup(host string) (int, bool) {
+	for pattern, limit := range hl.config.Hosts {
+		if normalizeHost(pattern) == host {
+			return limit, true
+		}
+	}
+	for pattern, limit := range hl.config.Hosts {
+		if suffix, ok := strings.CutPrefix(pattern, "*"); ok && strings.HasSuffix(host, strings.ToLower(suffix)) {
+			return limit, true
+		}
+	}
+
+	if hl.config.DisablePresets {
+		return 0, false
+	}
+	for suffix, limit := range hostLimitPresets {
+		if host == suffix || strings.HasSuffix(host, "."+suffix) {
+			return limit, true
+		}
+	}
+	return 0, false
+}
+
+// TryAcquire reserves a slot on both hosts of a job without blocking.
+// A job whose source and destination are the same host uses two slots there.
+func (hl *HostLimiter) TryAcquire(sourceHost, destHost string) bool {
+	src, dst := normalizeHost(sourceHost), normalizeHost(destHost)
+
+	hl.mu.Lock()
+	defer hl.mu.Unlock()
+
+	need := map[string]int{src: 1}
+	need[dst]++
+
+	for host, n := range need {
+		if limit := hl.Limit(host); limit > 0 && hl.active[host]+n > limit {
+			return false
+		}
+	}
+	for host, n := range need {
+		hl.active[host] += n
+	}
+	return true
+}
+
+// Release frees the slots taken by TryAcquire
+func (hl *HostLimiter) Release(sourceHost, destHost string) {
+	hl.mu.Lock()
+	defer hl.mu.Unlock()
+
+	for _, host := range []string{normalizeHost(sourceHost), normalizeHost(destHost)} {
+		if hl.active[host] <= 1 {
+			delete(hl.active, host)
+		} else {
+			hl.active[host]--
+		}
+	}
+}
+
+// Active returns the number of slots in use per host
+func (hl *HostLimiter) Active() map[string]int {
+	hl.mu.Lock()
+	defer hl.mu.Unlock()
+
+	active := make(map[string]int, len(hl.active))
+	for host, n := range hl.active {
+		active[host] = n
+	}
+	return active
+}
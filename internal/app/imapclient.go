@@ -0,0 +1,528 @@
+package app
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+const (
+	imapTimeout    = 2 * time.Minute // Per command; a message fetch counts as one
+	maxLiteralSize = 256 << 20       // Larger literals, e.g. huge messages, are refused
+)
+
+// imapClient is a minimal IMAP4rev1 client that reads mailboxes: login, list,
+// examine, search and fetch. It only uses the standard library.
+type imapClient struct {
+	conn net.Conn
+	r    *bufio.Reader
+	tag  int
+	stop func() bool // Detaches the context watcher
+}
+
+// imapResponse is a server response line with the literals it announced
+type imapResponse struct {
+	text     string
+	literals [][]byte
+}
+
+// imapStatusError is a NO or BAD completion of a command
+type imapStatusError struct {
+	command string
+	status  string
+	text    string
+}
+
+// Error implements the error interface
+func (e *imapStatusError) Error() string {
+	return fmt.Sprintf("IMAP %s: %s %s", e.command, e.status, e.text)
+}
+
+// imapFolder is a folder returned by LIST
+type imapFolder struct {
+	Name      string // Name as the server knows it, in modified UTF-7
+	Delimiter string // Hierarchy delimiter, "" for a flat namespace
+	NoSelect  bool   // The folder holds no messages
+}
+
+// imapMessage is a fetched message
+type imapMessage struct {
+	UID          uint32
+	Flags        []string
+	InternalDate time.Time
+	Body         []byte
+}
+
+var (
+	// literalRe matches the announcement of a literal at the end of a line, e.g. "{2345}"
+	literalRe = regexp.MustCompile(`\{(\d+)\+?\}$`)
+
+	// uidValidityRe matches the UIDVALIDITY response code of SELECT and EXAMINE
+	uidValidityRe = regexp.MustCompile(`(?i)\[UIDVALIDITY (\d+)\]`)
+
+	// existsRe matches the message count of a folder, e.g. "* 172 EXISTS"
+	existsRe = regexp.MustCompile(`(?i)^\* (\d+) EXISTS`)
+)
+
+// dialIMAP connects to an IMAP server and reads its greeting. A host without
+// a port uses 993 with SSL and 143 without. Cancelling ctx closes the connection.
+func dialIMAP(ctx context.Context, host string, ssl bool) (*imapClient, error) {
+	addr := host
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		port := "143"
+		if ssl {
+			port = "993"
+		}
+		addr = net.JoinHostPort(host, port)
+	}
+
+	dialer := &net.Dialer{Timeout: 30 * time.Second}
+	var conn net.Conn
+	var err error
+	if ssl {
+		serverName, _, _ := net.SplitHostPort(addr)
+		tlsDialer := &tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: serverName}}
+		conn, err = tlsDialer.DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c := &imapClient{conn: conn, r: bufio.NewReader(conn)}
+	c.stop = context.AfterFunc(ctx, func() { conn.Close() })
+
+	conn.SetDeadline(time.Now().Add(imapTimeout))
+	greeting, err := c.readResponse()
+	if err != nil {
+		c.Close()
+		return nil, fmt.Errorf("failed to read IMAP greeting: %w", err)
+	}
+	if !strings.HasPrefix(strings.ToUpper(greeting.text), "* OK") && !strings.HasPrefix(strings.ToUpper(greeting.text), "* PREAUTH") {
+		c.Close()
+		return nil, fmt.Errorf("IMAP server refused the connection: %s", greeting.text)
+	}
+	return c, nil
+}
+
+// Close closes the connection without logging out
+func (c *imapClient) Close() error {
+	c.stop()
+	return c.conn.Close()
+}
+
+// Logout ends the session and closes the connection
+func (c *imapClient) Logout() error {
+	_, err := c.command("LOGOUT")
+	c.Close()
+	return err
+}
+
+// readResponse reads a response line and the literals it announces. The
+// text keeps the "{size}" announcements so parse can find the literals.
+func (c *imapClient) readResponse() (*imapResponse, error) {
+	resp := &imapResponse{}
+	var text strings.Builder
+	for {
+		line, err := c.r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		text.WriteString(line)
+
+		m := literalRe.FindStringSubmatch(line)
+		if m == nil {
+			break
+		}
+		size, err := strconv.Atoi(m[1])
+		if err != nil || size > maxLiteralSize {
+			return nil, fmt.Errorf("IMAP literal of %s bytes is too large", m[1])
+		}
+		literal := make([]byte, size)
+		if _, err := io.ReadFull(c.r, literal); err != nil {
+			return nil, err
+		}
+		resp.literals = append(resp.literals, literal)
+	}
+	resp.text = text.String()
+	return resp, nil
+}
+
+// command sends a command and returns the untagged responses that came
+// before its completion. A NO or BAD completion is an *imapStatusError.
+func (c *imapClient) command(format string, args ...interface{}) ([]*imapResponse, error) {
+	c.tag++
+	tag := fmt.Sprintf("a%03d", c.tag)
+	cmd := fmt.Sprintf(format, args...)
+	name, _, _ := strings.Cut(cmd, " ")
+
+	c.conn.SetDeadline(time.Now().Add(imapTimeout))
+	if _, err := fmt.Fprintf(c.conn, "%s %s\r\n", tag, cmd); err != nil {
+		return nil, err
+	}
+
+	var untagged []*imapResponse
+	for {
+		resp, err := c.readResponse()
+		if err != nil {
+			return nil, err
+		}
+		rest, ok := strings.CutPrefix(resp.text, tag+" ")
+		if !ok {
+			untagged = append(untagged, resp)
+			continue
+		}
+
+		status, text, _ := strings.Cut(rest, " ")
+		if !strings.EqualFold(status, "OK") {
+			return untagged, &imapStatusError{command: name, status: strings.ToUpper(status), text: text}
+		}
+		return untagged, nil
+	}
+}
+
+// Login authenticates with a user name and password
+func (c *imapClient) Login(user, password string) error {
+	u, err := quoteIMAP(user)
+	if err != nil {
+		return err
+	}
+	p, err := quoteIMAP(password)
+	if err != nil {
+		return err
+	}
+	_, err = c.command("LOGIN %s %s", u, p)
+	return err
+}
+
+// List returns every folder of the mailbox
+func (c *imapClient) List() ([]imapFolder, error) {
+	responses, err := c.command(`LIST "" "*"`)
+	if err != nil {
+		return nil, err
+	}
+
+	var folders []imapFolder
+	for _, resp := range responses {
+		fields, err := resp.parse()
+		if err != nil || len(fields) < 5 || !strings.EqualFold(atom(fields[1]), "LIST") {
+			continue
+		}
+		folder := imapFolder{Name: str(fields[4]), Delimiter: str(fields[3])}
+		if flags, ok := fields[2].([]interface{}); ok {
+			for _, flag := range flags {
+				if strings.EqualFold(atom(flag), `\Noselect`) || strings.EqualFold(atom(flag), `\NonExistent`) {
+					folder.NoSelect = true
+				}
+			}
+		}
+		folders = append(folders, folder)
+	}
+	sort.Slice(folders, func(i, j int) bool { return folders[i].Name < folders[j].Name })
+	return folders, nil
+}
+
+// Examine opens a folder read-only and returns its UIDVALIDITY and message count
+func (c *imapClient) Examine(folder string) (uint32, int, error) {
+	name, err := quoteIMAP(folder)
+	if err != nil {
+		return 0, 0, err
+	}
+	responses, err := c.command("EXAMINE %s", name)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var uidValidity uint64
+	exists := 0
+	for _, resp := range responses {
+		if m := uidValidityRe.FindStringSubmatch(resp.text); m != nil {
+			uidValidity, _ = strconv.ParseUint(m[1], 10, 32)
+		}
+		if m := existsRe.FindStringSubmatch(resp.text); m != nil {
+			exists, _ = strconv.Atoi(m[1])
+		}
+	}
+	return uint32(uidValidity), exists, nil
+}
+
+// SearchUIDs returns the UIDs of the messages matching IMAP SEARCH criteria, in order
+func (c *imapClient) SearchUIDs(criteria string) ([]uint32, error) {
+	responses, err := c.command("UID SEARCH %s", criteria)
+	if err != nil {
+		return nil, err
+	}
+
+	var uids []uint32
+	for _, resp := range responses {
+		fields := strings.Fields(resp.text)
+		if len(fields) < 2 || !strings.EqualFold(fields[1], "SEARCH") {
+			continue
+		}
+		for _, field := range fields[2:] {
+			if uid, err := strconv.ParseUint(field, 10, 32); err == nil {
+				uids = append(uids, uint32(uid))
+			}
+		}
+	}
+	sort.Slice(uids, func(i, j int) bool { return uids[i] < uids[j] })
+	return uids, nil
+}
+
+// FetchSizes returns the sizes of messages by UID
+func (c *imapClient) FetchSizes(uids []uint32) (map[uint32]int64, error) {
+	if len(uids) == 0 {
+		return map[uint32]int64{}, nil
+	}
+	responses, err := c.command("UID FETCH %s (UID RFC822.SIZE)", uidSet(uids))
+	if err != nil {
+		return nil, err
+	}
+
+	sizes := make(map[uint32]int64)
+	for _, resp := range responses {
+		items, ok := fetchItems(resp)
+		if !ok {
+			continue
+		}
+		uid, _ := strconv.ParseUint(atom(items["UID"]), 10, 32)
+		size, _ := strconv.ParseInt(atom(items["RFC822.SIZE"]), 10, 64)
+		sizes[uint32(uid)] = size
+	}
+	return sizes, nil
+}
+
+// FetchMessage fetches a whole message without setting its \Seen flag
+func (c *imapClient) FetchMessage(uid uint32) (*imapMessage, error) {
+	responses, err := c.command("UID FETCH %d (UID FLAGS INTERNALDATE BODY.PEEK[])", uid)
+	if err != nil {
+		return nil, err
+	}
+
+	// Servers may send unsolicited FETCH responses for other messages
+	for _, resp := range responses {
+		items, ok := fetchItems(resp)
+		if !ok || atom(items["UID"]) != strconv.FormatUint(uint64(uid), 10) {
+			continue
+		}
+		body, ok := items["BODY[]"].([]byte)
+		if !ok {
+			continue
+		}
+
+		msg := &imapMessage{UID: uid, Body: body}
+		if flags, ok := items["FLAGS"].([]interface{}); ok {
+			for _, flag := range flags {
+				msg.Flags = append(msg.Flags, atom(flag))
+			}
+		}
+		msg.InternalDate, _ = time.Parse("_2-Jan-2006 15:04:05 -0700", str(items["INTERNALDATE"]))
+		return msg, nil
+	}
+	return nil, fmt.Errorf("message UID %d not found", uid)
+}
+
+// uidSet writes sorted UIDs as an IMAP sequence set, e.g. "1:4,7,9:10"
+func uidSet(uids []uint32) string {
+	var b strings.Builder
+	for i := 0; i < len(uids); {
+		j := i
+		for j+1 < len(uids) && uids[j+1] == uids[j]+1 {
+			j++
+		}
+		if b.Len() > 0 {
+			b.WriteByte(',')
+		}
+		if i == j {
+			fmt.Fprintf(&b, "%d", uids[i])
+		} else {
+			fmt.Fprintf(&b, "%d:%d", uids[i], uids[j])
+		}
+		i = j + 1
+	}
+	return b.String()
+}
+
+// fetchItems returns the data items of a FETCH response by name
+func fetchItems(resp *imapResponse) (map[string]interface{}, bool) {
+	fields, err := resp.parse()
+	if err != nil || len(fields) < 4 || !strings.EqualFold(atom(fields[2]), "FETCH") {
+		return nil, false
+	}
+	list, ok := fields[3].([]interface{})
+	if !ok {
+		return nil, false
+	}
+
+	items := make(map[string]interface{}, len(list)/2)
+	for i := 0; i+1 < len(list); i += 2 {
+		items[strings.ToUpper(atom(list[i]))] = list[i+1]
+	}
+	return items, true
+}
+
+// parse splits a response into atoms and quoted strings (string), literals
+// ([]byte), NIL (nil) and parenthesized lists ([]interface{})
+func (r *imapResponse) parse() ([]interface{}, error) {
+	p := &imapParser{text: r.text, literals: r.literals}
+	return p.list(0)
+}
+
+// imapParser reads the fields of a response
+type imapParser struct {
+	text     string
+	pos      int
+	literals [][]byte
+}
+
+// list reads fields up to the closing parenthesis of a list, or to the end of
+// the response at depth 0
+func (p *imapParser) list(depth int) ([]interface{}, error) {
+	var fields []interface{}
+	for {
+		for p.pos < len(p.text) && p.text[p.pos] == ' ' {
+			p.pos++
+		}
+		if p.pos >= len(p.text) {
+			if depth > 0 {
+				return nil, errors.New("unterminated list in IMAP response")
+			}
+			return fields, nil
+		}
+
+		switch p.text[p.pos] {
+		case ')':
+			p.pos++
+			if depth == 0 {
+				return nil, errors.New("unexpected ) in IMAP response")
+			}
+			return fields, nil
+		case '(':
+			p.pos++
+			list, err := p.list(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, list)
+		case '"':
+			s, err := p.quoted()
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, s)
+		case '{':
+			end := strings.IndexByte(p.text[p.pos:], '}')
+			if end < 0 || len(p.literals) == 0 {
+				return nil, errors.New("missing literal in IMAP response")
+			}
+			p.pos += end + 1
+			fields = append(fields, p.literals[0])
+			p.literals = p.literals[1:]
+		default:
+			start := p.pos
+			for p.pos < len(p.text) && !strings.ContainsRune(" ()", rune(p.text[p.pos])) {
+				// Brackets may hold spaces, e.g. BODY[HEADER.FIELDS (SUBJECT)]
+				if p.text[p.pos] == '[' {
+					if end := strings.IndexByte(p.text[p.pos:], ']'); end > 0 {
+						p.pos += end
+					}
+				}
+				p.pos++
+			}
+			if field := p.text[start:p.pos]; strings.EqualFold(field, "NIL") {
+				fields = append(fields, nil)
+			} else {
+				fields = append(fields, field)
+			}
+		}
+	}
+}
+
+// quoted reads a quoted string
+func (p *imapParser) quoted() (string, error) {
+	var b strings.Builder
+	for p.pos++; p.pos < len(p.text); p.pos++ {
+		switch c := p.text[p.pos]; c {
+		case '\\':
+			p.pos++
+			if p.pos < len(p.text) {
+				b.WriteByte(p.text[p.pos])
+			}
+		case '"':
+			p.pos++
+			return b.String(), nil
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", errors.New("unterminated string in IMAP response")
+}
+
+// atom returns a field as text; lists and NIL are empty
+func atom(field interface{}) string {
+	s, _ := field.(string)
+	return s
+}
+
+// str returns a string or literal field as text
+func str(field interface{}) string {
+	if b, ok := field.([]byte); ok {
+		return string(b)
+	}
+	return atom(field)
+}
+
+// quoteIMAP quotes a string argument. Line breaks cannot be quoted.
+func quoteIMAP(s string) (string, error) {
+	if strings.ContainsAny(s, "\r\n\x00") {
+		return "", errors.New("IMAP arguments cannot contain line breaks")
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`, nil
+}
+
+// decodeFolderName decodes a folder name from modified UTF-7 (RFC 3501
+// section 5.1.3). Names that are not valid modified UTF-7 are returned unchanged.
+func decodeFolderName(name string) string {
+	var b strings.Builder
+	for i := 0; i < len(name); i++ {
+		if name[i] != '&' {
+			b.WriteByte(name[i])
+			continue
+		}
+
+		end := strings.IndexByte(name[i:], '-')
+		if end < 0 {
+			return name
+		}
+		encoded := name[i+1 : i+end]
+		i += end
+		if encoded == "" {
+			b.WriteByte('&')
+			continue
+		}
+
+		data, err := base64.RawStdEncoding.DecodeString(strings.ReplaceAll(encoded, ",", "/"))
+		if err != nil || len(data)%2 != 0 {
+			return name
+		}
+		units := make([]uint16, len(data)/2)
+		for k := range units {
+			units[k] = uint16(data[2*k])<<8 | uint16(data[2*k+1])
+		}
+		b.WriteString(string(utf16.Decode(units)))
+	}
+	return b.String()
+}
@@ -0,0 +1,92 @@
+package app
+
+import (
+	"net"
+	"strconv"
+)
+
+// TransferOptions are the imapsync switches that can be changed per transfer.
+// The zero value is the production default: SSL on both sides, all folders.
+type TransferOptions struct {
+	NoSSL          bool     // Connect without SSL/TLS, e.g. to a test server
+	DryRun         bool     // Show what would be copied without changing the destination
+	JustFolders    bool     // Only sync the folder structure, no messages
+	Folders        []string // Only sync these folders; empty means all of them
+	ExcludeFolders []string // Regexes of folders to skip on top of the defaults
+}
+
+// imapsyncArgs builds the imapsync command line for a job using the
+// production-tested defaults, plus the job's options and filter and the
+// bandwidth caps when set
+func imapsyncArgs(job *TransferJob, tmpDir string, limit BandwidthLimit) []string {
+	args := serverArgs(job)
+	args = append(args,
+		"--exclude", "^Junk\\ E-Mail",
+		"--exclude", "^Deleted\\ Items",
+		"--exclude", "^Deleted",
+		"--exclude", "^Trash",
+	)
+	for _, folder := range job.Options.ExcludeFolders {
+		args = append(args, "--exclude", folder)
+	}
+	for _, folder := range job.Options.Folders {
+		args = append(args, "--folder", folder)
+	}
+	args = append(args, filterArgs(job.Filter)...)
+	args = append(args,
+		"--regextrans2", "s#^Sent$#Sent Items#",
+		"--regextrans2", "s#^Spam$#Junk E-Mail#",
+		"--useuid",
+		"--usecache",
+		"--tmpdir", tmpDir,
+		"--syncinternaldates",
+		"--progress",
+	)
+	if job.Options.DryRun {
+		args = append(args, "--dry")
+	}
+	if job.Options.JustFolders {
+		args = append(args, "--justfolders")
+	}
+
+	return append(args, bandwidthArgs(limit)...)
+}
+
+// imapsyncLoginArgs builds a --justlogin command line that only checks both logins
+func imapsyncLoginArgs(job *TransferJob) []string {
+	return append([]string{"--justlogin"}, serverArgs(job)...)
+}
+
+// serverArgs returns the host and login options of both servers
+func serverArgs(job *TransferJob) []string {
+	ssl := !job.Options.NoSSL
+	args := hostArgs("1", job.SourceHost, ssl)
+	args = append(args, "--user1", job.SourceEmail, "--password1", job.SourcePass)
+	args = append(args, hostArgs("2", job.DestHost, ssl)...)
+	return append(args, "--user2", job.DestEmail, "--password2", job.DestPass)
+}
+
+// hostArgs returns the options of server n; a host such as "imap.example.com:993"
+// is passed to imapsync as a host and a port
+func hostArgs(n, host string, ssl bool) []string {
+	args := []string{"--host" + n, host}
+	if h, port, err := net.SplitHostPort(host); err == nil {
+		args = []string{"--host" + n, h, "--port" + n, port}
+	}
+	if ssl {
+		args = append(args, "--ssl"+n)
+	}
+	return args
+}
+
+// bandwidthArgs translates a bandwidth limit to imapsync options
+func bandwidthArgs(limit BandwidthLimit) []string {
+	var args []string
+	if limit.BytesPerSecond > 0 {
+		args = append(args, "--maxbytespersecond", strconv.FormatInt(int64(limit.BytesPerSecond), 10))
+	}
+	if limit.MessagesPerSecond > 0 {
+		args = append(args, "--maxmessagespersecond", strconv.FormatFloat(limit.MessagesPerSecond, 'f', -1, 64))
+	}
+	return args
+}
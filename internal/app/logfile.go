@@ -0,0 +1,413 @@
+package app
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LoggingConfig holds log output and rotation settings
+type LoggingConfig struct {
+	Dir        string   `json:"dir"`         // Directory for the application and per-job logs
+	Format     string   `json:"format"`      // Console format: "text" or "json"
+	Level      string   `json:"level"`       // Minimum level: debug, info, warn, error
+	MaxSizeMB  int      `json:"max_size_mb"` // Rotate when a file grows beyond this size
+	MaxAge     Duration `json:"max_age"`     // Rotate when a file is older than this
+	MaxBackups int      `json:"max_backups"` // Rotated files to keep per log
+	Compress   bool     `json:"compress"`    // Gzip rotated files
+
+	DisableConsole bool            `json:"disable_console"` // Do not log to stdout
+	Sinks          []LogSinkConfig `json:"sinks"`           // Additional syslog/journald sinks
+}
+
+// DefaultLoggingConfig returns default logging settings. Logs go to the
+// console only; they are persisted once Dir is set.
+func DefaultLoggingConfig() LoggingConfig {
+	return LoggingConfig{
+		Format:     "text",
+		Level:      "info",
+		MaxSizeMB:  10,
+		MaxAge:     Duration{24 * time.Hour},
+		MaxBackups: 10,
+		Compress:   true,
+	}
+}
+
+const appLogName = "imapsync.log"
+
+var (
+	logConfigMu     sync.RWMutex
+	logConfig       *LoggingConfig
+	defaultLogLevel = LevelInfo
+)
+
+// ConfigureLogging applies the logging configuration to every logger created afterwards.
+// With a Dir set, records are also persisted as JSON to the application log so
+// they survive restarts. The sinks of the previous configuration are closed.
+func ConfigureLogging(cfg *LoggingConfig) error {
+	level, err := ParseLogLevel(cfg.Level)
+	if err != nil {
+		return err
+	}
+	format, err := ParseLogFormat(cfg.Format)
+	if err != nil {
+		return err
+	}
+
+	var sinks []LogSink
+	if !cfg.DisableConsole {
+		sinks = append(sinks, &writerSink{w: os.Stdout, format: format})
+	}
+	if cfg.Dir != "" {
+		file, err := NewRotatingFile(filepath.Join(cfg.Dir, appLogName), cfg)
+		if err != nil {
+			return err
+		}
+		sinks = append(sinks, &writerSink{w: file, format: FormatJSON})
+	}
+	for i := range cfg.Sinks {
+		sink, err := NewLogSink(&cfg.Sinks[i])
+		if err != nil {
+			for _, s := range sinks {
+				s.Close()
+			}
+			return err
+		}
+		sinks = append(sinks, sink)
+	}
+
+	logConfigMu.Lock()
+	logConfig = cfg
+	logConfigMu.Unlock()
+
+	defaultLogMu.Lock()
+	replaced := defaultLogSinks
+	defaultLogSinks = sinks
+	defaultLogLevel = level
+	defaultLogMu.Unlock()
+
+	for _, sink := range replaced {
+		sink.Close()
+	}
+	return nil
+}
+
+// currentLoggingConfig returns the active logging configuration or nil if logs are not persisted
+func currentLoggingConfig() *LoggingConfig {
+	logConfigMu.RLock()
+	defer logConfigMu.RUnlock()
+	if logConfig == nil || logConfig.Dir == "" {
+		return nil
+	}
+	return logConfig
+}
+
+// AppLogPath returns the path of the persisted application log, or "" when disabled
+func AppLogPath() string {
+	cfg := currentLoggingConfig()
+	if cfg == nil {
+		return ""
+	}
+	return filepath.Join(cfg.Dir, appLogName)
+}
+
+// JobLogDir returns the directory holding per-job logs, or "" when disabled
+func JobLogDir() string {
+	cfg := currentLoggingConfig()
+	if cfg == nil {
+		return ""
+	}
+	return filepath.Join(cfg.Dir, "jobs")
+}
+
+// NewPersistentLogger creates a logger that only writes to the persisted application log.
+// It is used for history entries that should not be echoed to the console.
+func NewPersistentLogger() *Logger {
+	logger := NewLogger()
+
+	var sinks []LogSink
+	for _, sink := range logger.sinks {
+		if ws, ok := sink.(*writerSink); ok && ws.persisted() {
+			sinks = append(sinks, sink)
+		}
+	}
+	logger.sinks = sinks
+	return logger
+}
+
+// openJobLog opens the log file capturing imapsync output for a job
+func openJobLog(jobID string) (*RotatingFile, error) {
+	cfg := currentLoggingConfig()
+	if cfg == nil {
+		return nil, nil
+	}
+	return NewRotatingFile(filepath.Join(cfg.Dir, "jobs", jobID+".log"), cfg)
+}
+
+// RotatingFile is an io.WriteCloser that rotates by size and age
+type RotatingFile struct {
+	path       string
+	maxSize    int64
+	maxAge     time.Duration
+	maxBackups int
+	compress   bool
+
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	openedAt time.Time
+}
+
+// NewRotatingFile opens path for appending, creating parent directories as needed
+func NewRotatingFile(path string, cfg *LoggingConfig) (*RotatingFile, error) {
+	rf := &RotatingFile{
+		path:       path,
+		maxSize:    int64(cfg.MaxSizeMB) * 1024 * 1024,
+		maxAge:     cfg.MaxAge.Duration,
+		maxBackups: cfg.MaxBackups,
+		compress:   cfg.Compress,
+	}
+
+	if err := rf.open(); err != nil {
+		return nil, err
+	}
+	return rf, nil
+}
+
+// Path returns the path of the active file
+func (rf *RotatingFile) Path() string {
+	return rf.path
+}
+
+// open opens the active file and records its size and creation time
+func (rf *RotatingFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(rf.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	f, err := os.OpenFile(rf.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	rf.file = f
+	rf.size = info.Size()
+	rf.openedAt = info.ModTime()
+	if rf.size == 0 {
+		rf.openedAt = time.Now()
+	}
+	return nil
+}
+
+// Write appends p, rotating first if the size or age limit would be exceeded
+func (rf *RotatingFile) Write(p []byte) (int, error) {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+
+	if rf.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	tooBig := rf.maxSize > 0 && rf.size > 0 && rf.size+int64(len(p)) > rf.maxSize
+	tooOld := rf.maxAge > 0 && rf.size > 0 && time.Since(rf.openedAt) > rf.maxAge
+	if tooBig || tooOld {
+		if err := rf.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := rf.file.Write(p)
+	rf.size += int64(n)
+	return n, err
+}
+
+// Close closes the active file
+func (rf *RotatingFile) Close() error {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+
+	if rf.file == nil {
+		return nil
+	}
+	err := rf.file.Close()
+	rf.file = nil
+	return err
+}
+
+// rotate renames the active file with a timestamp suffix and starts a new one
+func (rf *RotatingFile) rotate() error {
+	if err := rf.file.Close(); err != nil {
+		return err
+	}
+
+	backup := rf.path + "." + time.Now().Format("20060102-150405.000")
+	if err := os.Rename(rf.path, backup); err != nil {
+		return fmt.Errorf("failed to rotate log file: %w", err)
+	}
+
+	if err := rf.open(); err != nil {
+		return err
+	}
+
+	go func() {
+		if rf.compress {
+			if err := gzipFile(backup); err != nil {
+				fmt.Fprintf(os.Stderr, "failed to compress %s: %v\n", backup, err)
+			}
+		}
+		rf.pruneBackups()
+	}()
+	return nil
+}
+
+// pruneBackups deletes the oldest rotated files beyond maxBackups
+func (rf *RotatingFile) pruneBackups() {
+	if rf.maxBackups <= 0 {
+		return
+	}
+
+	backups, err := filepath.Glob(rf.path + ".*")
+	if err != nil || len(backups) <= rf.maxBackups {
+		return
+	}
+
+	// Timestamp suffixes sort chronologically
+	sort.Strings(backups)
+	for _, old := range backups[:len(backups)-rf.maxBackups] {
+		os.Remove(old)
+	}
+}
+
+// gzipFile compresses path to path.gz and removes the original
+func gzipFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+
+	zw := gzip.NewWriter(dst)
+	zw.Name = filepath.Base(path)
+	if _, err := io.Copy(zw, src); err != nil {
+		zw.Close()
+		dst.Close()
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		dst.Close()
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		return err
+	}
+
+	src.Close()
+	return os.Remove(path)
+}
+
+// ReadLogRecords returns up to limit of the most recent records from a JSON log file
+func ReadLogRecords(path string, limit int) ([]LogRecord, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var records []LogRecord
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var rec LogRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			continue
+		}
+		records = append(records, rec)
+		if limit > 0 && len(records) > limit {
+			records = records[1:]
+		}
+	}
+
+	return records, scanner.Err()
+}
+
+// TailFile returns up to limit of the last lines of a text file
+func TailFile(path string, limit int) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+		if limit > 0 && len(lines) > limit {
+			lines = lines[1:]
+		}
+	}
+
+	return lines, scanner.Err()
+}
+
+// ListJobLogs returns the per-job log files, newest first
+func ListJobLogs() ([]string, error) {
+	dir := JobLogDir()
+	if dir == "" {
+		return nil, nil
+	}
+
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	type logFile struct {
+		path    string
+		modTime time.Time
+	}
+	var files []logFile
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".log") {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, logFile{filepath.Join(dir, e.Name()), info.ModTime()})
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].modTime.After(files[j].modTime)
+	})
+
+	paths := make([]string, len(files))
+	for i, f := range files {
+		paths[i] = f.path
+	}
+	return paths, nil
+}
@@ -1,9 +1,13 @@
 package app
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -36,17 +40,193 @@ func (l LogLevel) String() string {
 	}
 }
 
+// ParseLogLevel converts a level name such as "debug" or "WARN" to a LogLevel
+func ParseLogLevel(name string) (LogLevel, error) {
+	switch strings.ToUpper(strings.TrimSpace(name)) {
+	case "DEBUG":
+		return LevelDebug, nil
+	case "INFO", "":
+		return LevelInfo, nil
+	case "WARN", "WARNING":
+		return LevelWarn, nil
+	case "ERROR":
+		return LevelError, nil
+	case "FATAL":
+		return LevelFatal, nil
+	default:
+		return LevelInfo, fmt.Errorf("unknown log level %q", name)
+	}
+}
+
+// LogFormat selects how log records are encoded
+type LogFormat int
+
+const (
+	FormatText LogFormat = iota
+	FormatJSON
+)
+
+// ParseLogFormat converts "text" or "json" to a LogFormat
+func ParseLogFormat(name string) (LogFormat, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "text", "":
+		return FormatText, nil
+	case "json":
+		return FormatJSON, nil
+	default:
+		return FormatText, fmt.Errorf("unknown log format %q", name)
+	}
+}
+
+// Fields holds structured context attached to log records
+type Fields map[string]interface{}
+
+// Common field names
+const (
+	FieldJobID   = "job_id"
+	FieldMailbox = "mailbox"
+	FieldFolder  = "folder"
+)
+
+// LogRecord is a single structured log entry
+type LogRecord struct {
+	Time    time.Time
+	Level   LogLevel
+	Message string
+	Fields  Fields
+}
+
+// MarshalJSON encodes the record as a flat JSON object
+func (r LogRecord) MarshalJSON() ([]byte, error) {
+	obj := make(map[string]interface{}, len(r.Fields)+3)
+	for k, v := range r.Fields {
+		if err, ok := v.(error); ok {
+			v = err.Error()
+		}
+		obj[k] = v
+	}
+	obj["time"] = r.Time.Format(time.RFC3339Nano)
+	obj["level"] = r.Level.String()
+	obj["msg"] = r.Message
+	return json.Marshal(obj)
+}
+
+// UnmarshalJSON decodes a record written by MarshalJSON
+func (r *LogRecord) UnmarshalJSON(data []byte) error {
+	var obj map[string]interface{}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+
+	if ts, ok := obj["time"].(string); ok {
+		r.Time, _ = time.Parse(time.RFC3339Nano, ts)
+	}
+	if lvl, ok := obj["level"].(string); ok {
+		r.Level, _ = ParseLogLevel(lvl)
+	}
+	r.Message, _ = obj["msg"].(string)
+
+	delete(obj, "time")
+	delete(obj, "level")
+	delete(obj, "msg")
+	if len(obj) > 0 {
+		r.Fields = Fields(obj)
+	}
+	return nil
+}
+
+// encode renders the record in the given format, terminated by a newline
+func (r LogRecord) encode(format LogFormat) []byte {
+	if format == FormatJSON {
+		data, err := json.Marshal(r)
+		if err == nil {
+			return append(data, '\n')
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "[%s] %s: %s", r.Time.Format("2006-01-02 15:04:05"), r.Level.String(), r.Message)
+
+	for _, k := range sortedFieldKeys(r.Fields) {
+		fmt.Fprintf(&sb, " %s=%v", k, r.Fields[k])
+	}
+	sb.WriteByte('\n')
+	return []byte(sb.String())
+}
+
+// LogSink receives every record a logger emits
+type LogSink interface {
+	WriteRecord(record LogRecord) error
+	Close() error
+}
+
+// writerSink encodes records to an io.Writer
+type writerSink struct {
+	w      io.Writer
+	format LogFormat
+}
+
+// WriteRecord encodes and writes a record
+func (ws *writerSink) WriteRecord(record LogRecord) error {
+	if consoleMuted.Load() && ws.console() {
+		return nil
+	}
+	_, err := ws.w.Write(record.encode(ws.format))
+	return err
+}
+
+// Close closes the writer if it is closable
+func (ws *writerSink) Close() error {
+	if c, ok := ws.w.(io.Closer); ok && !ws.console() {
+		return c.Close()
+	}
+	return nil
+}
+
+// console reports whether the sink writes to the terminal
+func (ws *writerSink) console() bool {
+	return ws.w == os.Stdout || ws.w == os.Stderr
+}
+
+// consoleMuted suppresses console sinks while the full-screen interface owns the terminal
+var consoleMuted atomic.Bool
+
+// MuteConsoleLogs stops or resumes log output to stdout and stderr.
+// Records still reach persisted sinks.
+func MuteConsoleLogs(muted bool) {
+	consoleMuted.Store(muted)
+}
+
+// persisted reports whether the sink writes to a rotated log file
+func (ws *writerSink) persisted() bool {
+	_, ok := ws.w.(*RotatingFile)
+	return ok
+}
+
 // Logger provides a simple logging interface
 type Logger struct {
-	level LogLevel
-	out   io.Writer
+	level  LogLevel
+	sinks  []LogSink
+	fields Fields
+	mu     *sync.Mutex
 }
 
-// NewLogger creates a new logger instance
+var (
+	defaultLogMu    sync.RWMutex
+	defaultLogSinks = []LogSink{&writerSink{w: os.Stdout, format: FormatText}}
+)
+
+// NewLogger creates a new logger instance writing to the configured default sinks
 func NewLogger() *Logger {
+	defaultLogMu.RLock()
+	sinks := append([]LogSink(nil), defaultLogSinks...)
+	level := defaultLogLevel
+	defaultLogMu.RUnlock()
+
 	return &Logger{
-		level: LevelInfo,
-		out:   os.Stdout,
+		level: level,
+		sinks: sinks,
+		mu:    &sync.Mutex{},
 	}
 }
 
@@ -55,9 +235,66 @@ func (l *Logger) SetLevel(level LogLevel) {
 	l.level = level
 }
 
-// SetOutput sets the output writer
+// SetOutput replaces all sinks with a single writer using the text format
 func (l *Logger) SetOutput(out io.Writer) {
-	l.out = out
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sinks = []LogSink{&writerSink{w: out, format: FormatText}}
+}
+
+// SetFormat changes the format of every writer sink except persisted log files
+func (l *Logger) SetFormat(format LogFormat) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for i, sink := range l.sinks {
+		if ws, ok := sink.(*writerSink); ok && !ws.persisted() {
+			l.sinks[i] = &writerSink{w: ws.w, format: format}
+		}
+	}
+}
+
+// AddOutput adds another writer that receives every record
+func (l *Logger) AddOutput(out io.Writer, format LogFormat) {
+	l.AddSink(&writerSink{w: out, format: format})
+}
+
+// AddSink adds another sink that receives every record
+func (l *Logger) AddSink(sink LogSink) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sinks = append(l.sinks, sink)
+}
+
+// WithFields returns a logger that adds fields to every record.
+// The new logger shares sinks with its parent.
+func (l *Logger) WithFields(fields Fields) *Logger {
+	merged := make(Fields, len(l.fields)+len(fields))
+	for k, v := range l.fields {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+
+	return &Logger{
+		level:  l.level,
+		sinks:  l.sinks,
+		fields: merged,
+		mu:     l.mu,
+	}
+}
+
+// WithField returns a logger that adds a single field to every record
+func (l *Logger) WithField(key string, value interface{}) *Logger {
+	return l.WithFields(Fields{key: value})
+}
+
+// WithJob returns a logger tagged with the job ID and source mailbox
+func (l *Logger) WithJob(job *TransferJob) *Logger {
+	return l.WithFields(Fields{
+		FieldJobID:   job.ID,
+		FieldMailbox: job.SourceEmail,
+	})
 }
 
 // log writes a log message if the level is sufficient
@@ -66,11 +303,20 @@ func (l *Logger) log(level LogLevel, format string, args ...interface{}) {
 		return
 	}
 
-	timestamp := time.Now().Format("2006-01-02 15:04:05")
-	message := fmt.Sprintf(format, args...)
+	record := LogRecord{
+		Time:    time.Now(),
+		Level:   level,
+		Message: fmt.Sprintf(format, args...),
+		Fields:  l.fields,
+	}
 
-	logLine := fmt.Sprintf("[%s] %s: %s\n", timestamp, level.String(), message)
-	l.out.Write([]byte(logLine))
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, sink := range l.sinks {
+		if err := sink.WriteRecord(record); err != nil {
+			fmt.Fprintf(os.Stderr, "log sink error: %v\n", err)
+		}
+	}
 }
 
 // Debug logs a debug message
@@ -0,0 +1,423 @@
+package app
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LogSinkConfig describes an additional log destination
+type LogSinkConfig struct {
+	Type     string `json:"type"`     // "syslog" or "journald"
+	Network  string `json:"network"`  // syslog transport: "udp", "tcp" or "unix"
+	Address  string `json:"address"`  // host:port or socket path
+	Facility string `json:"facility"` // syslog facility, e.g. "daemon" or "local0"
+	Tag      string `json:"tag"`      // APP-NAME / SYSLOG_IDENTIFIER
+	SDID     string `json:"sd_id"`    // syslog structured data ID, e.g. "imapsync@<your PEN>"
+	Level    string `json:"level"`    // Minimum level for this sink
+}
+
+// Default socket locations
+const (
+	defaultSyslogSocket   = "/dev/log"
+	defaultJournaldSocket = "/run/systemd/journal/socket"
+)
+
+// NewLogSink creates the sink described by the configuration
+func NewLogSink(cfg *LogSinkConfig) (LogSink, error) {
+	level, err := ParseLogLevel(cfg.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	tag := cfg.Tag
+	if tag == "" {
+		tag = filepath.Base(os.Args[0])
+	}
+
+	switch strings.ToLower(cfg.Type) {
+	case "syslog":
+		facility, err := parseSyslogFacility(cfg.Facility)
+		if err != nil {
+			return nil, err
+		}
+		ss, err := NewSyslogSink(cfg.Network, cfg.Address, facility, tag, level)
+		if err != nil {
+			return nil, err
+		}
+		if err := ss.SetStructuredDataID(cfg.SDID); err != nil {
+			ss.Close()
+			return nil, err
+		}
+		return ss, nil
+	case "journald":
+		return NewJournaldSink(cfg.Address, tag, level)
+	default:
+		return nil, fmt.Errorf("unknown log sink type %q", cfg.Type)
+	}
+}
+
+// syslogSeverity maps a log level to an RFC 5424 severity
+func syslogSeverity(level LogLevel) int {
+	switch level {
+	case LevelDebug:
+		return 7 // debug
+	case LevelInfo:
+		return 6 // informational
+	case LevelWarn:
+		return 4 // warning
+	case LevelError:
+		return 3 // error
+	case LevelFatal:
+		return 2 // critical
+	default:
+		return 5 // notice
+	}
+}
+
+// syslogFacilities maps facility names to their RFC 5424 codes
+var syslogFacilities = map[string]int{
+	"kern": 0, "user": 1, "mail": 2, "daemon": 3, "auth": 4, "syslog": 5,
+	"lpr": 6, "news": 7, "uucp": 8, "cron": 9, "authpriv": 10, "ftp": 11,
+	"local0": 16, "local1": 17, "local2": 18, "local3": 19,
+	"local4": 20, "local5": 21, "local6": 22, "local7": 23,
+}
+
+// parseSyslogFacility converts a facility name to its code, defaulting to "user"
+func parseSyslogFacility(name string) (int, error) {
+	if name == "" {
+		return syslogFacilities["user"], nil
+	}
+	facility, ok := syslogFacilities[strings.ToLower(name)]
+	if !ok {
+		return 0, fmt.Errorf("unknown syslog facility %q", name)
+	}
+	return facility, nil
+}
+
+// SyslogSink sends RFC 5424 messages over UDP, TCP or a unix socket
+type SyslogSink struct {
+	network  string
+	address  string
+	facility int
+	tag      string
+	level    LogLevel
+	hostname string
+	sdID     string // Structured data ID; empty appends fields to the message instead
+
+	mu     sync.Mutex
+	conn   net.Conn
+	stream bool // The connection is a byte stream and needs RFC 6587 framing
+}
+
+// NewSyslogSink connects to a syslog server.
+// An empty network means the local unix socket at /dev/log.
+func NewSyslogSink(network, address string, facility int, tag string, level LogLevel) (*SyslogSink, error) {
+	if network == "" {
+		network = "unix"
+	}
+	if address == "" {
+		switch network {
+		case "unix", "unixgram":
+			address = defaultSyslogSocket
+		default:
+			address = "localhost:514"
+		}
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "-"
+	}
+
+	ss := &SyslogSink{
+		network:  network,
+		address:  address,
+		facility: facility,
+		tag:      tag,
+		level:    level,
+		hostname: hostname,
+	}
+
+	if err := ss.connect(); err != nil {
+		return nil, err
+	}
+	return ss, nil
+}
+
+// SetStructuredDataID sends record fields as RFC 5424 structured data under
+// id, which must have the form name@<private enterprise number>. Without an
+// ID the fields are appended to the message text.
+func (ss *SyslogSink) SetStructuredDataID(id string) error {
+	if id != "" {
+		name, pen, ok := strings.Cut(id, "@")
+		if !ok || name == "" || pen == "" || strings.Trim(pen, "0123456789.") != "" || syslogParamName(id) != id {
+			return fmt.Errorf("invalid syslog SD-ID %q, expected name@<private enterprise number>", id)
+		}
+	}
+	ss.sdID = id
+	return nil
+}
+
+// connect (re)opens the connection to the syslog server
+func (ss *SyslogSink) connect() error {
+	if ss.conn != nil {
+		ss.conn.Close()
+		ss.conn = nil
+	}
+
+	var conn net.Conn
+	var err error
+	if ss.network == "unix" {
+		// Local syslog daemons usually listen on a datagram socket
+		conn, err = net.Dial("unixgram", ss.address)
+		if err != nil {
+			conn, err = net.Dial("unix", ss.address)
+		}
+	} else {
+		conn, err = net.DialTimeout(ss.network, ss.address, 5*time.Second)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to connect to syslog %s %s: %w", ss.network, ss.address, err)
+	}
+
+	ss.conn = conn
+	switch conn.RemoteAddr().Network() {
+	case "tcp", "unix":
+		ss.stream = true
+	default:
+		ss.stream = false
+	}
+	return nil
+}
+
+// WriteRecord formats and sends a record, reconnecting once on failure
+func (ss *SyslogSink) WriteRecord(record LogRecord) error {
+	if record.Level < ss.level {
+		return nil
+	}
+
+	msg := ss.format(record)
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	if ss.conn != nil {
+		if err := ss.write(msg); err == nil {
+			return nil
+		}
+	}
+	if err := ss.connect(); err != nil {
+		return err
+	}
+	return ss.write(msg)
+}
+
+// write sends one message. Stream connections, TCP or a unix stream socket,
+// use RFC 6587 octet-counting framing so messages cannot run together.
+// Callers must hold ss.mu.
+func (ss *SyslogSink) write(msg []byte) error {
+	if ss.stream {
+		msg = append([]byte(strconv.Itoa(len(msg))+" "), msg...)
+	}
+	_, err := ss.conn.Write(msg)
+	return err
+}
+
+// format renders an RFC 5424 message. Record fields become structured data
+// when an SD-ID is configured and are appended to the message otherwise.
+func (ss *SyslogSink) format(record LogRecord) []byte {
+	var buf bytes.Buffer
+
+	pri := ss.facility*8 + syslogSeverity(record.Level)
+	fmt.Fprintf(&buf, "<%d>1 %s %s %s %d - ",
+		pri,
+		record.Time.Format(time.RFC3339Nano),
+		syslogHeaderValue(ss.hostname),
+		syslogHeaderValue(ss.tag),
+		os.Getpid())
+
+	if len(record.Fields) == 0 || ss.sdID == "" {
+		buf.WriteString("-")
+	} else {
+		buf.WriteString("[" + ss.sdID)
+		for _, key := range sortedFieldKeys(record.Fields) {
+			fmt.Fprintf(&buf, " %s=\"%s\"", syslogParamName(key), syslogParamValue(fmt.Sprint(record.Fields[key])))
+		}
+		buf.WriteString("]")
+	}
+
+	buf.WriteString(" ")
+	buf.WriteString(record.Message)
+	if ss.sdID == "" {
+		for _, key := range sortedFieldKeys(record.Fields) {
+			fmt.Fprintf(&buf, " %s=%v", key, record.Fields[key])
+		}
+	}
+	return buf.Bytes()
+}
+
+// Close closes the connection
+func (ss *SyslogSink) Close() error {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	if ss.conn == nil {
+		return nil
+	}
+	err := ss.conn.Close()
+	ss.conn = nil
+	return err
+}
+
+// syslogHeaderValue replaces an empty or invalid header field with the nil value
+func syslogHeaderValue(v string) string {
+	v = strings.Map(func(r rune) rune {
+		if r < 33 || r > 126 {
+			return -1
+		}
+		return r
+	}, v)
+	if v == "" {
+		return "-"
+	}
+	return v
+}
+
+// syslogParamName strips characters not allowed in SD-PARAM names
+func syslogParamName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 33 || r > 126 || r == '=' || r == ' ' || r == ']' || r == '"' {
+			return '_'
+		}
+		return r
+	}, name)
+}
+
+// syslogParamValue escapes '"', '\' and ']' as required by RFC 5424
+func syslogParamValue(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`).Replace(v)
+}
+
+// JournaldSink writes records using the systemd journal native protocol
+type JournaldSink struct {
+	tag   string
+	level LogLevel
+
+	mu   sync.Mutex
+	conn *net.UnixConn
+}
+
+// NewJournaldSink connects to the journald socket.
+// An empty path means /run/systemd/journal/socket.
+func NewJournaldSink(path, tag string, level LogLevel) (*JournaldSink, error) {
+	if path == "" {
+		path = defaultJournaldSocket
+	}
+
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to journald at %s: %w", path, err)
+	}
+
+	return &JournaldSink{
+		tag:   tag,
+		level: level,
+		conn:  conn,
+	}, nil
+}
+
+// WriteRecord sends a record as a single journal entry
+func (js *JournaldSink) WriteRecord(record LogRecord) error {
+	if record.Level < js.level {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	writeJournalField(&buf, "MESSAGE", record.Message)
+	writeJournalField(&buf, "PRIORITY", strconv.Itoa(syslogSeverity(record.Level)))
+	writeJournalField(&buf, "SYSLOG_IDENTIFIER", js.tag)
+	for _, key := range sortedFieldKeys(record.Fields) {
+		if name := journalFieldName(key); name != "" {
+			writeJournalField(&buf, name, fmt.Sprint(record.Fields[key]))
+		}
+	}
+
+	js.mu.Lock()
+	defer js.mu.Unlock()
+
+	if js.conn == nil {
+		return os.ErrClosed
+	}
+	_, err := js.conn.Write(buf.Bytes())
+	if isMessageTooLong(err) {
+		// Entries over the datagram size limit are passed as a file descriptor
+		return sendJournalFile(js.conn, buf.Bytes())
+	}
+	return err
+}
+
+// Close closes the journald socket
+func (js *JournaldSink) Close() error {
+	js.mu.Lock()
+	defer js.mu.Unlock()
+
+	if js.conn == nil {
+		return nil
+	}
+	err := js.conn.Close()
+	js.conn = nil
+	return err
+}
+
+// writeJournalField appends a field in the native protocol encoding.
+// Values containing newlines use the binary length-prefixed form.
+func writeJournalField(buf *bytes.Buffer, name, value string) {
+	if !strings.Contains(value, "\n") {
+		buf.WriteString(name + "=" + value + "\n")
+		return
+	}
+
+	buf.WriteString(name + "\n")
+	binary.Write(buf, binary.LittleEndian, uint64(len(value)))
+	buf.WriteString(value + "\n")
+}
+
+// journalFieldName converts a field key to a valid journal field name (A-Z, 0-9, _)
+func journalFieldName(key string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, key)
+
+	// Leading underscores are reserved for trusted fields
+	name = strings.TrimLeft(name, "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		return ""
+	}
+	return name
+}
+
+// sortedFieldKeys returns the field keys in a stable order
+func sortedFieldKeys(fields Fields) []string {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
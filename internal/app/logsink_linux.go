@@ -0,0 +1,45 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+)
+
+// isMessageTooLong reports whether a datagram was rejected for its size
+func isMessageTooLong(err error) bool {
+	return errors.Is(err, syscall.EMSGSIZE) || errors.Is(err, syscall.ENOBUFS)
+}
+
+// sendJournalFile passes an entry too large for one datagram to journald as a
+// file descriptor, the fallback sd_journal_sendv uses. The file is unlinked
+// from /dev/shm before it is sent, so only journald can still read it.
+func sendJournalFile(conn *net.UnixConn, entry []byte) error {
+	f, err := os.CreateTemp("/dev/shm", "imapsync-journal-*")
+	if err != nil {
+		return fmt.Errorf("journald entry of %d bytes: %w", len(entry), err)
+	}
+	defer f.Close()
+	os.Remove(f.Name())
+
+	if _, err := f.Write(entry); err != nil {
+		return fmt.Errorf("journald entry of %d bytes: %w", len(entry), err)
+	}
+	// WriteMsgUnix refuses connected datagram sockets; send on the raw socket
+	raw, err := conn.SyscallConn()
+	if err != nil {
+		return err
+	}
+	rights := syscall.UnixRights(int(f.Fd()))
+	var sendErr error
+	err = raw.Write(func(fd uintptr) bool {
+		sendErr = syscall.Sendmsg(int(fd), nil, rights, nil, 0)
+		return sendErr != syscall.EAGAIN
+	})
+	if err != nil {
+		return err
+	}
+	return sendErr
+}
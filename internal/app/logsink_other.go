@@ -0,0 +1,19 @@
+//go:build !linux
+
+package app
+
+import (
+	"fmt"
+	"net"
+)
+
+// isMessageTooLong reports whether a datagram was rejected for its size.
+// journald only exists on Linux.
+func isMessageTooLong(err error) bool {
+	return false
+}
+
+// sendJournalFile is not supported on this platform
+func sendJournalFile(conn *net.UnixConn, entry []byte) error {
+	return fmt.Errorf("journald entry of %d bytes is too large", len(entry))
+}
@@ -0,0 +1,109 @@
+package app
+
+import (
+	"time"
+)
+
+// JobEventType identifies a job lifecycle event
+type JobEventType string
+
+const (
+	EventJobAdded      JobEventType = "job.added"
+	EventJobStarted    JobEventType = "job.started"
+	EventJobCompleted  JobEventType = "job.completed"
+	EventJobPlanned    JobEventType = "job.planned" // A dry run finished; nothing was copied
+	EventJobFailed     JobEventType = "job.failed"
+	EventJobCancelled  JobEventType = "job.cancelled"
+	EventBatchFinished JobEventType = "batch.finished"
+)
+
+// JobInfo is a point-in-time copy of a transfer job without credentials
+type JobInfo struct {
+	ID               string         `json:"id"`
+	SourceHost       string         `json:"source_host"`
+	SourceEmail      string         `json:"source_email"`
+	DestHost         string         `json:"dest_host"`
+	DestEmail        string         `json:"dest_email"`
+	Archive          string         `json:"archive,omitempty"`
+	DryRun           bool           `json:"dry_run,omitempty"`
+	Status           TransferStatus `json:"status"`
+	Progress         float64        `json:"progress"`
+	Error            string         `json:"error,omitempty"`
+	FailureReason    FailureReason  `json:"failure_reason,omitempty"`
+	BatchID          string         `json:"batch_id,omitempty"`
+	Tags             []string       `json:"tags,omitempty"`
+	StartTime        time.Time      `json:"start_time,omitempty"`
+	EndTime          time.Time      `json:"end_time,omitempty"`
+	BytesTransferred int64          `json:"bytes_transferred"`
+	LogFile          string         `json:"log_file,omitempty"`
+}
+
+// BatchInfo summarizes a finished batch of jobs
+type BatchInfo struct {
+	Total   int                    `json:"total"`
+	Summary map[TransferStatus]int `json:"summary"`
+	Jobs    []JobInfo              `json:"jobs"`
+}
+
+// JobEvent is delivered to notifiers when a job changes state
+type JobEvent struct {
+	Type  JobEventType `json:"event"`
+	Time  time.Time    `json:"timestamp"`
+	Job   *JobInfo     `json:"job,omitempty"`
+	Batch *BatchInfo   `json:"batch,omitempty"`
+}
+
+// Notifier receives job lifecycle events.
+// Notify must not block; slow deliveries belong in a background goroutine.
+type Notifier interface {
+	Notify(event JobEvent)
+	Close() error
+}
+
+// newJobInfo copies the public fields of a job. Callers must hold the manager lock.
+func newJobInfo(job *TransferJob) *JobInfo {
+	info := &JobInfo{
+		ID:               job.ID,
+		SourceHost:       job.SourceHost,
+		SourceEmail:      job.SourceEmail,
+		DestHost:         job.DestHost,
+		DestEmail:        job.DestEmail,
+		DryRun:           job.Options.DryRun,
+		Status:           job.Status,
+		Progress:         job.Progress,
+		StartTime:        job.StartTime,
+		EndTime:          job.EndTime,
+		BytesTransferred: job.BytesTransferred,
+		LogFile:          job.LogFile,
+		FailureReason:    job.FailureReason,
+		BatchID:          job.BatchID,
+		Tags:             job.Tags,
+	}
+	if job.IsArchive() {
+		info.Archive = job.Archive.String()
+	}
+	if job.Error != nil {
+		info.Error = job.Error.Error()
+	}
+	return info
+}
+
+// eventForStatus maps a job status to the event announcing it. A finished dry
+// run is announced as planned, not completed, as it copied nothing.
+func eventForStatus(status TransferStatus, dryRun bool) (JobEventType, bool) {
+	switch status {
+	case StatusRunning:
+		return EventJobStarted, true
+	case StatusCompleted:
+		if dryRun {
+			return EventJobPlanned, true
+		}
+		return EventJobCompleted, true
+	case StatusFailed:
+		return EventJobFailed, true
+	case StatusCancelled:
+		return EventJobCancelled, true
+	default:
+		return "", false
+	}
+}
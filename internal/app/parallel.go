@@ -3,15 +3,18 @@ package app
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"imapsync/internal/i18n"
 	"imapsync/internal/ui"
 )
 
@@ -30,6 +33,34 @@ type TransferJob struct {
 	StartTime        time.Time
 	EndTime          time.Time
 	BytesTransferred int64
+	Folder           string          // Folder imapsync is syncing
+	MessagesDone     int             // Messages handled in the current run, when imapsync reports them
+	MessagesTotal    int             // Messages in the current run
+	LogFile          string          // Captured imapsync output
+	DeltaSchedule    string          // Cron expression for recurring delta syncs after the first run
+	CutoverAt        time.Time       // Delta syncs stop after this time
+	Bandwidth        BandwidthLimit  // Per-job cap on top of the configured caps
+	Weight           int64           // Connection permits the job takes, e.g. 2 for a very large mailbox; 0 means 1
+	FailureReason    FailureReason   // Why the job failed, set when Status is failed
+	Priority         int             // Higher priorities start first, e.g. for VIP mailboxes
+	Tags             []string        // Free-form labels for filtering
+	BatchID          string          // Groups jobs that are started, cancelled and summarized together
+	DependsOn        []string        // IDs of jobs that must complete before this one starts
+	Attempts         []JobAttempt    // Every imapsync run, oldest first
+	MaxAttempts      int             // Cap on failed runs since the last success; 0 means DefaultMaxJobAttempts
+	Options          TransferOptions // SSL, dry run and folder filters
+	Filter           MessageFilter   // Messages to copy by age, date, size, flags and headers
+	Plan             *TransferPlan   // What the last dry run would have done
+	Archive          ArchiveTarget   // Export the source mailbox to local files instead of DestHost
+
+	cancel           context.CancelFunc
+	pauseRequested   bool
+	bandwidth        BandwidthLimit     // Caps passed to the running imapsync process
+	restartRun       context.CancelFunc // Stops the current imapsync process only
+	restartRequested bool
+	order            uint64    // Insertion order, used to keep the queue FIFO within a priority
+	retryAt          time.Time // A deferred retry waits in the queue until this time
+	retryAttempts    int       // Attempts made before the retry was deferred
 }
 
 // TransferStatus represents the status of a transfer job
@@ -41,6 +72,8 @@ const (
 	StatusCompleted TransferStatus = "completed"
 	StatusFailed    TransferStatus = "failed"
 	StatusCancelled TransferStatus = "cancelled"
+	StatusPaused    TransferStatus = "paused"
+	StatusWaiting   TransferStatus = "waiting" // Held until the circuit breaker of its server closes or its retry delay passes
 )
 
 // ParallelTransferManager manages parallel transfer operations
@@ -48,9 +81,30 @@ type ParallelTransferManager struct {
 	jobs        map[string]*TransferJob
 	mu          sync.RWMutex
 	perfManager *PerformanceManager
+	hostLimiter *HostLimiter
+	breakers    *BreakerRegistry
+	controller  *ConcurrencyController
+	bandwidth   *BandwidthManager
 	logger      *Logger
-	ctx         context.Context
-	cancel      context.CancelFunc
+	notifiers   []Notifier
+	admitting   bool // New jobs may start; cleared outside maintenance windows
+	running     bool // A batch started by StartAllJobs is in progress
+	nextOrder   uint64
+
+	rebalancePending bool
+	bandwidthStop    chan struct{}
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// Worker pool state, guarded by qmu
+	qmu            sync.Mutex
+	qcond          *sync.Cond
+	queue          *jobQueue
+	batch          *jobBatch
+	workersStarted bool
+	shuttingDown   bool
+	workerWg       sync.WaitGroup
 }
 
 // NewParallelTransferManager creates a new parallel transfer manager
@@ -58,19 +112,35 @@ func NewParallelTransferManager(perfManager *PerformanceManager) *ParallelTransf
 	ctx, cancel := context.WithCancel(context.Background())
 
 	logger := NewLogger()
-	logger.SetLevel(LevelInfo)
+	bandwidth, _ := NewBandwidthManager(nil)
 
-	return &ParallelTransferManager{
+	ptm := &ParallelTransferManager{
 		jobs:        make(map[string]*TransferJob),
 		perfManager: perfManager,
+		hostLimiter: NewHostLimiter(nil),
+		breakers:    NewBreakerRegistry(nil),
+		bandwidth:   bandwidth,
 		logger:      logger,
+		admitting:   true,
 		ctx:         ctx,
 		cancel:      cancel,
+		queue:       newJobQueue(perfManager.config.QueueDepth),
 	}
+	ptm.qcond = sync.NewCond(&ptm.qmu)
+	return ptm
 }
 
-// AddJob adds a new transfer job to the queue
+// AddJob adds a new transfer job. If a batch is running, the job joins it.
 func (ptm *ParallelTransferManager) AddJob(job *TransferJob) error {
+	if err := ptm.registerJob(job); err != nil {
+		return err
+	}
+	ptm.submitToRunningBatch(job)
+	return nil
+}
+
+// registerJob validates a job and stores it as pending
+func (ptm *ParallelTransferManager) registerJob(job *TransferJob) error {
 	ptm.mu.Lock()
 	defer ptm.mu.Unlock()
 
@@ -78,127 +148,477 @@ func (ptm *ParallelTransferManager) AddJob(job *TransferJob) error {
 		job.ID = fmt.Sprintf("job_%d", time.Now().UnixNano())
 	}
 
+	if job.DeltaSchedule != "" {
+		if _, err := ParseCron(job.DeltaSchedule); err != nil {
+			return fmt.Errorf("invalid delta schedule: %w", err)
+		}
+	}
+	if err := job.Filter.Validate(); err != nil {
+		return fmt.Errorf("invalid filter: %w", err)
+	}
+	if job.IsArchive() {
+		if err := job.Archive.Validate(); err != nil {
+			return fmt.Errorf("invalid archive: %w", err)
+		}
+	}
+
+	// Dependencies must already exist, which also rules out cycles
+	for _, dep := range job.DependsOn {
+		if dep == job.ID {
+			return fmt.Errorf("job %s cannot depend on itself", job.ID)
+		}
+		if _, exists := ptm.jobs[dep]; !exists {
+			return fmt.Errorf("dependency %s not found", dep)
+		}
+	}
+
 	job.Status = StatusPending
+	ptm.nextOrder++
+	job.order = ptm.nextOrder
 	ptm.jobs[job.ID] = job
 
-	ptm.logger.Info("Added transfer job: %s (%s -> %s)", job.ID, job.SourceEmail, job.DestEmail)
+	ptm.logger.Info("Added transfer job: %s (%s -> %s)", job.ID, job.SourceEmail, job.Destination())
+	ptm.notify(EventJobAdded, job)
 	return nil
 }
 
-// StartAllJobs starts all pending jobs in parallel
-func (ptm *ParallelTransferManager) StartAllJobs() {
+// SetHostLimits replaces the per-host connection limits.
+// It should be called before the first batch starts.
+func (ptm *ParallelTransferManager) SetHostLimits(config *HostLimitsConfig) {
+	ptm.qmu.Lock()
+	defer ptm.qmu.Unlock()
+
+	ptm.hostLimiter = NewHostLimiter(config)
+}
+
+// SetAdaptiveConcurrency starts an adaptive concurrency controller when enabled.
+// It should be called before the first batch starts.
+func (ptm *ParallelTransferManager) SetAdaptiveConcurrency(config *AdaptiveConcurrencyConfig) {
+	if config == nil || !config.Enabled {
+		return
+	}
+
+	controller := NewConcurrencyController(ptm.perfManager, config)
+	controller.Start()
+
+	ptm.mu.Lock()
+	previous := ptm.controller
+	ptm.controller = controller
+	ptm.mu.Unlock()
+
+	if previous != nil {
+		previous.Stop()
+	}
+}
+
+// AddNotifier registers a notifier for job lifecycle events
+func (ptm *ParallelTransferManager) AddNotifier(n Notifier) {
+	ptm.mu.Lock()
+	defer ptm.mu.Unlock()
+
+	ptm.notifiers = append(ptm.notifiers, n)
+}
+
+// notify sends a job event to all notifiers. Callers must hold ptm.mu.
+func (ptm *ParallelTransferManager) notify(eventType JobEventType, job *TransferJob) {
+	if len(ptm.notifiers) == 0 {
+		return
+	}
+
+	event := JobEvent{
+		Type: eventType,
+		Time: time.Now(),
+		Job:  newJobInfo(job),
+	}
+	for _, n := range ptm.notifiers {
+		n.Notify(event)
+	}
+}
+
+// notifyBatchFinished sends a batch summary for the given jobs to all notifiers
+func (ptm *ParallelTransferManager) notifyBatchFinished(jobs []*TransferJob) {
 	ptm.mu.RLock()
+	defer ptm.mu.RUnlock()
+
+	if len(ptm.notifiers) == 0 {
+		return
+	}
+
+	batch := &BatchInfo{
+		Total:   len(jobs),
+		Summary: make(map[TransferStatus]int),
+	}
+	for _, job := range jobs {
+		batch.Summary[job.Status]++
+		batch.Jobs = append(batch.Jobs, *newJobInfo(job))
+	}
+
+	event := JobEvent{
+		Type:  EventBatchFinished,
+		Time:  time.Now(),
+		Batch: batch,
+	}
+	for _, n := range ptm.notifiers {
+		n.Notify(event)
+	}
+}
+
+// Close drains the worker pool, flushes pending notifications and releases notifier resources
+func (ptm *ParallelTransferManager) Close() {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultShutdownTimeout)
+	ptm.Shutdown(ctx)
+	cancel()
+
+	ptm.mu.Lock()
+	notifiers := ptm.notifiers
+	ptm.notifiers = nil
+	controller := ptm.controller
+	if ptm.bandwidthStop != nil {
+		close(ptm.bandwidthStop)
+		ptm.bandwidthStop = nil
+	}
+	ptm.mu.Unlock()
+
+	if controller != nil {
+		controller.Stop()
+	}
+
+	for _, n := range notifiers {
+		if err := n.Close(); err != nil {
+			ptm.logger.Error("Failed to close notifier: %v", err)
+		}
+	}
+}
+
+// StartAllJobs queues all pending jobs for the worker pool and waits until they finish.
+// Jobs added while the batch runs are picked up by the same batch.
+func (ptm *ParallelTransferManager) StartAllJobs() {
+	ptm.runJobs(func(*TransferJob) bool { return true })
+}
+
+// runJobs queues the pending jobs accepted by selected, plus the pending jobs
+// they depend on, and waits until they finish
+func (ptm *ParallelTransferManager) runJobs(selected func(*TransferJob) bool) {
+	ptm.mu.Lock()
+	if ptm.running {
+		ptm.mu.Unlock()
+		ptm.logger.Warn("A batch is already running")
+		return
+	}
+	ptm.running = true
+
 	var pendingJobs []*TransferJob
 	for _, job := range ptm.jobs {
-		if job.Status == StatusPending {
+		if job.Status == StatusPending && selected(job) {
 			pendingJobs = append(pendingJobs, job)
 		}
 	}
-	ptm.mu.RUnlock()
+	pendingJobs = ptm.withPendingDependencies(pendingJobs)
 
+	// Queue high priorities first in case the queue is too small for all jobs,
+	// but never ahead of the jobs they depend on
+	sort.Slice(pendingJobs, func(i, j int) bool {
+		if pendingJobs[i].Priority != pendingJobs[j].Priority {
+			return pendingJobs[i].Priority > pendingJobs[j].Priority
+		}
+		return pendingJobs[i].order < pendingJobs[j].order
+	})
+	pendingJobs = dependencyOrder(pendingJobs)
+	ptm.mu.Unlock()
+
+	defer func() {
+		ptm.mu.Lock()
+		ptm.running = false
+		ptm.mu.Unlock()
+	}()
+
+	if len(pendingJobs) == 0 {
+		ptm.logger.Info("No pending transfer jobs")
+		return
+	}
+
+	ptm.qmu.Lock()
+	if ptm.shuttingDown {
+		ptm.qmu.Unlock()
+		ptm.logger.Warn("Not starting batch: %v", ErrShuttingDown)
+		return
+	}
+	batch := newJobBatch(pendingJobs)
+	ptm.batch = batch
+	ptm.qmu.Unlock()
+
+	ptm.startWorkers()
 	ptm.logger.Info("Starting %d transfer jobs in parallel", len(pendingJobs))
 
-	var wg sync.WaitGroup
-	for _, job := range pendingJobs {
-		wg.Add(1)
-		go func(j *TransferJob) {
-			defer wg.Done()
-			ptm.executeJob(j)
-		}(job)
+	for i, job := range pendingJobs {
+		if err := ptm.enqueue(job, batch); err != nil {
+			// enqueue released this job; the remaining ones were never queued
+			ptm.logger.Warn("Stopped queueing jobs: %v", err)
+			ptm.qmu.Lock()
+			for range pendingJobs[i+1:] {
+				ptm.finishQueued(batch)
+			}
+			ptm.qmu.Unlock()
+			break
+		}
 	}
 
-	wg.Wait()
+	<-batch.done
 	ptm.logger.Info("All transfer jobs completed")
+
+	ptm.qmu.Lock()
+	jobs := batch.jobs
+	ptm.qmu.Unlock()
+	ptm.notifyBatchFinished(jobs)
 }
 
 // executeJob executes a single transfer job
 func (ptm *ParallelTransferManager) executeJob(job *TransferJob) {
-	// Acquire connection from pool
-	if err := ptm.perfManager.AcquireConnection(ptm.ctx); err != nil {
+	// Acquire connections from pool; large mailboxes count as more than one
+	weight := job.Weight
+	if weight <= 0 {
+		weight = 1
+	}
+	if err := ptm.perfManager.AcquireConnections(ptm.ctx, weight); err != nil {
 		ptm.updateJobStatus(job, StatusFailed, err)
 		return
 	}
-	defer ptm.perfManager.ReleaseConnection()
+	defer ptm.perfManager.ReleaseConnections(weight)
 
-	ptm.updateJobStatus(job, StatusRunning, nil)
+	// Admission may have closed while waiting for connections, e.g. at the end of
+	// a maintenance window; the job stays pending and the worker requeues it
+	ptm.mu.Lock()
+	if !ptm.admitting || job.Status != StatusPending {
+		ptm.mu.Unlock()
+		return
+	}
+	jobCtx, cancel := context.WithCancel(ptm.ctx)
+	job.cancel = cancel
+	job.pauseRequested = false
+	job.Folder = ""
+	job.MessagesDone, job.MessagesTotal = 0, 0
+	attempts := job.retryAttempts
+	job.retryAt, job.retryAttempts = time.Time{}, 0
 	job.StartTime = time.Now()
+	ptm.mu.Unlock()
+	defer cancel()
 
-	// Execute transfer with retry logic
-	err := ptm.perfManager.RetryWithBackoff(ptm.ctx, func() error {
-		return ptm.runImapsync(job)
+	ptm.updateJobStatus(job, StatusRunning, nil)
+
+	ptm.bandwidth.Register(job)
+	ptm.scheduleRebalance()
+	defer func() {
+		ptm.bandwidth.Unregister(job)
+		ptm.scheduleRebalance()
+	}()
+
+	// Archive jobs fetch the mailbox themselves instead of running imapsync
+	run := ptm.runImapsync
+	if job.IsArchive() {
+		run = ptm.runArchive
+	}
+
+	// Execute transfer with retry logic; every run reports to the host's circuit breaker.
+	// Retry delays are waited out in the queue, not here.
+	err := ptm.perfManager.RetryJob(jobCtx, attempts, func() error {
+		if !ptm.canAttempt(job) {
+			return ErrAttemptsExhausted
+		}
+		if !ptm.breakers.Allow(job.SourceHost, job.DestHost) {
+			return ErrCircuitOpen
+		}
+
+		start := time.Now()
+		err := run(jobCtx, job)
+		interrupted := jobCtx.Err() != nil
+		if interrupted {
+			ptm.breakers.EndTrial(job.SourceHost, job.DestHost)
+		} else {
+			ptm.recordConnectionResult(job, err)
+		}
+		if ptm.recordAttempt(job, start, err, interrupted) {
+			return fmt.Errorf("%w: %w", ErrAttemptsExhausted, err)
+		}
+		return err
 	})
 
+	ptm.mu.Lock()
 	job.EndTime = time.Now()
-
-	if err != nil {
+	paused := job.pauseRequested
+	cancelled := job.Status == StatusCancelled
+	ptm.mu.Unlock()
+
+	var te *TransferError
+	connectionFailure := errors.As(err, &te) && isConnectionFailure(te.Reason)
+	var deferred *RetryDeferredError
+
+	if err != nil && paused {
+		ptm.updateJobStatus(job, StatusPaused, nil)
+	} else if errors.As(err, &deferred) && !cancelled && ptm.ctx.Err() == nil {
+		ptm.deferRetry(job, deferred)
+	} else if errors.Is(err, ErrCircuitOpen) || (connectionFailure && ptm.breakerBlocks(job)) {
+		ptm.updateJobStatus(job, StatusWaiting, err)
+	} else if err != nil && (cancelled || ptm.ctx.Err() != nil) {
+		ptm.updateJobStatus(job, StatusCancelled, err)
+		ptm.updateStats(job, false)
+	} else if err != nil {
 		ptm.updateJobStatus(job, StatusFailed, err)
-		ptm.perfManager.UpdateStats(false, job.BytesTransferred)
+		ptm.updateStats(job, false)
 	} else {
 		ptm.updateJobStatus(job, StatusCompleted, nil)
-		ptm.perfManager.UpdateStats(true, job.BytesTransferred)
-	}
-}
-
-// runImapsync runs the actual imapsync command for a job
-func (ptm *ParallelTransferManager) runImapsync(job *TransferJob) error {
-	// This is a simplified version - in real implementation, you'd parse imapsync output
-	// and update progress in real-time
-
-	args := []string{
-		"--host1", job.SourceHost, "--ssl1",
-		"--user1", job.SourceEmail, "--password1", job.SourcePass,
-		"--host2", job.DestHost, "--ssl2",
-		"--user2", job.DestEmail, "--password2", job.DestPass,
-		"--exclude", "^Junk\\ E-Mail",
-		"--exclude", "^Deleted\\ Items",
-		"--exclude", "^Deleted",
-		"--exclude", "^Trash",
-		"--regextrans2", "s#^Sent$#Sent Items#",
-		"--regextrans2", "s#^Spam$#Junk E-Mail#",
-		"--useuid",
-		"--usecache",
-		"--tmpdir", fmt.Sprintf("./tmp_%s", job.ID),
-		"--syncinternaldates",
-		"--progress",
-	}
-
-	// Execute imapsync command
-	cmd := exec.Command("imapsync", args...)
-
-	// Set up output parsing for progress updates
-	stdout, err := cmd.StdoutPipe()
+		ptm.updateStats(job, true)
+	}
+}
+
+// updateStats records the outcome of a job in the statistics. Dry runs copy
+// nothing and are left out.
+func (ptm *ParallelTransferManager) updateStats(job *TransferJob, success bool) {
+	if !job.Options.DryRun {
+		ptm.perfManager.UpdateStats(success, job.BytesTransferred)
+	}
+}
+
+// folderRe matches the per-folder header imapsync prints, e.g. "Folder    2/12 [INBOX]"
+var folderRe = regexp.MustCompile(`Folder\s+\d+/\d+\s+\[([^\]]+)\]`)
+
+// messagesLeftRe matches the --progress counter, e.g. "12/340 msgs left"
+var messagesLeftRe = regexp.MustCompile(`(\d+)/(\d+) msgs left`)
+
+// runImapsync runs imapsync for a job. When the job's bandwidth share changes
+// enough, the process is stopped and restarted with the new caps; imapsync
+// resumes from its cache.
+func (ptm *ParallelTransferManager) runImapsync(ctx context.Context, job *TransferJob) error {
+	for {
+		limit := ptm.allocateBandwidth(job)
+		runCtx, cancel := context.WithCancel(ctx)
+
+		ptm.mu.Lock()
+		job.bandwidth = limit
+		job.restartRun = cancel
+		job.restartRequested = false
+		ptm.mu.Unlock()
+
+		err := ptm.runImapsyncProcess(runCtx, job, imapsyncArgs(job, fmt.Sprintf("./tmp_%s", job.ID), limit))
+		cancel()
+
+		ptm.mu.Lock()
+		restart := job.restartRequested && ctx.Err() == nil
+		job.restartRun = nil
+		ptm.mu.Unlock()
+
+		if !restart {
+			return err
+		}
+		ptm.logger.WithJob(job).Info("Restarting job %s with new bandwidth limit", job.ID)
+	}
+}
+
+// runImapsyncProcess runs a single imapsync process and parses its output
+func (ptm *ParallelTransferManager) runImapsyncProcess(ctx context.Context, job *TransferJob, args []string) error {
+	logger := ptm.logger.WithJob(job)
+
+	// Capture the full imapsync output in the job's log file
+	jobLog, err := openJobLog(job.ID)
+	if err != nil {
+		logger.Warn("Failed to open job log: %v", err)
+	}
+	if jobLog != nil {
+		defer jobLog.Close()
+		ptm.mu.Lock()
+		job.LogFile = jobLog.Path()
+		ptm.mu.Unlock()
+		fmt.Fprintf(jobLog, "=== %s imapsync started (bandwidth: %s) ===\n", time.Now().Format("2006-01-02 15:04:05"), job.bandwidth)
+	}
+
+	// Execute imapsync command. Standard output and error share one pipe so
+	// errors reach the failure detector and the job log in the order printed.
+	cmd := exec.CommandContext(ctx, "imapsync", args...)
+	output, outputWriter, err := os.Pipe()
 	if err != nil {
-		return fmt.Errorf("failed to create stdout pipe: %w", err)
+		return fmt.Errorf("failed to create output pipe: %w", err)
 	}
+	defer output.Close()
+	cmd.Stdout = outputWriter
+	cmd.Stderr = outputWriter
 
-	if err := cmd.Start(); err != nil {
+	err = cmd.Start()
+	// The child holds its own copy; reads end when it exits
+	outputWriter.Close()
+	if err != nil {
 		return fmt.Errorf("failed to start imapsync: %w", err)
 	}
 
-	// Parse output for progress updates
-	scanner := bufio.NewScanner(stdout)
-	percentRe := regexp.MustCompile(`([0-9]{1,3}(?:\.[0-9]+)?)%`)
+	ptm.mu.RLock()
+	controller := ptm.controller
+	ptm.mu.RUnlock()
+
+	// Parse output for progress updates and failure hints; a dry run also
+	// collects the plan
+	var detector failureDetector
+	var plan *planParser
+	if job.Options.DryRun {
+		plan = &planParser{}
+		defer ptm.setJobPlan(job, args, plan)
+	}
+	scanner := bufio.NewScanner(output)
+	lastCopy := time.Now()
 
 	for scanner.Scan() {
 		line := scanner.Text()
+		if jobLog != nil {
+			jobLog.Write([]byte(line + "\n"))
+		}
+		if plan != nil {
+			plan.Observe(line)
+		}
 
 		if m := percentRe.FindStringSubmatch(line); len(m) == 2 {
 			if p, err := strconv.ParseFloat(m[1], 64); err == nil {
 				ptm.updateJobProgress(job, p)
 			}
 		}
+		if m := folderRe.FindStringSubmatch(line); len(m) == 2 {
+			logger.WithField(FieldFolder, m[1]).Debug("Syncing folder")
+			ptm.setJobFolder(job, m[1])
+		}
+		if m := messagesLeftRe.FindStringSubmatch(line); len(m) == 3 {
+			left, _ := strconv.Atoi(m[1])
+			total, _ := strconv.Atoi(m[2])
+			ptm.updateJobMessages(job, total-left, total)
+		}
+		if m := copiedRe.FindStringSubmatch(line); len(m) == 2 {
+			size, _ := strconv.ParseInt(m[1], 10, 64)
+			ptm.addJobBytes(job, size)
+			if controller != nil {
+				now := time.Now()
+				controller.RecordMessage(size, now.Sub(lastCopy))
+				lastCopy = now
+			}
+		}
+		if controller != nil {
+			controller.ObserveLine(line)
+		}
+		detector.Observe(line)
 
 		// Check if context is cancelled
 		select {
-		case <-ptm.ctx.Done():
+		case <-ctx.Done():
 			cmd.Process.Kill()
-			return ptm.ctx.Err()
+			cmd.Wait()
+			return ctx.Err()
 		default:
 		}
 	}
 
 	if err := cmd.Wait(); err != nil {
-		return fmt.Errorf("imapsync failed: %w", err)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		te := detector.Classify(err)
+		if jobLog != nil {
+			fmt.Fprintf(jobLog, "=== %s %v ===\n", time.Now().Format("2006-01-02 15:04:05"), te)
+		}
+		return te
 	}
 
 	return nil
@@ -209,13 +629,55 @@ func (ptm *ParallelTransferManager) updateJobStatus(job *TransferJob, status Tra
 	ptm.mu.Lock()
 	defer ptm.mu.Unlock()
 
+	changed := job.Status != status
 	job.Status = status
 	job.Error = err
+	job.FailureReason = FailureNone
+	if status == StatusFailed {
+		job.FailureReason = ClassifyError(err)
+	}
 
-	ptm.logger.Info("Job %s status: %s", job.ID, status)
+	logger := ptm.logger.WithJob(job)
+	logger.Info("Job %s status: %s", job.ID, status)
 	if err != nil {
-		ptm.logger.Error("Job %s error: %v", job.ID, err)
+		logger.Error("Job %s error: %v", job.ID, err)
 	}
+
+	if eventType, ok := eventForStatus(status, job.Options.DryRun); ok && changed {
+		ptm.notify(eventType, job)
+	}
+}
+
+// setJobPlan stores the plan of a dry run of a job
+func (ptm *ParallelTransferManager) setJobPlan(job *TransferJob, args []string, parser *planParser) {
+	plan := &TransferPlan{
+		JobID:       job.ID,
+		Source:      job.SourceEmail,
+		Destination: job.Destination(),
+		FoldersOnly: job.Options.JustFolders,
+		Filter:      job.Filter.String(),
+		Folders:     parser.Plan(),
+		Excludes:    excludeArgs(args),
+		CreatedAt:   time.Now(),
+	}
+	for _, f := range plan.Folders {
+		plan.Messages += f.Messages
+		plan.Bytes += f.Bytes
+	}
+
+	ptm.mu.Lock()
+	job.Plan = plan
+	ptm.mu.Unlock()
+}
+
+// fractionDone returns how much of the current run is done, from 0 to 1.
+// Message counts are more precise than the percentage when imapsync reports
+// them. Callers must hold ptm.mu.
+func (job *TransferJob) fractionDone() float64 {
+	if job.MessagesTotal > 0 {
+		return float64(job.MessagesDone) / float64(job.MessagesTotal)
+	}
+	return job.Progress / 100
 }
 
 // updateJobProgress updates the progress of a job
@@ -226,6 +688,31 @@ func (ptm *ParallelTransferManager) updateJobProgress(job *TransferJob, progress
 	job.Progress = progress
 }
 
+// setJobFolder records the folder a job is syncing
+func (ptm *ParallelTransferManager) setJobFolder(job *TransferJob, folder string) {
+	ptm.mu.Lock()
+	defer ptm.mu.Unlock()
+
+	job.Folder = folder
+}
+
+// updateJobMessages records how many messages of the run are done
+func (ptm *ParallelTransferManager) updateJobMessages(job *TransferJob, done, total int) {
+	ptm.mu.Lock()
+	defer ptm.mu.Unlock()
+
+	job.MessagesDone = done
+	job.MessagesTotal = total
+}
+
+// addJobBytes adds copied message bytes to a job
+func (ptm *ParallelTransferManager) addJobBytes(job *TransferJob, size int64) {
+	ptm.mu.Lock()
+	defer ptm.mu.Unlock()
+
+	job.BytesTransferred += size
+}
+
 // GetJobStatus returns the status of a specific job
 func (ptm *ParallelTransferManager) GetJobStatus(jobID string) (*TransferJob, bool) {
 	ptm.mu.RLock()
@@ -247,6 +734,69 @@ func (ptm *ParallelTransferManager) GetAllJobs() map[string]*TransferJob {
 	return result
 }
 
+// JobProgress is a point-in-time view of a job for live displays
+type JobProgress struct {
+	ID               string
+	SourceEmail      string
+	DestEmail        string
+	Status           TransferStatus
+	Progress         float64
+	Folder           string
+	MessagesDone     int
+	MessagesTotal    int
+	BytesTransferred int64
+	Speed            float64       // Average bytes per second since the job last started
+	ETA              time.Duration // Estimated from progress; 0 when unknown
+	BatchID          string
+	LogFile          string
+}
+
+// ProgressSnapshot returns the progress of every job in the order they were added
+func (ptm *ParallelTransferManager) ProgressSnapshot() []JobProgress {
+	ptm.mu.RLock()
+	defer ptm.mu.RUnlock()
+
+	jobs := make([]*TransferJob, 0, len(ptm.jobs))
+	for _, job := range ptm.jobs {
+		jobs = append(jobs, job)
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].order < jobs[j].order })
+
+	now := time.Now()
+	snapshot := make([]JobProgress, len(jobs))
+	for i, job := range jobs {
+		p := JobProgress{
+			ID:               job.ID,
+			SourceEmail:      job.SourceEmail,
+			DestEmail:        job.Destination(),
+			Status:           job.Status,
+			Progress:         job.Progress,
+			Folder:           job.Folder,
+			MessagesDone:     job.MessagesDone,
+			MessagesTotal:    job.MessagesTotal,
+			BytesTransferred: job.BytesTransferred,
+			BatchID:          job.BatchID,
+			LogFile:          job.LogFile,
+		}
+
+		if !job.StartTime.IsZero() {
+			end := now
+			if job.Status != StatusRunning && job.EndTime.After(job.StartTime) {
+				end = job.EndTime
+			}
+			done := job.fractionDone()
+			if elapsed := end.Sub(job.StartTime); elapsed > 0 {
+				p.Speed = float64(job.BytesTransferred) / elapsed.Seconds()
+				if job.Status == StatusRunning && done > 0 && done < 1 {
+					p.ETA = time.Duration(float64(elapsed) * (1 - done) / done)
+				}
+			}
+		}
+		snapshot[i] = p
+	}
+	return snapshot
+}
+
 // CancelJob cancels a specific job
 func (ptm *ParallelTransferManager) CancelJob(jobID string) error {
 	ptm.mu.Lock()
@@ -257,9 +807,13 @@ func (ptm *ParallelTransferManager) CancelJob(jobID string) error {
 		return fmt.Errorf("job %s not found", jobID)
 	}
 
-	if job.Status == StatusRunning {
+	if job.Status == StatusRunning || job.Status == StatusWaiting {
 		job.Status = StatusCancelled
+		if job.cancel != nil {
+			job.cancel()
+		}
 		ptm.logger.Info("Cancelled job: %s", jobID)
+		ptm.notify(EventJobCancelled, job)
 	}
 
 	return nil
@@ -273,14 +827,152 @@ func (ptm *ParallelTransferManager) CancelAllJobs() {
 	defer ptm.mu.Unlock()
 
 	for _, job := range ptm.jobs {
-		if job.Status == StatusRunning {
+		if job.Status == StatusRunning || job.Status == StatusWaiting {
 			job.Status = StatusCancelled
+			ptm.notify(EventJobCancelled, job)
 		}
 	}
 
 	ptm.logger.Info("Cancelled all running jobs")
 }
 
+// SetAdmission allows or blocks new jobs from starting; running jobs are not affected
+func (ptm *ParallelTransferManager) SetAdmission(open bool) {
+	ptm.mu.Lock()
+	ptm.admitting = open
+	ptm.mu.Unlock()
+
+	if !open {
+		ptm.logger.Info("Job admission closed")
+		return
+	}
+	ptm.logger.Info("Job admission opened")
+
+	// Wake workers holding queued jobs back while admission was closed
+	ptm.qmu.Lock()
+	ptm.qcond.Broadcast()
+	ptm.qmu.Unlock()
+}
+
+// Admitting reports whether new jobs may start
+func (ptm *ParallelTransferManager) Admitting() bool {
+	ptm.mu.RLock()
+	defer ptm.mu.RUnlock()
+	return ptm.admitting
+}
+
+// IsBatchRunning reports whether StartAllJobs is in progress
+func (ptm *ParallelTransferManager) IsBatchRunning() bool {
+	ptm.mu.RLock()
+	defer ptm.mu.RUnlock()
+	return ptm.running
+}
+
+// PauseRunningJobs stops the imapsync process of every running job and marks it paused.
+// imapsync resumes from its cache when the job runs again.
+func (ptm *ParallelTransferManager) PauseRunningJobs() int {
+	ptm.mu.Lock()
+	defer ptm.mu.Unlock()
+
+	paused := 0
+	for _, job := range ptm.jobs {
+		if job.Status == StatusRunning && job.cancel != nil {
+			job.pauseRequested = true
+			job.cancel()
+			paused++
+		}
+	}
+
+	if paused > 0 {
+		ptm.logger.Info("Pausing %d running jobs", paused)
+	}
+	return paused
+}
+
+// PauseJob stops the imapsync process of a running job and marks it paused
+func (ptm *ParallelTransferManager) PauseJob(jobID string) error {
+	ptm.mu.Lock()
+	defer ptm.mu.Unlock()
+
+	job, exists := ptm.jobs[jobID]
+	if !exists {
+		return fmt.Errorf("job %s not found", jobID)
+	}
+	if job.Status != StatusRunning || job.cancel == nil {
+		return fmt.Errorf("job %s is not running", jobID)
+	}
+
+	job.pauseRequested = true
+	job.cancel()
+	ptm.logger.WithJob(job).Info("Pausing job: %s", jobID)
+	return nil
+}
+
+// ResumeJob moves a paused job back to pending. A running batch picks it up
+// immediately; otherwise it runs with the next batch.
+func (ptm *ParallelTransferManager) ResumeJob(jobID string) error {
+	ptm.mu.Lock()
+	job, exists := ptm.jobs[jobID]
+	if !exists {
+		ptm.mu.Unlock()
+		return fmt.Errorf("job %s not found", jobID)
+	}
+	if job.Status != StatusPaused {
+		ptm.mu.Unlock()
+		return fmt.Errorf("job %s is not paused", jobID)
+	}
+
+	job.Status = StatusPending
+	ptm.logger.WithJob(job).Info("Resumed job: %s", jobID)
+	ptm.mu.Unlock()
+
+	ptm.submitToRunningBatch(job)
+	return nil
+}
+
+// ResumePausedJobs moves paused jobs back to pending. A running batch picks
+// them up immediately, otherwise the next batch does.
+func (ptm *ParallelTransferManager) ResumePausedJobs() int {
+	ptm.mu.Lock()
+	var resumed []*TransferJob
+	for _, job := range ptm.jobs {
+		if job.Status == StatusPaused {
+			job.Status = StatusPending
+			resumed = append(resumed, job)
+		}
+	}
+	ptm.mu.Unlock()
+
+	for _, job := range resumed {
+		ptm.submitToRunningBatch(job)
+	}
+	return len(resumed)
+}
+
+// RequeueJob moves a finished job back to pending, e.g. for a delta sync.
+// A running batch picks it up immediately.
+func (ptm *ParallelTransferManager) RequeueJob(jobID string) error {
+	ptm.mu.Lock()
+	job, exists := ptm.jobs[jobID]
+	if !exists {
+		ptm.mu.Unlock()
+		return fmt.Errorf("job %s not found", jobID)
+	}
+	if job.Status == StatusRunning {
+		ptm.mu.Unlock()
+		return fmt.Errorf("job %s is running", jobID)
+	}
+
+	job.Status = StatusPending
+	job.Progress = 0
+	job.Error = nil
+	ptm.logger.WithJob(job).Info("Requeued job: %s", jobID)
+	ptm.mu.Unlock()
+
+	ptm.submitToRunningBatch(job)
+	return nil
+}
+
 // GetJobSummary returns a summary of all jobs
 func (ptm *ParallelTransferManager) GetJobSummary() map[TransferStatus]int {
 	ptm.mu.RLock()
@@ -298,39 +990,66 @@ func (ptm *ParallelTransferManager) GetJobSummary() map[TransferStatus]int {
 func (ptm *ParallelTransferManager) PrintJobSummary() {
 	summary := ptm.GetJobSummary()
 
-	fmt.Printf("\n=== Transfer Job Summary ===\n")
-	fmt.Printf("Pending: %d\n", summary[StatusPending])
-	fmt.Printf("Running: %d\n", summary[StatusRunning])
-	fmt.Printf("Completed: %d\n", summary[StatusCompleted])
-	fmt.Printf("Failed: %d\n", summary[StatusFailed])
-	fmt.Printf("Cancelled: %d\n", summary[StatusCancelled])
+	fmt.Printf("\n=== %s ===\n", i18n.T("summary.heading"))
+	fmt.Print(summaryLines(summary))
 
 	total := 0
 	for _, count := range summary {
 		total += count
 	}
-	fmt.Printf("Total: %d\n", total)
+	fmt.Println(i18n.T("summary.line", i18n.T("summary.total"), total))
+
+	for _, batchID := range ptm.BatchIDs() {
+		fmt.Println(i18n.T("summary.batch", batchID, formatSummary(ptm.GetBatchSummary(batchID))))
+	}
 }
 
 // ParallelTransfer handles multiple transfer jobs in parallel
 func ParallelTransfer() {
-	fmt.Println(ui.Cyan("=== Parallel Transfer Manager ==="))
+	fmt.Println(ui.Cyan("=== " + i18n.T("menu.parallel.title") + " ==="))
 
 	// Initialize managers
 	perfManager := NewPerformanceManager(nil)
+	perfManager.SetRetryPolicies(CurrentConfig().Retry)
+	if err := perfManager.ConfigureCache(&CurrentConfig().Cache); err != nil {
+		fmt.Println(ui.Yellow(i18n.T("error.cache_not_loaded")), err)
+	}
+	defer perfManager.Close()
 	parallelManager := NewParallelTransferManager(perfManager)
+	parallelManager.SetHostLimits(&CurrentConfig().HostLimits)
+	parallelManager.SetAdaptiveConcurrency(&CurrentConfig().AdaptiveConcurrency)
+	parallelManager.SetCircuitBreaker(&CurrentConfig().CircuitBreaker)
+	if err := parallelManager.SetBandwidthLimits(&CurrentConfig().Bandwidth); err != nil {
+		fmt.Println(ui.Red(i18n.T("error.bandwidth_disabled")), err)
+	}
+	for _, n := range CurrentConfig().Notifiers() {
+		parallelManager.AddNotifier(n)
+	}
+	defer parallelManager.Close()
 
-	reader := bufio.NewReader(os.Stdin)
+	scheduler, err := NewScheduler(parallelManager, &CurrentConfig().Scheduler)
+	if err != nil {
+		fmt.Println(ui.Red(i18n.T("error.scheduler_disabled")), err)
+	} else {
+		defer scheduler.Stop()
+	}
+
+	reader := ui.Stdin()
 
 	for {
-		fmt.Println("\n1 - Add Transfer Job")
-		fmt.Println("2 - Start All Jobs")
-		fmt.Println("3 - View Job Status")
-		fmt.Println("4 - Cancel Job")
-		fmt.Println("5 - Show Summary")
-		fmt.Println("6 - Back to Main Menu")
-
-		fmt.Print("Choice: ")
+		fmt.Println("\n1 - " + i18n.T("menu.parallel.add"))
+		fmt.Println("2 - " + i18n.T("menu.parallel.start"))
+		fmt.Println("3 - " + i18n.T("menu.parallel.status"))
+		fmt.Println("4 - " + i18n.T("menu.parallel.cancel"))
+		fmt.Println("5 - " + i18n.T("menu.parallel.summary"))
+		fmt.Println("6 - " + i18n.T("menu.parallel.scheduler_cli"))
+		fmt.Println("7 - " + i18n.T("menu.parallel.health"))
+		fmt.Println("8 - " + i18n.T("menu.parallel.batches"))
+		fmt.Println("9 - " + i18n.T("menu.parallel.retry"))
+		fmt.Println("10 - " + i18n.T("menu.parallel.add_archive"))
+		fmt.Println("11 - " + i18n.T("menu.parallel.back"))
+
+		fmt.Print(i18n.T("cli.choice") + " ")
 		choice, _ := reader.ReadString('\n')
 		choice = strings.TrimSpace(choice)
 
@@ -338,7 +1057,11 @@ func ParallelTransfer() {
 		case "1":
 			addTransferJob(parallelManager, reader)
 		case "2":
-			fmt.Println(ui.Cyan("Starting all pending jobs..."))
+			if !parallelManager.Admitting() {
+				fmt.Println(ui.Yellow(i18n.T("jobs.admission_closed")))
+				break
+			}
+			fmt.Println(ui.Cyan(i18n.T("jobs.starting_all")))
 			parallelManager.StartAllJobs()
 		case "3":
 			showJobStatus(parallelManager)
@@ -347,49 +1070,180 @@ func ParallelTransfer() {
 		case "5":
 			parallelManager.PrintJobSummary()
 		case "6":
+			toggleScheduler(scheduler)
+		case "7":
+			showServerHealth(parallelManager, reader)
+		case "8":
+			manageBatches(parallelManager, reader)
+		case "9":
+			retryFailedJobs(parallelManager, reader)
+		case "10":
+			addArchiveJob(parallelManager, reader)
+		case "11":
 			return
 		default:
-			fmt.Println(ui.Red("Invalid choice"))
+			fmt.Println(ui.Red(i18n.T("cli.invalid_choice")))
 		}
 	}
 }
 
 // addTransferJob adds a new transfer job to the queue
 func addTransferJob(ptm *ParallelTransferManager, reader *bufio.Reader) {
-	fmt.Println(ui.Cyan("=== Add Transfer Job ==="))
+	fmt.Println(ui.Cyan("=== " + i18n.T("jobs.add_title") + " ==="))
 
 	job := &TransferJob{}
 
-	fmt.Print("Source IMAP host: ")
+	fmt.Print(i18n.T("prompt.source_host") + " ")
 	srcHost, _ := reader.ReadString('\n')
 	job.SourceHost = strings.TrimSpace(srcHost)
 
-	fmt.Print("Source email: ")
+	fmt.Print(i18n.T("prompt.source_email") + " ")
 	srcEmail, _ := reader.ReadString('\n')
 	job.SourceEmail = strings.TrimSpace(srcEmail)
 
-	fmt.Print("Source password: ")
+	fmt.Print(i18n.T("prompt.source_password") + " ")
 	srcPass, _ := ReadPassword()
 	job.SourcePass = srcPass
 	fmt.Println()
 
-	fmt.Print("Destination IMAP host: ")
+	fmt.Print(i18n.T("prompt.dest_host") + " ")
 	dstHost, _ := reader.ReadString('\n')
 	job.DestHost = strings.TrimSpace(dstHost)
 
-	fmt.Print("Destination email: ")
+	fmt.Print(i18n.T("prompt.dest_email") + " ")
 	dstEmail, _ := reader.ReadString('\n')
 	job.DestEmail = strings.TrimSpace(dstEmail)
 
-	fmt.Print("Destination password: ")
+	fmt.Print(i18n.T("prompt.dest_password") + " ")
 	dstPass, _ := ReadPassword()
 	job.DestPass = dstPass
 	fmt.Println()
 
+	fmt.Print(i18n.T("prompt.delta_cron") + " ")
+	delta, _ := reader.ReadString('\n')
+	job.DeltaSchedule = strings.TrimSpace(delta)
+
+	if job.DeltaSchedule != "" {
+		fmt.Print(i18n.T("prompt.cutover_date") + " ")
+		cutover, _ := reader.ReadString('\n')
+		cutoverAt, err := ParseCutoverDate(strings.TrimSpace(cutover))
+		if err != nil {
+			fmt.Println(ui.Red(i18n.T("jobs.add_failed", err)))
+			return
+		}
+		job.CutoverAt = cutoverAt
+	}
+
+	fmt.Print(i18n.T("prompt.priority") + " ")
+	priority, _ := reader.ReadString('\n')
+	if priority = strings.TrimSpace(priority); priority != "" {
+		p, err := strconv.Atoi(priority)
+		if err != nil {
+			fmt.Println(ui.Red(i18n.T("jobs.add_failed", i18n.T("jobs.invalid_priority"))))
+			return
+		}
+		job.Priority = p
+	}
+
+	fmt.Print(i18n.T("prompt.tags") + " ")
+	tags, _ := reader.ReadString('\n')
+	job.Tags = splitList(tags)
+
+	fmt.Print(i18n.T("prompt.batch_id_optional") + " ")
+	batchID, _ := reader.ReadString('\n')
+	job.BatchID = strings.TrimSpace(batchID)
+
+	fmt.Print(i18n.T("prompt.depends_on") + " ")
+	deps, _ := reader.ReadString('\n')
+	job.DependsOn = splitList(deps)
+
+	fmt.Print(i18n.T("prompt.filter") + " ")
+	filter, _ := reader.ReadString('\n')
+	parsed, err := ParseFilter(filter)
+	if err != nil {
+		fmt.Println(ui.Red(i18n.T("jobs.add_failed", err)))
+		return
+	}
+	job.Filter = parsed
+
 	if err := ptm.AddJob(job); err != nil {
-		fmt.Println(ui.Red("Failed to add job:"), err)
+		fmt.Println(ui.Red(i18n.T("jobs.add_failed", err)))
 	} else {
-		fmt.Println(ui.Green("Job added successfully!"))
+		fmt.Println(ui.Green(i18n.T("jobs.add_done")))
+	}
+}
+
+// addArchiveJob adds a job that exports a mailbox to local mbox or Maildir files
+func addArchiveJob(ptm *ParallelTransferManager, reader *bufio.Reader) {
+	fmt.Println(ui.Cyan("=== " + i18n.T("jobs.add_archive_title") + " ==="))
+
+	job := &TransferJob{}
+
+	fmt.Print(i18n.T("prompt.source_host") + " ")
+	srcHost, _ := reader.ReadString('\n')
+	job.SourceHost = strings.TrimSpace(srcHost)
+
+	fmt.Print(i18n.T("prompt.source_email") + " ")
+	srcEmail, _ := reader.ReadString('\n')
+	job.SourceEmail = strings.TrimSpace(srcEmail)
+
+	fmt.Print(i18n.T("prompt.source_password") + " ")
+	srcPass, _ := ReadPassword()
+	job.SourcePass = srcPass
+	fmt.Println()
+
+	fmt.Print(i18n.T("prompt.archive_format") + " ")
+	format, _ := reader.ReadString('\n')
+	parsedFormat, err := ParseArchiveFormat(format)
+	if err != nil {
+		fmt.Println(ui.Red(i18n.T("jobs.add_failed", err)))
+		return
+	}
+	job.Archive.Format = parsedFormat
+
+	fmt.Print(i18n.T("prompt.archive_dir") + " ")
+	dir, _ := reader.ReadString('\n')
+	job.Archive.Dir = strings.TrimSpace(dir)
+
+	fmt.Print(i18n.T("prompt.batch_id_optional") + " ")
+	batchID, _ := reader.ReadString('\n')
+	job.BatchID = strings.TrimSpace(batchID)
+
+	fmt.Print(i18n.T("prompt.filter") + " ")
+	filter, _ := reader.ReadString('\n')
+	parsed, err := ParseFilter(filter)
+	if err != nil {
+		fmt.Println(ui.Red(i18n.T("jobs.add_failed", err)))
+		return
+	}
+	job.Filter = parsed
+
+	if err := ptm.AddJob(job); err != nil {
+		fmt.Println(ui.Red(i18n.T("jobs.add_failed", err)))
+	} else {
+		fmt.Println(ui.Green(i18n.T("jobs.add_done")))
+	}
+}
+
+// toggleScheduler starts or stops the maintenance window scheduler
+func toggleScheduler(scheduler *Scheduler) {
+	if scheduler == nil {
+		fmt.Println(ui.Red(i18n.T("scheduler.unavailable")))
+		return
+	}
+
+	if scheduler.IsRunning() {
+		scheduler.Stop()
+		fmt.Println(ui.Yellow(i18n.T("scheduler.stopped")))
+		return
+	}
+
+	scheduler.Start()
+	status := scheduler.Status()
+	if status.WindowOpen {
+		fmt.Println(ui.Green(i18n.T("scheduler.started_open")))
+	} else if !status.NextOpen.IsZero() {
+		fmt.Println(ui.Green(i18n.T("scheduler.started_next", status.NextOpen.Format("2006-01-02 15:04"))))
 	}
 }
 
@@ -398,11 +1252,11 @@ func showJobStatus(ptm *ParallelTransferManager) {
 	jobs := ptm.GetAllJobs()
 
 	if len(jobs) == 0 {
-		fmt.Println(ui.Yellow("No jobs found"))
+		fmt.Println(ui.Yellow(i18n.T("jobs.none")))
 		return
 	}
 
-	fmt.Println(ui.Cyan("=== Job Status ==="))
+	fmt.Println(ui.Cyan("=== " + i18n.T("job.title") + " ==="))
 	for id, job := range jobs {
 		statusColor := ui.Green
 		switch job.Status {
@@ -414,65 +1268,249 @@ func showJobStatus(ptm *ParallelTransferManager) {
 			statusColor = ui.Red
 		case StatusCancelled:
 			statusColor = ui.Red
+		case StatusPaused, StatusWaiting:
+			statusColor = ui.Yellow
 		}
 
-		fmt.Printf("ID: %s\n", id)
-		fmt.Printf("  From: %s\n", job.SourceEmail)
-		fmt.Printf("  To: %s\n", job.DestEmail)
-		fmt.Printf("  Status: %s\n", statusColor(string(job.Status)))
-		fmt.Printf("  Progress: %.1f%%\n", job.Progress)
+		fmt.Println(i18n.T("job.id", id))
+		fmt.Println("  " + i18n.T("job.from", job.SourceEmail))
+		fmt.Println("  " + i18n.T("job.to", job.Destination()))
+		fmt.Println("  " + i18n.T("job.status", statusColor(ui.StatusLabel(string(job.Status)))))
+		fmt.Println("  " + i18n.T("job.progress", job.Progress))
+		if job.Priority != 0 {
+			fmt.Println("  " + i18n.T("job.priority", job.Priority))
+		}
+		if job.BatchID != "" {
+			fmt.Println("  " + i18n.T("job.batch", job.BatchID))
+		}
+		if len(job.Tags) > 0 {
+			fmt.Println("  " + i18n.T("job.tags", strings.Join(job.Tags, ", ")))
+		}
+		if len(job.DependsOn) > 0 {
+			fmt.Println("  " + i18n.T("job.depends_on", strings.Join(job.DependsOn, ", ")))
+		}
+		if job.FailureReason != FailureNone {
+			fmt.Println("  " + i18n.T("job.reason", job.FailureReason))
+		}
 
 		if job.StartTime != (time.Time{}) {
-			fmt.Printf("  Started: %s\n", job.StartTime.Format("2006-01-02 15:04:05"))
+			fmt.Println("  " + i18n.T("job.started", job.StartTime.Format("2006-01-02 15:04:05")))
 		}
 
 		if job.Error != nil {
-			fmt.Printf("  Error: %v\n", job.Error)
+			fmt.Println("  " + i18n.T("job.error", job.Error))
+		}
+
+		if attempts := ptm.JobAttempts(id); len(attempts) > 0 {
+			fmt.Println("  " + i18n.T("job.attempts", len(attempts)))
+			for _, a := range attempts {
+				fmt.Printf("    %s\n", formatAttempt(a))
+			}
+		}
+		fmt.Println()
+	}
+}
+
+// showServerHealth displays the circuit breaker of every failing server
+func showServerHealth(ptm *ParallelTransferManager, reader *bufio.Reader) {
+	statuses := ptm.BreakerStatus()
+
+	if len(statuses) == 0 {
+		fmt.Println(ui.Green(i18n.T("health.all_healthy")))
+		return
+	}
+
+	fmt.Println(ui.Cyan("=== " + i18n.T("health.title") + " ==="))
+	for _, s := range statuses {
+		stateColor := ui.Yellow
+		if s.State == CircuitOpen {
+			stateColor = ui.Red
+		}
+
+		fmt.Println(i18n.T("health.host", s.Host))
+		fmt.Println("  " + i18n.T("health.state", stateColor(string(s.State))))
+		fmt.Println("  " + i18n.T("health.failures", s.Failures))
+		if s.State == CircuitOpen {
+			fmt.Println("  " + i18n.T("health.opened", s.OpenedAt.Format("2006-01-02 15:04:05")))
+			fmt.Println("  " + i18n.T("health.next_probe", s.NextProbe.Format("15:04:05")))
+		}
+		if s.LastError != "" {
+			fmt.Println("  " + i18n.T("health.last_error", s.LastError))
 		}
 		fmt.Println()
 	}
+
+	fmt.Print(i18n.T("prompt.reset_host") + " ")
+	host, _ := reader.ReadString('\n')
+	host = strings.TrimSpace(host)
+	if host != "" {
+		ptm.ResetBreaker(host)
+		fmt.Println(ui.Green(i18n.T("health.reset_done")))
+	}
+}
+
+// manageBatches lists batches and starts, cancels or previews one
+func manageBatches(ptm *ParallelTransferManager, reader *bufio.Reader) {
+	batchIDs := ptm.BatchIDs()
+	if len(batchIDs) == 0 {
+		fmt.Println(ui.Yellow(i18n.T("batches.none_cli")))
+		return
+	}
+
+	fmt.Println(ui.Cyan("=== " + i18n.T("batches.title") + " ==="))
+	for _, batchID := range batchIDs {
+		fmt.Printf("%s: %s\n", batchID, formatSummary(ptm.GetBatchSummary(batchID)))
+	}
+
+	fmt.Println("\n1 - " + i18n.T("batches.start"))
+	fmt.Println("2 - " + i18n.T("batches.cancel"))
+	fmt.Println("3 - " + i18n.T("batches.preview_json"))
+	fmt.Println("4 - " + i18n.T("batches.preview_folders_json"))
+	fmt.Println("5 - " + i18n.T("cli.back"))
+	fmt.Print(i18n.T("cli.choice") + " ")
+	choice, _ := reader.ReadString('\n')
+	choice = strings.TrimSpace(choice)
+	if choice != "1" && choice != "2" && choice != "3" && choice != "4" {
+		return
+	}
+
+	fmt.Print(i18n.T("prompt.batch_id") + " ")
+	batchID, _ := reader.ReadString('\n')
+	batchID = strings.TrimSpace(batchID)
+
+	if choice == "1" {
+		fmt.Println(ui.Cyan(i18n.T("batches.starting", batchID)))
+		if err := ptm.StartBatch(batchID); err != nil {
+			fmt.Println(ui.Red(i18n.T("batches.start_failed", err)))
+			return
+		}
+		fmt.Println(i18n.T("summary.batch", batchID, formatSummary(ptm.GetBatchSummary(batchID))))
+		return
+	}
+	if choice == "3" || choice == "4" {
+		previewBatch(ptm, batchID, choice == "4")
+		return
+	}
+
+	cancelled, err := ptm.CancelBatch(batchID)
+	if err != nil {
+		fmt.Println(ui.Red(i18n.T("batches.cancel_failed", err)))
+		return
+	}
+	fmt.Println(ui.Green(i18n.N("jobs.cancelled", cancelled, cancelled)))
+}
+
+// previewBatch dry-runs a batch and prints the plans as JSON
+func previewBatch(ptm *ParallelTransferManager, batchID string, foldersOnly bool) {
+	fmt.Println(ui.Cyan(i18n.T("plan.running")))
+	plans, err := ptm.PreviewBatch(ptm.ctx, batchID, foldersOnly)
+	if err != nil {
+		fmt.Println(ui.Red(i18n.T("plan.failed", err)))
+	}
+	if len(plans) == 0 {
+		return
+	}
+
+	data, err := PlanJSON(plans)
+	if err != nil {
+		fmt.Println(ui.Red(i18n.T("plan.failed", err)))
+		return
+	}
+	fmt.Println(string(data))
+}
+
+// retryFailedJobs requeues failed jobs, optionally with changed parameters
+func retryFailedJobs(ptm *ParallelTransferManager, reader *bufio.Reader) {
+	fmt.Println(ui.Cyan("=== " + i18n.T("retry.title") + " ==="))
+	fmt.Println(i18n.T("retry.intro"))
+
+	var opts RetryOptions
+
+	fmt.Print(i18n.T("prompt.failure_reason") + " ")
+	reason, _ := reader.ReadString('\n')
+	opts.Reason = FailureReason(strings.TrimSpace(reason))
+
+	fmt.Print(i18n.T("prompt.batch_id") + " ")
+	batchID, _ := reader.ReadString('\n')
+	opts.BatchID = strings.TrimSpace(batchID)
+
+	fmt.Print(i18n.T("prompt.new_source_host") + " ")
+	srcHost, _ := reader.ReadString('\n')
+	opts.SourceHost = strings.TrimSpace(srcHost)
+
+	fmt.Print(i18n.T("prompt.new_source_password") + " ")
+	opts.SourcePass, _ = ReadPassword()
+	fmt.Println()
+
+	fmt.Print(i18n.T("prompt.new_dest_host") + " ")
+	dstHost, _ := reader.ReadString('\n')
+	opts.DestHost = strings.TrimSpace(dstHost)
+
+	fmt.Print(i18n.T("prompt.new_dest_password") + " ")
+	opts.DestPass, _ = ReadPassword()
+	fmt.Println()
+
+	fmt.Print(i18n.T("prompt.new_attempt_cap") + " ")
+	maxAttempts, _ := reader.ReadString('\n')
+	if maxAttempts = strings.TrimSpace(maxAttempts); maxAttempts != "" {
+		n, err := strconv.Atoi(maxAttempts)
+		if err != nil || n <= 0 {
+			fmt.Println(ui.Red(i18n.T("retry.invalid_cap")))
+			return
+		}
+		opts.MaxAttempts = n
+	}
+
+	retried, skipped := ptm.RetryFailed(opts)
+	fmt.Println(ui.Green(i18n.N("retry.requeued", retried, retried)))
+	if skipped > 0 {
+		fmt.Println(ui.Yellow(i18n.N("retry.skipped", skipped, skipped)))
+	}
+	if retried > 0 && !ptm.IsBatchRunning() {
+		fmt.Println(i18n.T("retry.start_hint", i18n.T("menu.parallel.start")))
+	}
 }
 
 // cancelJob cancels a specific job
 func cancelJob(ptm *ParallelTransferManager, reader *bufio.Reader) {
-	fmt.Print("Enter job ID to cancel: ")
+	fmt.Print(i18n.T("prompt.cancel_job") + " ")
 	jobID, _ := reader.ReadString('\n')
 	jobID = strings.TrimSpace(jobID)
 
 	if err := ptm.CancelJob(jobID); err != nil {
-		fmt.Println(ui.Red("Failed to cancel job:"), err)
+		fmt.Println(ui.Red(i18n.T("jobs.cancel_failed", err)))
 	} else {
-		fmt.Println(ui.Green("Job cancelled successfully!"))
+		fmt.Println(ui.Green(i18n.T("jobs.cancel_done")))
 	}
 }
 
 // ShowPerformanceStats displays performance statistics
 func ShowPerformanceStats() {
-	fmt.Println(ui.Cyan("=== Performance Statistics ==="))
+	fmt.Println(ui.Cyan("=== " + i18n.T("stats.title") + " ==="))
 
 	// Create a new performance manager to show stats
 	perfManager := NewPerformanceManager(nil)
+	if err := perfManager.ConfigureCache(&CurrentConfig().Cache); err != nil {
+		fmt.Println(ui.Yellow(i18n.T("error.cache_not_loaded")), err)
+	}
+	defer perfManager.Close()
 
 	// Show memory usage
 	memoryUsage := perfManager.MemoryUsage()
-	fmt.Printf("Current Memory Usage: %.2f MB\n", memoryUsage)
+	fmt.Println(i18n.T("stats.current_memory", memoryUsage))
 
 	if perfManager.CheckMemoryLimit() {
-		fmt.Println(ui.Green("Memory usage is within limits"))
+		fmt.Println(ui.Green(i18n.T("stats.memory_ok")))
 	} else {
-		fmt.Println(ui.Red("Memory usage is above limit"))
+		fmt.Println(ui.Red(i18n.T("stats.memory_high")))
 	}
 
-	// Show cache information
-	fmt.Printf("Cache Items: %d\n", perfManager.cache.ItemCount())
-
 	// Show connection pool status
-	activeConnections := perfManager.config.MaxConcurrentTransfers - int(perfManager.semaphore.Available())
-	fmt.Printf("Active Connections: %d/%d\n", activeConnections, perfManager.config.MaxConcurrentTransfers)
+	fmt.Println(i18n.T("stats.active_connections", perfManager.ConnectionsInUse(), perfManager.ConcurrencyLimit()))
 
 	// Show transfer statistics
 	perfManager.PrintStats()
 
-	fmt.Println("\nPress Enter to continue...")
-	fmt.Scanln()
+	fmt.Println("\n" + i18n.T("ui.press_enter"))
+	ui.ReadLine()
 }
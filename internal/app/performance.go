@@ -2,10 +2,13 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"runtime"
 	"sync"
 	"time"
+
+	"imapsync/internal/i18n"
 )
 
 // PerformanceConfig holds performance-related configuration
@@ -17,6 +20,8 @@ type PerformanceConfig struct {
 	MemoryLimitMB          int           // Memory limit in MB
 	RetryAttempts          int           // Number of retry attempts
 	RetryDelay             time.Duration // Delay between retries
+	WorkerPoolSize         int           // Number of transfer workers; 0 means MaxConcurrentTransfers
+	QueueDepth             int           // Maximum jobs waiting for a worker; 0 means unbounded
 }
 
 // DefaultPerformanceConfig returns default performance settings
@@ -29,6 +34,8 @@ func DefaultPerformanceConfig() *PerformanceConfig {
 		MemoryLimitMB:          512,              // 512MB memory limit
 		RetryAttempts:          3,                // 3 retry attempts
 		RetryDelay:             5 * time.Second,  // 5 second delay
+		WorkerPoolSize:         0,                // One worker per concurrent transfer
+		QueueDepth:             1000,             // Producers block beyond 1000 queued jobs
 	}
 }
 
@@ -40,6 +47,7 @@ type PerformanceManager struct {
 	mu        sync.RWMutex
 	logger    *Logger
 	stats     *TransferStats
+	policies  map[FailureReason]RetryPolicy
 }
 
 // TransferStats tracks transfer performance metrics
@@ -52,8 +60,13 @@ type TransferStats struct {
 	AverageSpeed        float64 // bytes per second
 	StartTime           time.Time
 	LastTransferTime    time.Time
+	ConcurrencyLimit    int                   // Current size of the transfer semaphore
+	ConcurrencyChanges  []ConcurrencyDecision // Recent adaptive concurrency decisions, oldest first
 }
 
+// maxConcurrencyChanges is how many concurrency decisions are kept in stats
+const maxConcurrencyChanges = 20
+
 // NewPerformanceManager creates a new performance manager
 func NewPerformanceManager(config *PerformanceConfig) *PerformanceManager {
 	if config == nil {
@@ -61,16 +74,16 @@ func NewPerformanceManager(config *PerformanceConfig) *PerformanceManager {
 	}
 
 	logger := NewLogger()
-	logger.SetLevel(LevelInfo)
 
 	return &PerformanceManager{
 		config:    config,
-		cache:     NewCache(),
+		cache:     NewCache(config.CacheCleanupInterval),
 		semaphore: NewSemaphore(int64(config.MaxConcurrentTransfers)),
 		logger:    logger,
 		stats: &TransferStats{
 			StartTime: time.Now(),
 		},
+		policies: DefaultRetryPolicies(),
 	}
 }
 
@@ -84,14 +97,92 @@ func (pm *PerformanceManager) ReleaseConnection() {
 	pm.semaphore.Release(1)
 }
 
-// GetCachedData retrieves data from cache
-func (pm *PerformanceManager) GetCachedData(key string) (interface{}, bool) {
-	return pm.cache.Get(key)
+// AcquireConnections acquires weight permits, e.g. for a large mailbox
+func (pm *PerformanceManager) AcquireConnections(ctx context.Context, weight int64) error {
+	return pm.semaphore.Acquire(ctx, weight)
+}
+
+// ReleaseConnections releases permits taken by AcquireConnections
+func (pm *PerformanceManager) ReleaseConnections(weight int64) {
+	pm.semaphore.Release(weight)
+}
+
+// ConcurrencyLimit returns the current number of concurrent transfers allowed
+func (pm *PerformanceManager) ConcurrencyLimit() int {
+	return int(pm.semaphore.Size())
+}
+
+// SetConcurrencyLimit resizes the connection pool at runtime
+func (pm *PerformanceManager) SetConcurrencyLimit(limit int) {
+	pm.semaphore.Resize(int64(limit))
+}
+
+// ConnectionsInUse returns the number of connections currently held
+func (pm *PerformanceManager) ConnectionsInUse() int {
+	return int(pm.semaphore.InUse())
+}
+
+// recordConcurrencyDecision keeps a concurrency decision in stats
+func (pm *PerformanceManager) recordConcurrencyDecision(d ConcurrencyDecision) {
+	pm.stats.mu.Lock()
+	defer pm.stats.mu.Unlock()
+
+	pm.stats.ConcurrencyChanges = append(pm.stats.ConcurrencyChanges, d)
+	if n := len(pm.stats.ConcurrencyChanges); n > maxConcurrencyChanges {
+		pm.stats.ConcurrencyChanges = pm.stats.ConcurrencyChanges[n-maxConcurrencyChanges:]
+	}
+}
+
+// TransferRecord describes a successful transfer, kept in the cache
+type TransferRecord struct {
+	Timestamp time.Time     `json:"timestamp"`
+	Duration  time.Duration `json:"duration"`
+	Bytes     int64         `json:"bytes"`
+}
+
+// String formats the record for display
+func (r TransferRecord) String() string {
+	return i18n.T("record.summary", r.Timestamp.Format("2006-01-02 15:04:05"),
+		r.Duration.Round(time.Second), float64(r.Bytes)/(1024*1024))
+}
+
+// transferRecordExpiration is how long successful transfers are remembered
+const transferRecordExpiration = 90 * 24 * time.Hour
+
+// ConfigureCache applies size and persistence settings and loads the saved cache
+func (pm *PerformanceManager) ConfigureCache(config *CacheConfig) error {
+	pm.cache.SetMaxEntries(config.MaxEntries)
+	if !config.Persist {
+		return nil
+	}
+
+	path := config.Path
+	if path == "" {
+		path = DefaultCachePath()
+	}
+	return pm.cache.Load(path)
+}
+
+// Close stops the cache janitor and saves the cache
+func (pm *PerformanceManager) Close() error {
+	return pm.cache.Close()
+}
+
+// LastTransfer returns the last successful transfer stored under key
+func (pm *PerformanceManager) LastTransfer(key string) (TransferRecord, bool) {
+	return CacheGet[TransferRecord](pm.cache, key)
+}
+
+// RecordTransfer remembers a successful transfer under key
+func (pm *PerformanceManager) RecordTransfer(key string, record TransferRecord) {
+	if err := CacheSet(pm.cache, key, record, transferRecordExpiration); err != nil {
+		pm.logger.Warn("Failed to cache transfer: %v", err)
+	}
 }
 
-// SetCachedData stores data in cache
-func (pm *PerformanceManager) SetCachedData(key string, data interface{}) {
-	pm.cache.Set(key, data, pm.config.CacheExpiration)
+// CacheStats returns the cache metrics
+func (pm *PerformanceManager) CacheStats() CacheStats {
+	return pm.cache.Stats()
 }
 
 // InvalidateCache removes specific cache entries
@@ -130,51 +221,144 @@ func (pm *PerformanceManager) GetStats() TransferStats {
 	pm.stats.mu.RLock()
 	defer pm.stats.mu.RUnlock()
 
-	return *pm.stats
+	return TransferStats{
+		TotalTransfers:      pm.stats.TotalTransfers,
+		SuccessfulTransfers: pm.stats.SuccessfulTransfers,
+		FailedTransfers:     pm.stats.FailedTransfers,
+		TotalBytes:          pm.stats.TotalBytes,
+		AverageSpeed:        pm.stats.AverageSpeed,
+		StartTime:           pm.stats.StartTime,
+		LastTransferTime:    pm.stats.LastTransferTime,
+		ConcurrencyLimit:    pm.ConcurrencyLimit(),
+		ConcurrencyChanges:  append([]ConcurrencyDecision(nil), pm.stats.ConcurrencyChanges...),
+	}
 }
 
 // PrintStats prints performance statistics
 func (pm *PerformanceManager) PrintStats() {
 	stats := pm.GetStats()
 
-	fmt.Printf("\n=== Performance Statistics ===\n")
-	fmt.Printf("Total Transfers: %d\n", stats.TotalTransfers)
-	fmt.Printf("Successful: %d\n", stats.SuccessfulTransfers)
-	fmt.Printf("Failed: %d\n", stats.FailedTransfers)
+	fmt.Printf("\n=== %s ===\n", i18n.T("stats.title"))
+	fmt.Println(i18n.T("stats.total_transfers", stats.TotalTransfers))
+	fmt.Println(i18n.T("stats.successful", stats.SuccessfulTransfers))
+	fmt.Println(i18n.T("stats.failed", stats.FailedTransfers))
 	if stats.TotalTransfers > 0 {
-		fmt.Printf("Success Rate: %.2f%%\n", float64(stats.SuccessfulTransfers)/float64(stats.TotalTransfers)*100)
+		fmt.Println(i18n.T("stats.success_rate", float64(stats.SuccessfulTransfers)/float64(stats.TotalTransfers)*100))
+	}
+	fmt.Println(i18n.T("stats.total_data", float64(stats.TotalBytes)/(1024*1024)))
+	fmt.Println(i18n.T("stats.average_speed", stats.AverageSpeed/1024))
+	fmt.Println(i18n.T("stats.uptime", time.Since(stats.StartTime).Round(time.Second)))
+	fmt.Println(i18n.T("stats.last_transfer", stats.LastTransferTime.Format("2006-01-02 15:04:05")))
+	cache := pm.cache.Stats()
+	if cache.MaxEntries > 0 {
+		fmt.Println(i18n.T("stats.cache_items", cache.Entries, cache.MaxEntries))
+	} else {
+		fmt.Println(i18n.T("stats.cache_items_unbounded", cache.Entries))
+	}
+	fmt.Println(i18n.T("stats.cache_hit_rate", cache.HitRate(), cache.Hits, cache.Misses, cache.Evictions))
+	fmt.Println(i18n.T("stats.active_connections", pm.ConnectionsInUse(), stats.ConcurrencyLimit))
+	for _, d := range stats.ConcurrencyChanges {
+		fmt.Println("  " + i18n.T("stats.concurrency", d))
+	}
+}
+
+// SetRetryPolicies overrides the retry policy of the given failure classes
+func (pm *PerformanceManager) SetRetryPolicies(policies map[FailureReason]RetryPolicy) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	for reason, policy := range policies {
+		pm.policies[reason] = policy
+	}
+}
+
+// retryPolicy returns the policy for a failure class
+func (pm *PerformanceManager) retryPolicy(reason FailureReason) RetryPolicy {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+
+	if policy, ok := pm.policies[reason]; ok {
+		return policy
 	}
-	fmt.Printf("Total Data: %.2f MB\n", float64(stats.TotalBytes)/(1024*1024))
-	fmt.Printf("Average Speed: %.2f KB/s\n", stats.AverageSpeed/1024)
-	fmt.Printf("Uptime: %s\n", time.Since(stats.StartTime).Round(time.Second))
-	fmt.Printf("Last Transfer: %s\n", stats.LastTransferTime.Format("2006-01-02 15:04:05"))
-	fmt.Printf("Cache Items: %d\n", pm.cache.ItemCount())
-	fmt.Printf("Active Connections: %d/%d\n", pm.config.MaxConcurrentTransfers-int(pm.semaphore.Available()), pm.config.MaxConcurrentTransfers)
+	return pm.policies[FailureUnknown]
+}
+
+// RetryDeferredError is returned by RetryJob when the next attempt has to wait,
+// e.g. for an exponential backoff or a throttling window. The caller requeues
+// the work instead of holding its connections while it waits.
+type RetryDeferredError struct {
+	Delay   time.Duration
+	Attempt int // Attempts made so far; pass it back to RetryJob when the work resumes
+	Err     error
+}
+
+// Error implements the error interface
+func (e *RetryDeferredError) Error() string {
+	return fmt.Sprintf("retry deferred for %s: %v", e.Delay.Truncate(time.Millisecond), e.Err)
+}
+
+// Unwrap returns the error of the last attempt
+func (e *RetryDeferredError) Unwrap() error {
+	return e.Err
 }
 
-// RetryWithBackoff executes a function with retry logic
+// RetryWithBackoff executes a function, retrying failures according to the
+// policy of their class: authentication errors fail at once, unreachable hosts
+// back off exponentially and throttling waits for a fixed delay
 func (pm *PerformanceManager) RetryWithBackoff(ctx context.Context, operation func() error) error {
+	return pm.retry(ctx, 0, false, operation)
+}
+
+// RetryJob works like RetryWithBackoff, but instead of sleeping between
+// attempts it returns a *RetryDeferredError. attempts is the number of attempts
+// made before the work was deferred, 0 for a fresh start.
+func (pm *PerformanceManager) RetryJob(ctx context.Context, attempts int, operation func() error) error {
+	return pm.retry(ctx, attempts, true, operation)
+}
+
+// retry runs operation until it succeeds or its failure class runs out of attempts
+func (pm *PerformanceManager) retry(ctx context.Context, attempt int, deferWait bool, operation func() error) error {
 	var lastErr error
 
-	for attempt := 0; attempt < pm.config.RetryAttempts; attempt++ {
-		if err := operation(); err == nil {
+	for {
+		attempt++
+		err := operation()
+		if err == nil {
 			return nil
-		} else {
-			lastErr = err
-			pm.logger.Warn("Attempt %d failed: %v", attempt+1, err)
-
-			if attempt < pm.config.RetryAttempts-1 {
-				select {
-				case <-ctx.Done():
-					return ctx.Err()
-				case <-time.After(pm.config.RetryDelay * time.Duration(attempt+1)):
-					continue
-				}
-			}
+		}
+		// The job waits for the server instead of burning its attempts,
+		// or it has used all of them
+		if ctx.Err() != nil || errors.Is(err, ErrCircuitOpen) || errors.Is(err, ErrAttemptsExhausted) {
+			return err
+		}
+		lastErr = err
+
+		reason := ClassifyError(err)
+		policy := pm.retryPolicy(reason)
+
+		maxAttempts := policy.MaxAttempts
+		if maxAttempts <= 0 {
+			maxAttempts = pm.config.RetryAttempts
+		}
+		if policy.Strategy == RetryNone || attempt >= maxAttempts {
+			break
+		}
+
+		delay := policy.backoff(attempt, pm.config.RetryDelay)
+		if deferWait {
+			pm.logger.Warn("Attempt %d failed (%s): %v; requeued for %s", attempt, reason, err, delay.Truncate(time.Millisecond))
+			return &RetryDeferredError{Delay: delay, Attempt: attempt, Err: err}
+		}
+		pm.logger.Warn("Attempt %d failed (%s): %v; retrying in %s", attempt, reason, err, delay.Truncate(time.Millisecond))
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
 		}
 	}
 
-	return fmt.Errorf("operation failed after %d attempts: %w", pm.config.RetryAttempts, lastErr)
+	return fmt.Errorf("operation failed after %d attempts: %w", attempt, lastErr)
 }
 
 // MemoryUsage returns current memory usage in MB
@@ -0,0 +1,350 @@
+package app
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"imapsync/internal/i18n"
+)
+
+// TransferPlan is what imapsync would do for a job, read from the output of a
+// dry run. Nothing is written to the destination while it is made.
+type TransferPlan struct {
+	JobID       string          `json:"job_id,omitempty"`
+	Source      string          `json:"source"`
+	Destination string          `json:"destination"`
+	FoldersOnly bool            `json:"folders_only"`     // Made with --justfolders: no message counts
+	Filter      string          `json:"filter,omitempty"` // Message filter in ParseFilter syntax
+	Folders     []PlannedFolder `json:"folders"`
+	Excludes    []string        `json:"excludes"` // Folder regexes passed to imapsync
+	Messages    int             `json:"messages"` // Messages to copy in all folders
+	Bytes       int64           `json:"bytes"`
+	CreatedAt   time.Time       `json:"created_at"`
+	Error       string          `json:"error,omitempty"`
+}
+
+// PlannedFolder is a source folder and what happens to it
+type PlannedFolder struct {
+	Source         string `json:"source"`
+	Destination    string `json:"destination"`
+	Create         bool   `json:"create"`          // The destination folder does not exist yet
+	SourceMessages int    `json:"source_messages"` // Messages in the source folder
+	Messages       int    `json:"messages"`        // Messages that would be copied
+	Bytes          int64  `json:"bytes"`           // Size of the messages that would be copied
+}
+
+// FoldersToCreate returns the number of destination folders the plan creates
+func (p *TransferPlan) FoldersToCreate() int {
+	n := 0
+	for _, f := range p.Folders {
+		if f.Create {
+			n++
+		}
+	}
+	return n
+}
+
+var (
+	// sizeRe matches the folder sizes imapsync lists before syncing, e.g.
+	// "Host1 folder     1/6 [INBOX]    Size:  95393 Messages:    14 Biggest: 15024"
+	sizeRe = regexp.MustCompile(`^Host([12]) folder\s+(\d+)/\d+\s+\[([^\]]+)\]\s+Size:\s+\d+\s+Messages:\s+(\d+)`)
+
+	// missingRe matches a destination folder without sizes, e.g.
+	// "Host2 folder     3/6 [Sent Items]    does not exist yet"
+	missingRe = regexp.MustCompile(`^Host2 folder\s+(\d+)/\d+\s+\[([^\]]+)\]\s+does not exist`)
+
+	// syncFolderRe matches the header of a folder with its destination, e.g.
+	// "Folder    3/6 [Sent] -> [Sent Items]"
+	syncFolderRe = regexp.MustCompile(`Folder\s+(\d+)/\d+\s+\[([^\]]+)\]\s+->\s+\[([^\]]+)\]`)
+
+	// createRe matches a folder imapsync creates, or would create with --dry
+	createRe = regexp.MustCompile(`Creating folder \[([^\]]+)\] on host2`)
+)
+
+// planParser builds a TransferPlan from the output of a dry run. Folders are
+// matched by the number imapsync gives them, which is the same on both hosts.
+type planParser struct {
+	folders map[int]*PlannedFolder
+	byName  map[string]*PlannedFolder // By source name, for message lines
+	created map[string]bool           // Destination folders to create
+	current *PlannedFolder
+}
+
+// folder returns the folder with number n, adding it when it is new
+func (pp *planParser) folder(n int) *PlannedFolder {
+	if pp.folders == nil {
+		pp.folders = make(map[int]*PlannedFolder)
+		pp.byName = make(map[string]*PlannedFolder)
+	}
+	f, ok := pp.folders[n]
+	if !ok {
+		f = &PlannedFolder{}
+		pp.folders[n] = f
+	}
+	return f
+}
+
+// source names the source folder of folder n
+func (pp *planParser) source(n int, name string) *PlannedFolder {
+	f := pp.folder(n)
+	f.Source = name
+	pp.byName[name] = f
+	return f
+}
+
+// Observe records a line of imapsync output
+func (pp *planParser) Observe(line string) {
+	if m := sizeRe.FindStringSubmatch(line); m != nil {
+		n, _ := strconv.Atoi(m[2])
+		messages, _ := strconv.Atoi(m[4])
+		if m[1] == "1" {
+			pp.source(n, m[3]).SourceMessages = messages
+		} else {
+			pp.folder(n).Destination = m[3]
+		}
+		return
+	}
+	if m := missingRe.FindStringSubmatch(line); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		pp.folder(n).Destination = m[2]
+		pp.markCreated(m[2])
+		return
+	}
+	if m := syncFolderRe.FindStringSubmatch(line); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		pp.current = pp.source(n, m[2])
+		pp.current.Destination = m[3]
+		return
+	}
+	if m := folderRe.FindStringSubmatch(line); m != nil {
+		pp.current = pp.byName[m[1]]
+		return
+	}
+	if m := createRe.FindStringSubmatch(line); m != nil {
+		pp.markCreated(m[1])
+		return
+	}
+	if m := copiedRe.FindStringSubmatch(line); m != nil && pp.current != nil {
+		size, _ := strconv.ParseInt(m[1], 10, 64)
+		pp.current.Messages++
+		pp.current.Bytes += size
+	}
+}
+
+// markCreated records a destination folder that does not exist yet
+func (pp *planParser) markCreated(name string) {
+	if pp.created == nil {
+		pp.created = make(map[string]bool)
+	}
+	pp.created[name] = true
+}
+
+// Plan returns the folders found so far in imapsync's order
+func (pp *planParser) Plan() []PlannedFolder {
+	numbers := make([]int, 0, len(pp.folders))
+	for n := range pp.folders {
+		numbers = append(numbers, n)
+	}
+	sort.Ints(numbers)
+
+	folders := make([]PlannedFolder, 0, len(numbers))
+	for _, n := range numbers {
+		f := *pp.folders[n]
+		if f.Destination == "" {
+			f.Destination = f.Source
+		}
+		f.Create = pp.created[f.Destination]
+		folders = append(folders, f)
+	}
+	return folders
+}
+
+// previewArgs builds the imapsync command line of a dry run of a job
+func previewArgs(job *TransferJob, foldersOnly bool) []string {
+	dry := *job
+	dry.Options.DryRun = true
+	dry.Options.JustFolders = dry.Options.JustFolders || foldersOnly
+	return imapsyncArgs(&dry, fmt.Sprintf("./tmp_%s", job.ID), BandwidthLimit{})
+}
+
+// excludeArgs returns the folder regexes passed with --exclude
+func excludeArgs(args []string) []string {
+	var excludes []string
+	for i := 0; i+1 < len(args); i++ {
+		if args[i] == "--exclude" {
+			excludes = append(excludes, args[i+1])
+		}
+	}
+	return excludes
+}
+
+// previewTransfer runs imapsync with --dry for a job and returns its plan.
+// With foldersOnly it adds --justfolders, which is quick but counts no messages.
+func previewTransfer(ctx context.Context, job *TransferJob, foldersOnly bool) (*TransferPlan, error) {
+	if job.IsArchive() {
+		return previewArchive(ctx, job, foldersOnly)
+	}
+
+	args := previewArgs(job, foldersOnly)
+	plan := &TransferPlan{
+		JobID:       job.ID,
+		Source:      job.SourceEmail,
+		Destination: job.Destination(),
+		FoldersOnly: foldersOnly || job.Options.JustFolders,
+		Filter:      job.Filter.String(),
+		Excludes:    excludeArgs(args),
+		CreatedAt:   time.Now(),
+	}
+
+	cmd := exec.CommandContext(ctx, "imapsync", args...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stdout pipe: %w", err)
+	}
+	cmd.Stderr = cmd.Stdout
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start imapsync: %w", err)
+	}
+
+	var parser planParser
+	var detector failureDetector
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		parser.Observe(scanner.Text())
+		detector.Observe(scanner.Text())
+	}
+	err = cmd.Wait()
+
+	plan.Folders = parser.Plan()
+	for _, f := range plan.Folders {
+		plan.Messages += f.Messages
+		plan.Bytes += f.Bytes
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			err = ctx.Err()
+		} else {
+			err = detector.Classify(err)
+		}
+		plan.Error = err.Error()
+		return plan, err
+	}
+	return plan, nil
+}
+
+// PreviewJob makes the plan of a job with a dry run. The job itself is not
+// changed and may be in any state.
+func (ptm *ParallelTransferManager) PreviewJob(ctx context.Context, jobID string, foldersOnly bool) (*TransferPlan, error) {
+	job, exists := ptm.GetJobStatus(jobID)
+	if !exists {
+		return nil, fmt.Errorf("job %s not found", jobID)
+	}
+	ptm.logger.WithJob(job).Info("Previewing job %s", jobID)
+	return previewTransfer(ctx, job, foldersOnly)
+}
+
+// PreviewBatch makes the plans of the jobs of a batch, one after the other.
+// A job that fails still gets a plan with its error; the first error is returned.
+func (ptm *ParallelTransferManager) PreviewBatch(ctx context.Context, batchID string, foldersOnly bool) ([]*TransferPlan, error) {
+	jobs := ptm.batchJobs(batchID)
+	if len(jobs) == 0 {
+		return nil, fmt.Errorf("batch %s not found", batchID)
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].order < jobs[j].order })
+
+	ptm.logger.Info("Previewing batch %s", batchID)
+	var plans []*TransferPlan
+	var firstErr error
+	for _, job := range jobs {
+		plan, err := previewTransfer(ctx, job, foldersOnly)
+		if plan != nil {
+			plans = append(plans, plan)
+		}
+		if err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("job %s: %w", job.ID, err)
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return plans, firstErr
+}
+
+// PlanJSON encodes plans as indented JSON
+func PlanJSON(plans []*TransferPlan) ([]byte, error) {
+	return json.MarshalIndent(plans, "", "  ")
+}
+
+// SavePlans writes plans as JSON next to the job logs, or to the working
+// directory when logs are not persisted, and returns the path
+func SavePlans(name string, plans []*TransferPlan) (string, error) {
+	data, err := PlanJSON(plans)
+	if err != nil {
+		return "", err
+	}
+
+	dir := "."
+	if cfg := currentLoggingConfig(); cfg != nil {
+		dir = filepath.Join(cfg.Dir, "plans")
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return "", fmt.Errorf("failed to create plan directory: %w", err)
+		}
+	}
+	path := filepath.Join(dir, fmt.Sprintf("plan_%s_%s.json", name, time.Now().Format("20060102_150405")))
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return "", fmt.Errorf("failed to write plan: %w", err)
+	}
+	return path, nil
+}
+
+// formatPlan describes a plan for people, one line per folder
+func formatPlan(plan *TransferPlan) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: %s → %s\n", plan.JobID, plan.Source, plan.Destination)
+	if plan.Error != "" {
+		b.WriteString(i18n.T("plan.error", plan.Error) + "\n")
+	}
+	if len(plan.Excludes) > 0 {
+		b.WriteString(i18n.T("plan.excludes", strings.Join(plan.Excludes, ", ")) + "\n")
+	}
+	if plan.Filter != "" {
+		b.WriteString(i18n.T("plan.filter", plan.Filter) + "\n")
+	}
+	if len(plan.Folders) == 0 {
+		b.WriteString("  " + i18n.T("plan.no_folders") + "\n")
+		return b.String()
+	}
+
+	for _, f := range plan.Folders {
+		name := f.Source
+		if f.Destination != f.Source {
+			name += " → " + f.Destination
+		}
+		if f.Create {
+			name += " " + i18n.T("plan.create")
+		}
+		if plan.FoldersOnly {
+			fmt.Fprintf(&b, "  %s (%s)\n", name, i18n.N("plan.source_messages", f.SourceMessages, f.SourceMessages))
+		} else {
+			fmt.Fprintf(&b, "  %s: %s, %.2f MB\n", name, i18n.N("plan.messages", f.Messages, f.Messages), float64(f.Bytes)/(1024*1024))
+		}
+	}
+
+	created := plan.FoldersToCreate()
+	if plan.FoldersOnly {
+		b.WriteString(i18n.T("plan.total_folders", len(plan.Folders), created) + "\n")
+	} else {
+		b.WriteString(i18n.T("plan.total", len(plan.Folders), created, plan.Messages, float64(plan.Bytes)/(1024*1024)) + "\n")
+	}
+	return b.String()
+}
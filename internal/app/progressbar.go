@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"strings"
 	"time"
+
+	"imapsync/internal/ui"
 )
 
 // ProgressBar represents a simple progress bar
@@ -14,6 +16,7 @@ type ProgressBar struct {
 	description string
 	startTime   time.Time
 	lastUpdate  time.Time
+	lastStep    int // Last 10% step printed without a terminal
 }
 
 // NewProgressBar creates a new progress bar
@@ -25,6 +28,7 @@ func NewProgressBar(total int) *ProgressBar {
 		description: "Progress",
 		startTime:   time.Now(),
 		lastUpdate:  time.Now(),
+		lastStep:    -1,
 	}
 }
 
@@ -73,6 +77,15 @@ func (pb *ProgressBar) render() {
 		eta = time.Duration(float64(elapsed) * float64(pb.total-pb.current) / float64(pb.current))
 	}
 
+	// A file or pipe cannot redraw a line; print one line per 10% step
+	if ui.CurrentTheme().Plain {
+		if step := int(percentage) / 10; step != pb.lastStep {
+			pb.lastStep = step
+			fmt.Printf("%s %d/%d (%.1f%%) ETA: %s\n", pb.description, pb.current, pb.total, percentage, formatDuration(eta))
+		}
+		return
+	}
+
 	// Clear line and print progress
 	fmt.Printf("\r[%s] %s %d/%d (%.1f%%) ETA: %s",
 		bar,
@@ -85,8 +98,11 @@ func (pb *ProgressBar) render() {
 
 // Finish completes the progress bar
 func (pb *ProgressBar) Finish() {
-	pb.Set(pb.total)
-	fmt.Println() // New line after progress bar
+	pb.current = pb.total
+	pb.render()
+	if !ui.CurrentTheme().Plain {
+		fmt.Println() // New line after progress bar
+	}
 }
 
 // formatDuration formats a duration in a human-readable way
@@ -0,0 +1,226 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"imapsync/internal/i18n"
+)
+
+// DefaultMaxJobAttempts caps the failed runs of a job since its last success.
+// Interrupted runs do not count.
+const DefaultMaxJobAttempts = 10
+
+// ErrAttemptsExhausted is returned when a job reached its attempt cap
+var ErrAttemptsExhausted = errors.New("maximum attempts reached")
+
+// JobAttempt records a single imapsync run of a job
+type JobAttempt struct {
+	Number        int
+	StartTime     time.Time
+	EndTime       time.Time
+	ExitCode      int           // imapsync exit code, -1 if it did not exit normally
+	FailureReason FailureReason // Empty when the attempt succeeded or was interrupted
+	Interrupted   bool          // Cancelled, paused or stopped by shutdown; neither a success nor a failure
+	Error         string
+	LogFile       string
+}
+
+// RetryOptions selects failed jobs to retry and the parameters to change.
+// Empty fields match every job or keep the job's current settings.
+type RetryOptions struct {
+	Reason        FailureReason // Only jobs that failed for this reason
+	BatchID       string        // Only jobs of this batch
+	FailedBefore  time.Time     // Only jobs that failed before this time
+	RetryableOnly bool          // Skip reasons whose retry policy is none, e.g. wrong passwords
+
+	SourceHost  string
+	SourcePass  string
+	DestHost    string
+	DestPass    string
+	Bandwidth   BandwidthLimit
+	MaxAttempts int // New attempt cap; jobs at their old cap are retried only if this raises it
+}
+
+// maxAttempts returns the cap on failed runs since the job's last success
+func (job *TransferJob) maxAttempts() int {
+	if job.MaxAttempts > 0 {
+		return job.MaxAttempts
+	}
+	return DefaultMaxJobAttempts
+}
+
+// failuresSinceSuccess counts the failed attempts since the job's last success,
+// skipping interrupted ones. Callers must hold ptm.mu.
+func (job *TransferJob) failuresSinceSuccess() int {
+	n := 0
+	for i := len(job.Attempts) - 1; i >= 0; i-- {
+		attempt := job.Attempts[i]
+		if attempt.Interrupted {
+			continue
+		}
+		if attempt.FailureReason == FailureNone {
+			break
+		}
+		n++
+	}
+	return n
+}
+
+// canAttempt reports whether the job is below its attempt cap
+func (ptm *ParallelTransferManager) canAttempt(job *TransferJob) bool {
+	ptm.mu.RLock()
+	defer ptm.mu.RUnlock()
+	return job.failuresSinceSuccess() < job.maxAttempts()
+}
+
+// recordAttempt appends an imapsync run to the job's history. Runs that were
+// interrupted are recorded as such, not as failures. It reports whether the
+// job has reached its attempt cap.
+func (ptm *ParallelTransferManager) recordAttempt(job *TransferJob, start time.Time, err error, interrupted bool) bool {
+	ptm.mu.Lock()
+	defer ptm.mu.Unlock()
+
+	attempt := JobAttempt{
+		Number:    len(job.Attempts) + 1,
+		StartTime: start,
+		EndTime:   time.Now(),
+		LogFile:   job.LogFile,
+	}
+	if err != nil && interrupted {
+		attempt.ExitCode = -1
+		attempt.Interrupted = true
+	} else if err != nil {
+		attempt.ExitCode = -1
+		attempt.FailureReason = ClassifyError(err)
+		attempt.Error = err.Error()
+
+		var te *TransferError
+		if errors.As(err, &te) {
+			attempt.ExitCode = te.ExitCode
+		}
+	}
+	job.Attempts = append(job.Attempts, attempt)
+
+	return err != nil && !interrupted && job.failuresSinceSuccess() >= job.maxAttempts()
+}
+
+// deferRetry holds a job in the waiting state until its retry delay has passed.
+// The worker requeues it, so its connections and host slots go to other jobs meanwhile.
+func (ptm *ParallelTransferManager) deferRetry(job *TransferJob, deferred *RetryDeferredError) {
+	ptm.mu.Lock()
+	job.retryAt = time.Now().Add(deferred.Delay)
+	job.retryAttempts = deferred.Attempt
+	ptm.mu.Unlock()
+
+	ptm.updateJobStatus(job, StatusWaiting, deferred.Err)
+	time.AfterFunc(deferred.Delay, ptm.releaseWaitingJobs)
+}
+
+// retryDelayed reports whether a deferred retry of the job is not due yet. Callers must hold ptm.mu.
+func (job *TransferJob) retryDelayed() bool {
+	return time.Now().Before(job.retryAt)
+}
+
+// JobAttempts returns a copy of a job's attempt history
+func (ptm *ParallelTransferManager) JobAttempts(jobID string) []JobAttempt {
+	ptm.mu.RLock()
+	defer ptm.mu.RUnlock()
+
+	job, exists := ptm.jobs[jobID]
+	if !exists {
+		return nil
+	}
+	return append([]JobAttempt(nil), job.Attempts...)
+}
+
+// RetryFailed moves the failed jobs matching opts back to pending with the
+// changed parameters applied. A running batch picks them up immediately.
+// It returns how many jobs were retried and how many matched but were skipped
+// because they reached their attempt cap.
+func (ptm *ParallelTransferManager) RetryFailed(opts RetryOptions) (retried, skipped int) {
+	ptm.mu.Lock()
+	var jobs []*TransferJob
+	for _, job := range ptm.jobs {
+		if !ptm.retryMatches(job, opts) {
+			continue
+		}
+
+		if opts.MaxAttempts > 0 {
+			job.MaxAttempts = opts.MaxAttempts
+		}
+		if job.failuresSinceSuccess() >= job.maxAttempts() {
+			skipped++
+			continue
+		}
+
+		if opts.SourceHost != "" {
+			job.SourceHost = opts.SourceHost
+		}
+		if opts.SourcePass != "" {
+			job.SourcePass = opts.SourcePass
+		}
+		if opts.DestHost != "" {
+			job.DestHost = opts.DestHost
+		}
+		if opts.DestPass != "" {
+			job.DestPass = opts.DestPass
+		}
+		if !opts.Bandwidth.IsZero() {
+			job.Bandwidth = opts.Bandwidth
+		}
+
+		job.Status = StatusPending
+		job.Progress = 0
+		job.Error = nil
+		job.FailureReason = FailureNone
+		jobs = append(jobs, job)
+	}
+	if len(jobs) > 0 || skipped > 0 {
+		ptm.logger.Info("Retrying %d failed jobs (%d at their attempt cap)", len(jobs), skipped)
+	}
+	ptm.mu.Unlock()
+
+	for _, job := range jobs {
+		ptm.submitToRunningBatch(job)
+	}
+	return len(jobs), skipped
+}
+
+// retryMatches reports whether a job is selected by opts. Callers must hold ptm.mu.
+func (ptm *ParallelTransferManager) retryMatches(job *TransferJob, opts RetryOptions) bool {
+	if job.Status != StatusFailed {
+		return false
+	}
+	if opts.Reason != FailureNone && job.FailureReason != opts.Reason {
+		return false
+	}
+	if opts.BatchID != "" && job.BatchID != opts.BatchID {
+		return false
+	}
+	if !opts.FailedBefore.IsZero() && !job.EndTime.Before(opts.FailedBefore) {
+		return false
+	}
+	if opts.RetryableOnly && ptm.perfManager.retryPolicy(job.FailureReason).Strategy == RetryNone {
+		return false
+	}
+	return true
+}
+
+// formatAttempt renders one attempt for display
+func formatAttempt(a JobAttempt) string {
+	line := fmt.Sprintf("#%d %s (%s)", a.Number, a.StartTime.Format("2006-01-02 15:04:05"),
+		a.EndTime.Sub(a.StartTime).Round(time.Second))
+	if a.Interrupted {
+		return line + " " + i18n.T("attempt.interrupted")
+	}
+	if a.FailureReason == FailureNone {
+		return line + " " + i18n.T("attempt.ok")
+	}
+	line += " " + i18n.T("attempt.failed", a.FailureReason, a.ExitCode)
+	if a.LogFile != "" {
+		line += ", " + i18n.T("attempt.log", a.LogFile)
+	}
+	return line
+}
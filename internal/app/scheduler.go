@@ -0,0 +1,341 @@
+package app
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// MaintenanceWindowConfig describes a recurring period in which migrations may run
+type MaintenanceWindowConfig struct {
+	Name     string   `json:"name"`
+	Start    string   `json:"start"`    // Cron expression for when the window opens, e.g. "0 22 * * *"
+	Duration Duration `json:"duration"` // How long the window stays open, e.g. "8h"
+}
+
+// SchedulerConfig holds maintenance window settings
+type SchedulerConfig struct {
+	Windows       []MaintenanceWindowConfig `json:"windows"`        // No windows means always open
+	PauseRunning  bool                      `json:"pause_running"`  // Pause running jobs when a window closes
+	CheckInterval Duration                  `json:"check_interval"` // How often windows and delta syncs are evaluated
+
+	RetryFailed      bool     `json:"retry_failed"`       // Requeue failed jobs with a retryable reason while a window is open
+	RetryFailedAfter Duration `json:"retry_failed_after"` // How long a job stays failed before it is retried
+}
+
+// DefaultSchedulerConfig returns default scheduler settings
+func DefaultSchedulerConfig() SchedulerConfig {
+	return SchedulerConfig{
+		CheckInterval:    Duration{30 * time.Second},
+		RetryFailedAfter: Duration{30 * time.Minute},
+	}
+}
+
+// maintenanceWindow is a parsed maintenance window
+type maintenanceWindow struct {
+	name     string
+	start    *CronSchedule
+	duration time.Duration
+}
+
+// openAt reports whether the window is open at t. Every start in the last
+// duration is checked, since back-to-back windows such as an hourly start with
+// a one hour duration close and reopen on the same minute.
+func (mw *maintenanceWindow) openAt(t time.Time) bool {
+	for start := mw.start.Next(t.Add(-mw.duration - time.Minute)); !start.IsZero() && !start.After(t); start = mw.start.Next(start) {
+		if t.Before(start.Add(mw.duration)) {
+			return true
+		}
+	}
+	return false
+}
+
+// SchedulerStatus describes the scheduler state for display
+type SchedulerStatus struct {
+	Running       bool
+	WindowOpen    bool
+	NextOpen      time.Time
+	PendingDeltas map[string]time.Time
+}
+
+// Scheduler starts queued jobs inside maintenance windows and runs recurring delta syncs
+type Scheduler struct {
+	ptm     *ParallelTransferManager
+	config  *SchedulerConfig
+	windows []maintenanceWindow
+	logger  *Logger
+
+	mu        sync.Mutex
+	running   bool
+	open      bool
+	nextDelta map[string]time.Time
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+// NewScheduler creates a scheduler for the given transfer manager
+func NewScheduler(ptm *ParallelTransferManager, config *SchedulerConfig) (*Scheduler, error) {
+	if config == nil {
+		defaults := DefaultSchedulerConfig()
+		config = &defaults
+	}
+	if config.CheckInterval.Duration <= 0 {
+		config.CheckInterval = DefaultSchedulerConfig().CheckInterval
+	}
+
+	s := &Scheduler{
+		ptm:       ptm,
+		config:    config,
+		logger:    NewLogger().WithField("component", "scheduler"),
+		nextDelta: make(map[string]time.Time),
+	}
+
+	for i, wc := range config.Windows {
+		cron, err := ParseCron(wc.Start)
+		if err != nil {
+			return nil, fmt.Errorf("maintenance window %d: %w", i+1, err)
+		}
+		if wc.Duration.Duration <= 0 {
+			return nil, fmt.Errorf("maintenance window %d: duration must be positive", i+1)
+		}
+
+		name := wc.Name
+		if name == "" {
+			name = wc.Start
+		}
+		s.windows = append(s.windows, maintenanceWindow{
+			name:     name,
+			start:    cron,
+			duration: wc.Duration.Duration,
+		})
+	}
+
+	return s, nil
+}
+
+// Start begins evaluating windows in the background
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	if s.running {
+		s.mu.Unlock()
+		return
+	}
+	s.running = true
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+
+	// Force a transition on the first tick so admission matches the current window
+	s.open = !s.WindowOpen(time.Now())
+	s.mu.Unlock()
+
+	go s.run()
+	s.logger.Info("Scheduler started with %d maintenance windows", len(s.windows))
+}
+
+// Stop stops the scheduler and reopens admission
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	if !s.running {
+		s.mu.Unlock()
+		return
+	}
+	s.running = false
+	close(s.stop)
+	done := s.done
+	s.mu.Unlock()
+
+	<-done
+	s.ptm.SetAdmission(true)
+	s.logger.Info("Scheduler stopped")
+}
+
+// IsRunning reports whether the scheduler is active
+func (s *Scheduler) IsRunning() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.running
+}
+
+// run evaluates the schedule until stopped
+func (s *Scheduler) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.config.CheckInterval.Duration)
+	defer ticker.Stop()
+
+	s.tick(time.Now())
+	for {
+		select {
+		case <-s.stop:
+			return
+		case now := <-ticker.C:
+			s.tick(now)
+		}
+	}
+}
+
+// tick applies window transitions, requeues due delta syncs and starts pending jobs
+func (s *Scheduler) tick(now time.Time) {
+	open := s.WindowOpen(now)
+
+	s.mu.Lock()
+	changed := open != s.open
+	s.open = open
+	s.mu.Unlock()
+
+	if changed && open {
+		s.ptm.SetAdmission(true)
+		if resumed := s.ptm.ResumePausedJobs(); resumed > 0 {
+			s.logger.Info("Maintenance window opened, resuming %d paused jobs", resumed)
+		} else {
+			s.logger.Info("Maintenance window opened")
+		}
+	} else if changed {
+		s.ptm.SetAdmission(false)
+		s.logger.Info("Maintenance window closed, no new jobs will start")
+		if s.config.PauseRunning {
+			s.ptm.PauseRunningJobs()
+		}
+	}
+
+	s.scheduleDeltas(now)
+
+	if open && s.config.RetryFailed {
+		retried, _ := s.ptm.RetryFailed(RetryOptions{
+			FailedBefore:  now.Add(-s.config.RetryFailedAfter.Duration),
+			RetryableOnly: true,
+		})
+		if retried > 0 {
+			s.logger.Info("Queued %d failed jobs for retry", retried)
+		}
+	}
+
+	if open && !s.ptm.IsBatchRunning() && s.ptm.GetJobSummary()[StatusPending] > 0 {
+		go s.ptm.StartAllJobs()
+	}
+}
+
+// scheduleDeltas requeues completed jobs whose next delta sync is due
+func (s *Scheduler) scheduleDeltas(now time.Time) {
+	for _, candidate := range s.ptm.deltaCandidates() {
+		if !candidate.cutoverAt.IsZero() && !now.Before(candidate.cutoverAt) {
+			s.mu.Lock()
+			delete(s.nextDelta, candidate.id)
+			s.mu.Unlock()
+			continue
+		}
+
+		s.mu.Lock()
+		next, scheduled := s.nextDelta[candidate.id]
+		if !scheduled {
+			cron, err := ParseCron(candidate.schedule)
+			if err != nil {
+				s.mu.Unlock()
+				continue
+			}
+			next = cron.Next(candidate.endTime)
+			s.nextDelta[candidate.id] = next
+		}
+		s.mu.Unlock()
+
+		if next.IsZero() || now.Before(next) {
+			continue
+		}
+		if !candidate.cutoverAt.IsZero() && !next.Before(candidate.cutoverAt) {
+			continue
+		}
+
+		if err := s.ptm.RequeueJob(candidate.id); err != nil {
+			s.logger.Warn("Failed to queue delta sync for %s: %v", candidate.id, err)
+			continue
+		}
+		s.mu.Lock()
+		delete(s.nextDelta, candidate.id)
+		s.mu.Unlock()
+		s.logger.WithField(FieldJobID, candidate.id).Info("Queued delta sync")
+	}
+}
+
+// WindowOpen reports whether any maintenance window is open at t.
+// Without configured windows the scheduler is always open.
+func (s *Scheduler) WindowOpen(t time.Time) bool {
+	if len(s.windows) == 0 {
+		return true
+	}
+	for i := range s.windows {
+		if s.windows[i].openAt(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// NextWindow returns the next time a maintenance window opens after t
+func (s *Scheduler) NextWindow(t time.Time) time.Time {
+	var next time.Time
+	for i := range s.windows {
+		start := s.windows[i].start.Next(t)
+		if !start.IsZero() && (next.IsZero() || start.Before(next)) {
+			next = start
+		}
+	}
+	return next
+}
+
+// Status returns the current scheduler state
+func (s *Scheduler) Status() SchedulerStatus {
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status := SchedulerStatus{
+		Running:       s.running,
+		WindowOpen:    s.WindowOpen(now),
+		NextOpen:      s.NextWindow(now),
+		PendingDeltas: make(map[string]time.Time, len(s.nextDelta)),
+	}
+	for id, next := range s.nextDelta {
+		status.PendingDeltas[id] = next
+	}
+	return status
+}
+
+// deltaCandidate is a completed job with a delta schedule
+type deltaCandidate struct {
+	id        string
+	schedule  string
+	cutoverAt time.Time
+	endTime   time.Time
+}
+
+// deltaCandidates returns completed jobs that have a delta sync schedule
+func (ptm *ParallelTransferManager) deltaCandidates() []deltaCandidate {
+	ptm.mu.RLock()
+	defer ptm.mu.RUnlock()
+
+	var candidates []deltaCandidate
+	for _, job := range ptm.jobs {
+		if job.Status == StatusCompleted && job.DeltaSchedule != "" {
+			candidates = append(candidates, deltaCandidate{
+				id:        job.ID,
+				schedule:  job.DeltaSchedule,
+				cutoverAt: job.CutoverAt,
+				endTime:   job.EndTime,
+			})
+		}
+	}
+	return candidates
+}
+
+// ParseCutoverDate parses a YYYY-MM-DD date in local time; an empty string means no cut-over
+func ParseCutoverDate(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", s, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid cut-over date %q: expected YYYY-MM-DD", s)
+	}
+	return t, nil
+}
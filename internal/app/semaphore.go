@@ -1,61 +1,119 @@
 package app
 
 import (
+	"container/list"
 	"context"
 	"sync"
 )
 
-// Semaphore provides a simple semaphore implementation
+// Semaphore is a weighted semaphore that serves waiters in FIFO order.
+// Waits honor context cancellation and the size can change at runtime.
 type Semaphore struct {
-	permits int64
+	size    int64
+	permits int64 // size minus held permits; negative after shrinking below the held count
 	mu      sync.Mutex
-	cond    *sync.Cond
+	waiters list.List
+}
+
+// semaphoreWaiter is a blocked Acquire call
+type semaphoreWaiter struct {
+	n     int64
+	ready chan struct{}
 }
 
 // NewSemaphore creates a new semaphore with the given number of permits
 func NewSemaphore(permits int64) *Semaphore {
-	s := &Semaphore{
+	return &Semaphore{
+		size:    permits,
 		permits: permits,
 	}
-	s.cond = sync.NewCond(&s.mu)
-	return s
 }
 
-// Acquire acquires a permit from the semaphore
+// fits reports whether n permits can be granted now. A request larger than
+// the whole semaphore is granted once nothing else is held, so it runs alone
+// instead of blocking the queue forever. Callers must hold s.mu.
+func (s *Semaphore) fits(n int64) bool {
+	if n > s.size {
+		return s.permits >= s.size
+	}
+	return s.permits >= n
+}
+
+// Acquire acquires n permits, blocking until they are available or ctx is done.
+// Waiters are served in the order they arrived.
 func (s *Semaphore) Acquire(ctx context.Context, n int64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	s.mu.Lock()
-	defer s.mu.Unlock()
+	if s.waiters.Len() == 0 && s.fits(n) {
+		s.permits -= n
+		s.mu.Unlock()
+		return nil
+	}
+
+	ready := make(chan struct{})
+	elem := s.waiters.PushBack(semaphoreWaiter{n: n, ready: ready})
+	s.mu.Unlock()
+
+	select {
+	case <-ready:
+		return nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		defer s.mu.Unlock()
 
-	for s.permits < n {
-		// Check if context is cancelled
 		select {
-		case <-ctx.Done():
-			return ctx.Err()
+		case <-ready:
+			// Granted while being cancelled; give the permits back
+			s.permits += n
 		default:
+			s.waiters.Remove(elem)
 		}
-
-		s.cond.Wait()
+		// Leaving the queue may let the waiters behind us proceed
+		s.notifyWaiters()
+		return ctx.Err()
 	}
-
-	s.permits -= n
-	return nil
 }
 
-// Release releases permits back to the semaphore
+// Release releases n permits back to the semaphore
 func (s *Semaphore) Release(n int64) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	s.permits += n
-	s.cond.Broadcast()
+	s.notifyWaiters()
 }
 
-// TryAcquire attempts to acquire a permit without blocking
+// notifyWaiters grants permits to waiters at the front of the queue.
+// It stops at the first waiter that does not fit to keep the order fair.
+// Callers must hold s.mu.
+func (s *Semaphore) notifyWaiters() {
+	for {
+		front := s.waiters.Front()
+		if front == nil {
+			return
+		}
+
+		w := front.Value.(semaphoreWaiter)
+		if !s.fits(w.n) {
+			return
+		}
+
+		s.permits -= w.n
+		s.waiters.Remove(front)
+		close(w.ready)
+	}
+}
+
+// TryAcquire attempts to acquire n permits without blocking.
+// It fails while others are waiting so it cannot jump the queue.
 func (s *Semaphore) TryAcquire(n int64) bool {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if s.permits >= n {
+	if s.waiters.Len() == 0 && s.fits(n) {
 		s.permits -= n
 		return true
 	}
@@ -66,5 +124,40 @@ func (s *Semaphore) TryAcquire(n int64) bool {
 func (s *Semaphore) Available() int64 {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	if s.permits < 0 {
+		return 0
+	}
 	return s.permits
 }
+
+// Size returns the total number of permits
+func (s *Semaphore) Size() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.size
+}
+
+// InUse returns the number of permits currently held
+func (s *Semaphore) InUse() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.size - s.permits
+}
+
+// Waiting returns the number of blocked Acquire calls
+func (s *Semaphore) Waiting() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.waiters.Len()
+}
+
+// Resize changes the total number of permits. When shrinking below the
+// number of held permits, new acquisitions wait until enough are released.
+func (s *Semaphore) Resize(size int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.permits += size - s.size
+	s.size = size
+	s.notifyWaiters()
+}
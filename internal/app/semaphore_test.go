@@ -0,0 +1,246 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// waitFor polls cond until it holds or the test times out
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// acquireAsync starts an Acquire call and returns the channel its result arrives on
+func acquireAsync(ctx context.Context, s *Semaphore, n int64) <-chan error {
+	done := make(chan error, 1)
+	go func() { done <- s.Acquire(ctx, n) }()
+	return done
+}
+
+// queueAcquire starts an Acquire call and waits until it is queued
+func queueAcquire(t *testing.T, ctx context.Context, s *Semaphore, n int64) <-chan error {
+	t.Helper()
+	queued := s.Waiting()
+	done := acquireAsync(ctx, s, n)
+	waitFor(t, "waiter to queue", func() bool { return s.Waiting() == queued+1 })
+	return done
+}
+
+// expectAcquired fails unless the Acquire call finished without error
+func expectAcquired(t *testing.T, done <-chan error, name string) {
+	t.Helper()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("%s: Acquire returned %v", name, err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("%s: Acquire did not return", name)
+	}
+}
+
+// expectBlocked fails if the Acquire call finished
+func expectBlocked(t *testing.T, done <-chan error, name string) {
+	t.Helper()
+	select {
+	case err := <-done:
+		t.Fatalf("%s: Acquire returned %v while it should block", name, err)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestSemaphoreFIFO(t *testing.T) {
+	ctx := context.Background()
+	s := NewSemaphore(1)
+	if err := s.Acquire(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	order := make(chan int, 5)
+	for i := 0; i < 5; i++ {
+		i := i
+		queued := s.Waiting()
+		go func() {
+			if err := s.Acquire(ctx, 1); err == nil {
+				order <- i
+			}
+		}()
+		waitFor(t, "waiter to queue", func() bool { return s.Waiting() == queued+1 })
+	}
+
+	for want := 0; want < 5; want++ {
+		s.Release(1)
+		select {
+		case got := <-order:
+			if got != want {
+				t.Fatalf("waiter %d acquired before waiter %d", got, want)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("waiter %d was not served", want)
+		}
+	}
+	if s.TryAcquire(1) {
+		t.Fatal("TryAcquire succeeded while the last waiter holds the permit")
+	}
+}
+
+func TestSemaphoreWeighted(t *testing.T) {
+	ctx := context.Background()
+	s := NewSemaphore(4)
+	if err := s.Acquire(ctx, 3); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Available(); got != 1 {
+		t.Fatalf("Available() = %d, want 1", got)
+	}
+
+	// A small request behind a large one waits, even though it would fit
+	large := queueAcquire(t, ctx, s, 2)
+	small := queueAcquire(t, ctx, s, 1)
+	expectBlocked(t, large, "large")
+	expectBlocked(t, small, "small")
+	if s.TryAcquire(1) {
+		t.Fatal("TryAcquire jumped the queue")
+	}
+
+	s.Release(3)
+	expectAcquired(t, large, "large")
+	expectAcquired(t, small, "small")
+	if got := s.InUse(); got != 3 {
+		t.Fatalf("InUse() = %d, want 3", got)
+	}
+
+	// A request larger than the semaphore runs alone once everything is released
+	s.Release(3)
+	if err := s.Acquire(ctx, 10); err != nil {
+		t.Fatal(err)
+	}
+	if s.TryAcquire(1) {
+		t.Fatal("TryAcquire succeeded while an oversized request holds the semaphore")
+	}
+}
+
+func TestSemaphoreCancel(t *testing.T) {
+	s := NewSemaphore(2)
+	if err := s.Acquire(context.Background(), 2); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := queueAcquire(t, ctx, s, 1)
+	cancel()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("Acquire returned %v, want context.Canceled", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Acquire did not return promptly after cancellation")
+	}
+	if n := s.Waiting(); n != 0 {
+		t.Fatalf("Waiting() = %d after cancellation, want 0", n)
+	}
+
+	if err := s.Acquire(ctx, 1); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Acquire with a done context returned %v", err)
+	}
+}
+
+func TestSemaphoreCancelHeadWaiter(t *testing.T) {
+	bg := context.Background()
+	s := NewSemaphore(3)
+	if err := s.Acquire(bg, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	// The head needs 2 of the 1 free permit and blocks the waiters behind it
+	ctx, cancel := context.WithCancel(bg)
+	head := queueAcquire(t, ctx, s, 2)
+	first := queueAcquire(t, bg, s, 1)
+	second := queueAcquire(t, bg, s, 1)
+	expectBlocked(t, first, "first")
+
+	cancel()
+	if err := <-head; !errors.Is(err, context.Canceled) {
+		t.Fatalf("head Acquire returned %v, want context.Canceled", err)
+	}
+
+	// Removing the head lets the next waiter that fits proceed right away
+	expectAcquired(t, first, "first")
+	expectBlocked(t, second, "second")
+
+	s.Release(1)
+	expectAcquired(t, second, "second")
+}
+
+func TestSemaphoreResize(t *testing.T) {
+	ctx := context.Background()
+	s := NewSemaphore(2)
+	if err := s.Acquire(ctx, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	a := queueAcquire(t, ctx, s, 1)
+	b := queueAcquire(t, ctx, s, 2)
+	c := queueAcquire(t, ctx, s, 1)
+
+	// Growing grants the waiters in order while they fit
+	s.Resize(5)
+	expectAcquired(t, a, "a")
+	expectAcquired(t, b, "b")
+	expectBlocked(t, c, "c")
+	if got := s.Size(); got != 5 {
+		t.Fatalf("Size() = %d, want 5", got)
+	}
+
+	// Shrinking below the held permits makes waiters wait for enough releases
+	s.Resize(3)
+	if got := s.Available(); got != 0 {
+		t.Fatalf("Available() = %d after shrinking, want 0", got)
+	}
+	s.Release(2)
+	expectBlocked(t, c, "c")
+	s.Release(1)
+	expectAcquired(t, c, "c")
+	if got := s.InUse(); got != 3 {
+		t.Fatalf("InUse() = %d, want 3", got)
+	}
+}
+
+func TestSemaphoreConcurrent(t *testing.T) {
+	ctx := context.Background()
+	s := NewSemaphore(3)
+	done := make(chan struct{})
+	for i := 0; i < 20; i++ {
+		n := int64(i%3 + 1)
+		go func() {
+			defer func() { done <- struct{}{} }()
+			for j := 0; j < 50; j++ {
+				if err := s.Acquire(ctx, n); err != nil {
+					t.Error(err)
+					return
+				}
+				if used := s.InUse(); used > 3 {
+					t.Errorf("InUse() = %d exceeds the size", used)
+				}
+				s.Release(n)
+			}
+		}()
+	}
+	for i := 0; i < 20; i++ {
+		<-done
+	}
+	if used := s.InUse(); used != 0 {
+		t.Fatalf("InUse() = %d after all releases, want 0", used)
+	}
+}
@@ -1,13 +1,12 @@
 package app
 
 import (
-	"bufio"
 	"fmt"
-	"os"
 	"os/exec"
 	"runtime"
 	"strings"
 
+	"imapsync/internal/i18n"
 	"imapsync/internal/ui"
 )
 
@@ -27,7 +26,7 @@ func SetupSystem() {
 		fmt.Println(ui.Red("Python ✗"))
 		switch runtime.GOOS {
 		case "windows":
-			fmt.Println("Download Python: https://www.python.org/downloads/windows/")
+			fmt.Println(i18n.T("setup.download_python", "https://www.python.org/downloads/windows/"))
 		case "darwin":
 			fmt.Println("brew install python")
 		default:
@@ -51,14 +50,14 @@ func SetupSystem() {
 	}
 
 	if pythonOK && imapOK {
-		fmt.Println(ui.Green("System Setup ✅"))
+		fmt.Println(ui.Green(i18n.T("setup.done") + " ✅"))
 	}
 }
 
 // promptInstall lets user choose a local install script and executes it via bash.
 func promptInstall() {
-	reader := bufio.NewReader(os.Stdin)
-	fmt.Println("Local install scripts available in ./install directory:")
+	reader := ui.Stdin()
+	fmt.Println(i18n.T("setup.scripts"))
 	scripts := map[string]string{
 		"ubuntu": "install/ubuntu.txt",
 		"debian": "install/debian.txt",
@@ -69,17 +68,17 @@ func promptInstall() {
 	for k := range scripts {
 		fmt.Println(" -", k)
 	}
-	fmt.Print("Enter distribution key to run installer or press Enter to skip: ")
+	fmt.Print(i18n.T("prompt.distribution") + " ")
 	choice, _ := reader.ReadString('\n')
 	choice = strings.TrimSpace(strings.ToLower(choice))
 	path, ok := scripts[choice]
 	if !ok || choice == "" {
-		fmt.Println("Skipping automatic install. Follow manual instructions in INSTALL.d directory.")
+		fmt.Println(i18n.T("setup.skipping"))
 		return
 	}
-	fmt.Println(ui.Cyan("Running installation script:"), path)
+	fmt.Println(ui.Cyan(i18n.T("setup.running_script")), path)
 	if err := exec.Command("bash", path).Run(); err != nil {
-		fmt.Println(ui.Red("Installer failed:"), err)
+		fmt.Println(ui.Red(i18n.T("setup.installer_failed")), err)
 	}
 }
 
@@ -93,7 +92,7 @@ func installImapsync() {
 		} else if checkBinary("scoop") {
 			exec.Command("scoop", "install", "imapsync").Run()
 		} else {
-			fmt.Println("Please install Chocolatey or Scoop, or download the binary from https://imapsync.lamiral.info/")
+			fmt.Println(i18n.T("setup.windows_manual", "https://imapsync.lamiral.info/"))
 		}
 	case "darwin":
 		exec.Command("brew", "install", "imapsync").Run()
@@ -104,7 +103,7 @@ func installImapsync() {
 		} else if checkBinary("yum") {
 			exec.Command("sudo", "yum", "install", "-y", "imapsync").Run()
 		} else {
-			fmt.Println("Please install imapsync manually: https://imapsync.lamiral.info/#install")
+			fmt.Println(i18n.T("setup.manual", "https://imapsync.lamiral.info/#install"))
 		}
 	}
 }
@@ -1,47 +1,71 @@
 package app
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
+	"imapsync/internal/i18n"
 	"imapsync/internal/ui"
 )
 
-// LogEntry represents a log entry
-type LogEntry struct {
-	Time    time.Time
-	Type    string
-	Message string
-}
-
 // SimpleInterface manages the simple TUI application
 type SimpleInterface struct {
 	tui         *ui.SimpleTUI
 	perfManager *PerformanceManager
 	parallelMgr *ParallelTransferManager
-	lang        string
-	logs        []LogEntry
+	history     *Logger
+	scheduler   *Scheduler
 }
 
 // NewSimpleInterface creates a new simple interface
 func NewSimpleInterface() *SimpleInterface {
-	return &SimpleInterface{
+	perfManager := NewPerformanceManager(nil)
+	perfManager.SetRetryPolicies(CurrentConfig().Retry)
+	cacheErr := perfManager.ConfigureCache(&CurrentConfig().Cache)
+	parallelMgr := NewParallelTransferManager(perfManager)
+	parallelMgr.SetHostLimits(&CurrentConfig().HostLimits)
+	parallelMgr.SetAdaptiveConcurrency(&CurrentConfig().AdaptiveConcurrency)
+	parallelMgr.SetCircuitBreaker(&CurrentConfig().CircuitBreaker)
+	bandwidthErr := parallelMgr.SetBandwidthLimits(&CurrentConfig().Bandwidth)
+	for _, n := range CurrentConfig().Notifiers() {
+		parallelMgr.AddNotifier(n)
+	}
+
+	si := &SimpleInterface{
 		tui:         ui.NewSimpleTUI(),
-		perfManager: NewPerformanceManager(nil),
-		parallelMgr: NewParallelTransferManager(NewPerformanceManager(nil)),
-		lang:        "en",
-		logs:        make([]LogEntry, 0),
+		perfManager: perfManager,
+		parallelMgr: parallelMgr,
+		history:     NewPersistentLogger(),
+	}
+
+	if scheduler, err := NewScheduler(parallelMgr, &CurrentConfig().Scheduler); err == nil {
+		si.scheduler = scheduler
+	} else {
+		si.addLog("error", "Scheduler disabled: "+err.Error())
+	}
+	if bandwidthErr != nil {
+		si.addLog("error", "Bandwidth limits disabled: "+bandwidthErr.Error())
 	}
+	if cacheErr != nil {
+		si.addLog("error", "Cache not loaded: "+cacheErr.Error())
+	}
+
+	return si
 }
 
-// addLog adds a log entry
+// addLog records a history entry in the persisted application log
 func (si *SimpleInterface) addLog(logType, message string) {
-	si.logs = append(si.logs, LogEntry{
-		Time:    time.Now(),
-		Type:    logType,
-		Message: message,
-	})
+	logger := si.history.WithField("type", logType)
+	if logType == "error" {
+		logger.Error("%s", message)
+	} else {
+		logger.Info("%s", message)
+	}
 }
 
 // Run starts the simple interface
@@ -57,15 +81,16 @@ func (si *SimpleInterface) Run() {
 // showMainMenu displays the main menu
 func (si *SimpleInterface) showMainMenu() int {
 	items := []string{
-		"🔧 Setup System",
-		"📧 Transfer Mail",
-		"⚡ Parallel Transfer",
-		"📊 Performance Stats",
-		"📜 History/Logs",
-		"👨‍💻 Developer Info",
+		"🔧 " + i18n.T("menu.main.setup"),
+		"📧 " + i18n.T("menu.main.transfer"),
+		"⚡ " + i18n.T("menu.main.parallel"),
+		"📊 " + i18n.T("menu.main.stats"),
+		"📜 " + i18n.T("menu.main.logs"),
+		"⚙️ " + i18n.T("menu.main.settings"),
+		"👨‍💻 " + i18n.T("menu.main.developer"),
 	}
 
-	choice := si.tui.ShowMenu("IMAPSYNC CLI - "+strings.ToUpper(si.lang), items)
+	choice := si.tui.ShowMenu("IMAPSYNC CLI - "+strings.ToUpper(i18n.Language()), items)
 
 	switch choice {
 	case 0:
@@ -79,6 +104,8 @@ func (si *SimpleInterface) showMainMenu() int {
 	case 4:
 		si.showLogs()
 	case 5:
+		si.showSettings()
+	case 6:
 		si.showDeveloperInfo()
 	case -1:
 		return -1
@@ -90,11 +117,11 @@ func (si *SimpleInterface) showMainMenu() int {
 // showSetupMenu displays the setup menu
 func (si *SimpleInterface) showSetupMenu() {
 	items := []string{
-		"🔍 Check Dependencies",
-		"📦 Install imapsync",
+		"🔍 " + i18n.T("menu.setup.check"),
+		"📦 " + i18n.T("menu.setup.install"),
 	}
 
-	choice := si.tui.ShowMenu("System Setup", items)
+	choice := si.tui.ShowMenu(i18n.T("menu.setup.title"), items)
 
 	switch choice {
 	case 0:
@@ -106,30 +133,59 @@ func (si *SimpleInterface) showSetupMenu() {
 
 // showTransferForm displays the transfer form
 func (si *SimpleInterface) showTransferForm() {
-	fields := []string{
-		"Source IMAP Host",
-		"Source Email",
-		"Source Password",
-		"Destination IMAP Host",
-		"Destination Email",
-		"Destination Password",
-	}
+	fields := append(mailboxFields(),
+		ui.FormField{Label: i18n.T("form.ssl"), Kind: ui.FieldBool, Default: "true"},
+		ui.FormField{Label: i18n.T("form.dry_run"), Kind: ui.FieldBool, Default: "false"},
+		ui.FormField{Label: i18n.T("form.folders")},
+		ui.FormField{Label: i18n.T("form.exclude_folders")},
+		filterField(),
+	)
 
-	data := si.tui.ShowForm("Mail Transfer Configuration", fields)
+	data := si.tui.ShowForm(i18n.T("transfer.form.title"), fields)
+	if data == nil {
+		return
+	}
 	si.executeTransfer(data)
 }
 
+// mailboxFields are the form fields of a source and a destination mailbox.
+// Logins are free text: IMAP user names such as "jdoe" or "DOMAIN\user" are
+// not always email addresses.
+func mailboxFields() []ui.FormField {
+	return []ui.FormField{
+		{Label: i18n.T("form.source_host"), Kind: ui.FieldHost, Required: true},
+		{Label: i18n.T("form.source_email"), Kind: ui.FieldText, Required: true},
+		{Label: i18n.T("form.source_password"), Kind: ui.FieldPassword, Required: true},
+		{Label: i18n.T("form.dest_host"), Kind: ui.FieldHost, Required: true},
+		{Label: i18n.T("form.dest_email"), Kind: ui.FieldText, Required: true},
+		{Label: i18n.T("form.dest_password"), Kind: ui.FieldPassword, Required: true},
+	}
+}
+
+// filterField is the form field of a message filter
+func filterField() ui.FormField {
+	return ui.FormField{Label: i18n.T("form.filter"), Validate: func(value string) error {
+		_, err := ParseFilter(value)
+		return err
+	}}
+}
+
 // showParallelTransferMenu displays the parallel transfer menu
 func (si *SimpleInterface) showParallelTransferMenu() {
 	items := []string{
-		"➕ Add Transfer Job",
-		"▶️ Start All Jobs",
-		"📋 View Job Status",
-		"❌ Cancel Job",
-		"📊 Show Summary",
+		"➕ " + i18n.T("menu.parallel.add"),
+		"▶️ " + i18n.T("menu.parallel.start"),
+		"📺 " + i18n.T("menu.parallel.dashboard"),
+		"❌ " + i18n.T("menu.parallel.cancel"),
+		"📊 " + i18n.T("menu.parallel.summary"),
+		"🕒 " + i18n.T("menu.parallel.scheduler"),
+		"🔌 " + i18n.T("menu.parallel.health"),
+		"📦 " + i18n.T("menu.parallel.batches"),
+		"🔁 " + i18n.T("menu.parallel.retry"),
+		"🗄️ " + i18n.T("menu.parallel.add_archive"),
 	}
 
-	choice := si.tui.ShowMenu("Parallel Transfer Manager", items)
+	choice := si.tui.ShowMenu(i18n.T("menu.parallel.title"), items)
 
 	switch choice {
 	case 0:
@@ -137,84 +193,350 @@ func (si *SimpleInterface) showParallelTransferMenu() {
 	case 1:
 		si.startAllJobs()
 	case 2:
-		si.showJobStatus()
+		si.showDashboard()
 	case 3:
 		si.showCancelJobForm()
 	case 4:
 		si.showJobSummary()
+	case 5:
+		si.showScheduler()
+	case 6:
+		si.showServerHealth()
+	case 7:
+		si.showBatches()
+	case 8:
+		si.showRetryFailedForm()
+	case 9:
+		si.showAddArchiveJobForm()
+	}
+}
+
+// showServerHealth displays the circuit breakers and lets the user reset one
+func (si *SimpleInterface) showServerHealth() {
+	statuses := si.parallelMgr.BreakerStatus()
+
+	if len(statuses) == 0 {
+		si.tui.ShowModal(i18n.T("health.title"), i18n.T("health.all_healthy"), []string{i18n.T("button.ok")})
+		return
+	}
+
+	content := i18n.T("health.breakers") + "\n\n"
+	for _, s := range statuses {
+		content += i18n.T("health.host", s.Host) + "\n"
+		content += i18n.T("health.state", s.State) + "\n"
+		content += i18n.T("health.failures", s.Failures) + "\n"
+		if s.State == CircuitOpen {
+			content += i18n.T("health.next_probe", s.NextProbe.Format("15:04:05")) + "\n"
+		}
+		if s.LastError != "" {
+			content += i18n.T("health.last_error", s.LastError) + "\n"
+		}
+		content += "---\n"
+	}
+
+	if si.tui.ShowModal(i18n.T("health.title"), content, []string{i18n.T("health.reset_button"), i18n.T("button.ok")}) != 0 {
+		return
+	}
+
+	hosts := make([]string, len(statuses))
+	for i, s := range statuses {
+		hosts[i] = s.Host
+	}
+	hostField := ui.FormField{Label: i18n.T("form.host"), Kind: ui.FieldSelect, Options: hosts, Required: true}
+	data := si.tui.ShowForm(i18n.T("health.reset_title"), []ui.FormField{hostField})
+	if data == nil {
+		return
+	}
+	host := data[hostField.Label]
+	si.parallelMgr.ResetBreaker(host)
+	si.addLog("info", "Circuit breaker reset for "+host)
+	si.tui.PrintSuccess(i18n.T("health.reset_done"))
+	si.tui.WaitForKey()
+}
+
+// showScheduler displays the scheduler state and lets the user start or stop it
+func (si *SimpleInterface) showScheduler() {
+	if si.scheduler == nil {
+		si.tui.ShowModal(i18n.T("scheduler.title"), i18n.T("scheduler.invalid"), []string{i18n.T("button.ok")})
+		return
+	}
+
+	status := si.scheduler.Status()
+	content := i18n.T("scheduler.heading") + "\n\n"
+	if status.Running {
+		content += i18n.T("scheduler.state_running") + "\n"
+	} else {
+		content += i18n.T("scheduler.state_stopped") + "\n"
+	}
+	if status.WindowOpen {
+		content += i18n.T("scheduler.window_open") + "\n"
+	} else {
+		content += i18n.T("scheduler.window_closed") + "\n"
+	}
+	if !status.NextOpen.IsZero() {
+		content += i18n.T("scheduler.next_window", status.NextOpen.Format("2006-01-02 15:04")) + "\n"
+	}
+	if len(status.PendingDeltas) > 0 {
+		content += "\n" + i18n.T("scheduler.deltas") + "\n"
+		for id, next := range status.PendingDeltas {
+			content += fmt.Sprintf("%s: %s\n", id, next.Format("2006-01-02 15:04"))
+		}
+	}
+
+	action := i18n.T("button.start")
+	if status.Running {
+		action = i18n.T("button.stop")
+	}
+
+	if si.tui.ShowModal(i18n.T("scheduler.title"), content, []string{action, i18n.T("button.cancel")}) != 0 {
+		return
+	}
+	if status.Running {
+		si.scheduler.Stop()
+		si.addLog("info", "Scheduler stopped")
+	} else {
+		si.scheduler.Start()
+		si.addLog("info", "Scheduler started")
+	}
+}
+
+// showBatches displays batch summaries and lets the user start, preview or cancel a batch
+func (si *SimpleInterface) showBatches() {
+	batchIDs := si.parallelMgr.BatchIDs()
+	if len(batchIDs) == 0 {
+		si.tui.ShowModal(i18n.T("batches.title"), i18n.T("batches.none"), []string{i18n.T("button.ok")})
+		return
+	}
+
+	content := i18n.T("batches.title") + ":\n\n"
+	for _, batchID := range batchIDs {
+		content += fmt.Sprintf("%s: %s\n", batchID, formatSummary(si.parallelMgr.GetBatchSummary(batchID)))
+	}
+
+	buttons := []string{i18n.T("batches.start"), i18n.T("batches.preview"), i18n.T("batches.cancel"), i18n.T("button.ok")}
+	choice := si.tui.ShowModal(i18n.T("batches.title"), content, buttons)
+	if choice < 0 || choice > 2 {
+		return
+	}
+
+	fields := []ui.FormField{{Label: i18n.T("form.batch_id"), Kind: ui.FieldSelect, Options: batchIDs, Required: true}}
+	if choice == 1 {
+		fields = append(fields, ui.FormField{Label: i18n.T("form.just_folders"), Kind: ui.FieldBool, Default: "false"})
+	}
+	data := si.tui.ShowForm(i18n.T("batches.form_title"), fields)
+	if data == nil {
+		return
+	}
+	batchID := data[fields[0].Label]
+
+	switch choice {
+	case 0:
+		si.addLog("info", "Starting batch "+batchID)
+		if err := si.parallelMgr.StartBatch(batchID); err != nil {
+			si.tui.PrintError(i18n.T("batches.start_failed", err))
+		} else {
+			summary := formatSummary(si.parallelMgr.GetBatchSummary(batchID))
+			si.tui.PrintSuccess(i18n.T("batches.finished", summary))
+			si.addLog("success", "Batch "+batchID+" finished: "+summary)
+		}
+		si.tui.WaitForKey()
+		return
+	case 1:
+		si.tui.ShowLoading(i18n.T("plan.running"))
+		si.addLog("info", "Previewing batch "+batchID)
+		plans, err := si.parallelMgr.PreviewBatch(context.Background(), batchID, data.Bool(i18n.T("form.just_folders")))
+		if err != nil {
+			si.addLog("error", "Batch preview failed: "+err.Error())
+		}
+		si.showPlans(batchID, plans, err)
+		return
+	}
+
+	cancelled, err := si.parallelMgr.CancelBatch(batchID)
+	if err != nil {
+		si.tui.PrintError(i18n.T("batches.cancel_failed", err))
+	} else {
+		si.tui.PrintSuccess(i18n.N("jobs.cancelled", cancelled, cancelled))
+		si.addLog("info", fmt.Sprintf("Cancelled %d jobs of batch %s", cancelled, batchID))
+	}
+	si.tui.WaitForKey()
+}
+
+// showRetryFailedForm requeues failed jobs, optionally with changed parameters
+func (si *SimpleInterface) showRetryFailedForm() {
+	fields := []ui.FormField{
+		{Label: i18n.T("form.failure_reason"), Kind: ui.FieldSelect, Options: failureReasons()},
+		{Label: i18n.T("form.batch_id_optional"), Kind: ui.FieldSelect, Options: si.parallelMgr.BatchIDs()},
+		{Label: i18n.T("form.new_source_host"), Kind: ui.FieldHost},
+		{Label: i18n.T("form.new_source_password"), Kind: ui.FieldPassword},
+		{Label: i18n.T("form.new_dest_host"), Kind: ui.FieldHost},
+		{Label: i18n.T("form.new_dest_password"), Kind: ui.FieldPassword},
+		{Label: i18n.T("form.new_attempt_cap"), Kind: ui.FieldNumber, Validate: func(value string) error {
+			if n, _ := strconv.Atoi(value); n <= 0 {
+				return errors.New(i18n.T("retry.invalid_cap"))
+			}
+			return nil
+		}},
+	}
+	data := si.tui.ShowForm(i18n.T("retry.title"), fields)
+	if data == nil {
+		return
+	}
+
+	opts := RetryOptions{
+		Reason:      FailureReason(data[fields[0].Label]),
+		BatchID:     data[fields[1].Label],
+		SourceHost:  data[fields[2].Label],
+		SourcePass:  data[fields[3].Label],
+		DestHost:    data[fields[4].Label],
+		DestPass:    data[fields[5].Label],
+		MaxAttempts: data.Int(fields[6].Label),
+	}
+
+	retried, skipped := si.parallelMgr.RetryFailed(opts)
+	si.addLog("info", fmt.Sprintf("Requeued %d failed jobs", retried))
+	si.tui.PrintSuccess(i18n.N("retry.requeued", retried, retried))
+	if skipped > 0 {
+		si.tui.PrintError(i18n.N("retry.skipped", skipped, skipped))
 	}
+	si.tui.WaitForKey()
 }
 
 // showAddJobForm displays the add job form
 func (si *SimpleInterface) showAddJobForm() {
-	fields := []string{
-		"Source IMAP Host",
-		"Source Email",
-		"Source Password",
-		"Destination IMAP Host",
-		"Destination Email",
-		"Destination Password",
-	}
+	fields := append(mailboxFields(),
+		ui.FormField{Label: i18n.T("form.delta_cron"), Validate: func(value string) error {
+			_, err := ParseCron(value)
+			return err
+		}},
+		ui.FormField{Label: i18n.T("form.cutover_date"), Validate: func(value string) error {
+			_, err := ParseCutoverDate(value)
+			return err
+		}},
+		ui.FormField{Label: i18n.T("form.priority"), Kind: ui.FieldNumber},
+		ui.FormField{Label: i18n.T("form.tags")},
+		ui.FormField{Label: i18n.T("form.batch_id_optional")},
+		ui.FormField{Label: i18n.T("form.depends_on")},
+		filterField(),
+		ui.FormField{Label: i18n.T("form.dry_run"), Kind: ui.FieldBool, Default: "false"},
+		ui.FormField{Label: i18n.T("form.just_folders"), Kind: ui.FieldBool, Default: "false"},
+	)
 
-	data := si.tui.ShowForm("Add Transfer Job", fields)
+	data := si.tui.ShowForm(i18n.T("jobs.add_title"), fields)
+	if data == nil {
+		return
+	}
 	si.addTransferJob(data)
 }
 
+// showAddArchiveJobForm displays the form of a job that exports a mailbox to
+// local mbox or Maildir files
+func (si *SimpleInterface) showAddArchiveJobForm() {
+	fields := []ui.FormField{
+		{Label: i18n.T("form.source_host"), Kind: ui.FieldHost, Required: true},
+		{Label: i18n.T("form.source_email"), Kind: ui.FieldText, Required: true},
+		{Label: i18n.T("form.source_password"), Kind: ui.FieldPassword, Required: true},
+		{Label: i18n.T("form.archive_format"), Kind: ui.FieldSelect, Options: archiveFormats(), Default: string(ArchiveMbox), Required: true},
+		{Label: i18n.T("form.archive_dir"), Required: true},
+		{Label: i18n.T("form.ssl"), Kind: ui.FieldBool, Default: "true"},
+		{Label: i18n.T("form.folders")},
+		{Label: i18n.T("form.exclude_folders")},
+		filterField(),
+		{Label: i18n.T("form.batch_id_optional")},
+		{Label: i18n.T("form.dry_run"), Kind: ui.FieldBool, Default: "false"},
+	}
+
+	data := si.tui.ShowForm(i18n.T("jobs.add_archive_title"), fields)
+	if data == nil {
+		return
+	}
+
+	job := &TransferJob{
+		SourceHost:  data[i18n.T("form.source_host")],
+		SourceEmail: data[i18n.T("form.source_email")],
+		SourcePass:  data[i18n.T("form.source_password")],
+		Archive: ArchiveTarget{
+			Format: ArchiveFormat(data[i18n.T("form.archive_format")]),
+			Dir:    data[i18n.T("form.archive_dir")],
+		},
+		BatchID: data[i18n.T("form.batch_id_optional")],
+		Options: TransferOptions{
+			Folders:        splitList(data[i18n.T("form.folders")]),
+			ExcludeFolders: splitList(data[i18n.T("form.exclude_folders")]),
+			NoSSL:          !data.Bool(i18n.T("form.ssl")),
+			DryRun:         data.Bool(i18n.T("form.dry_run")),
+		},
+	}
+	// The form has checked the filter
+	job.Filter, _ = ParseFilter(data[i18n.T("form.filter")])
+
+	if err := si.parallelMgr.AddJob(job); err != nil {
+		si.tui.PrintError(i18n.T("jobs.add_failed", err))
+		si.addLog("error", "Failed to add archive job: "+err.Error())
+	} else {
+		si.tui.PrintSuccess(i18n.T("jobs.add_done"))
+		si.addLog("success", "Archive job added successfully")
+	}
+	si.tui.WaitForKey()
+}
+
 // showCancelJobForm displays the cancel job form
 func (si *SimpleInterface) showCancelJobForm() {
-	fields := []string{"Job ID"}
-	data := si.tui.ShowForm("Cancel Transfer Job", fields)
+	jobField := ui.FormField{Label: i18n.T("form.job_id"), Required: true}
+	data := si.tui.ShowForm(i18n.T("jobs.cancel_title"), []ui.FormField{jobField})
+	if data == nil {
+		return
+	}
 
-	jobID := data["Job ID"]
+	jobID := data[jobField.Label]
 	if err := si.parallelMgr.CancelJob(jobID); err != nil {
-		si.tui.PrintError("Failed to cancel job: " + err.Error())
+		si.tui.PrintError(i18n.T("jobs.cancel_failed", err))
 	} else {
-		si.tui.PrintSuccess("Job cancelled successfully!")
+		si.tui.PrintSuccess(i18n.T("jobs.cancel_done"))
 	}
 	si.tui.WaitForKey()
 }
 
 // checkDependencies checks system dependencies
 func (si *SimpleInterface) checkDependencies() {
-	content := "Checking system dependencies...\n\n"
+	content := i18n.T("setup.checking") + "\n\n"
 
 	// Check Python
 	pythonOK := checkBinary("python") || checkBinary("python3")
 	if pythonOK {
-		content += "✅ Python: Available\n"
+		content += "✅ " + i18n.T("setup.available", "Python") + "\n"
 	} else {
-		content += "❌ Python: Not found\n"
+		content += "❌ " + i18n.T("setup.not_found", "Python") + "\n"
 	}
 
 	// Check imapsync
 	imapOK := checkBinary("imapsync")
 	if imapOK {
-		content += "✅ imapsync: Available\n"
+		content += "✅ " + i18n.T("setup.available", "imapsync") + "\n"
 	} else {
-		content += "❌ imapsync: Not found\n"
+		content += "❌ " + i18n.T("setup.not_found", "imapsync") + "\n"
 	}
 
 	if pythonOK && imapOK {
-		content += "\n🎉 All dependencies are satisfied!"
+		content += "\n🎉 " + i18n.T("setup.all_ok")
 	} else {
-		content += "\n⚠️ Some dependencies are missing. Use 'Install imapsync' to install."
+		content += "\n⚠️ " + i18n.T("setup.missing", i18n.T("menu.setup.install"))
 	}
 
-	si.tui.ShowModal("Dependency Check", content, []string{"OK"})
+	si.tui.ShowModal(i18n.T("setup.check_title"), content, []string{i18n.T("button.ok")})
 }
 
 // installImapsync installs imapsync
 func (si *SimpleInterface) installImapsync() {
-	content := "Installing imapsync...\n\n"
-	content += "This will attempt to install imapsync using your system's package manager.\n"
-	content += "You may need to provide sudo password.\n\n"
-	content += "Supported systems:\n"
+	content := i18n.T("setup.installing") + "\n\n"
+	content += i18n.T("setup.install_intro") + "\n\n"
+	content += i18n.T("setup.supported") + "\n"
 	content += "• Ubuntu/Debian (apt)\n"
 	content += "• CentOS/RHEL (yum)\n"
 	content += "• Arch Linux (pacman)\n"
 	content += "• macOS (brew)"
 
-	choice := si.tui.ShowModal("Install imapsync", content, []string{"Install", "Cancel"})
+	choice := si.tui.ShowModal(i18n.T("menu.setup.install"), content, []string{i18n.T("button.install"), i18n.T("button.cancel")})
 	if choice == 0 {
 		si.performInstall()
 	}
@@ -222,102 +544,352 @@ func (si *SimpleInterface) installImapsync() {
 
 // performInstall performs the actual installation
 func (si *SimpleInterface) performInstall() {
-	content := "Installing imapsync...\n\n"
-	content += "Installation completed successfully!\n"
-	content += "imapsync is now available in your system."
+	content := i18n.T("setup.installing") + "\n\n"
+	content += i18n.T("setup.install_done") + "\n"
+	content += i18n.T("setup.install_available")
 
-	si.tui.ShowModal("Installation Complete", content, []string{"OK"})
+	si.tui.ShowModal(i18n.T("setup.install_done_title"), content, []string{i18n.T("button.ok")})
 }
 
-// executeTransfer executes a mail transfer
-func (si *SimpleInterface) executeTransfer(data map[string]string) {
-	si.tui.PrintInfo("Transferring mail...")
-	si.addLog("info", "Starting mail transfer")
+// executeTransfer runs a mail transfer from the transfer form in the
+// foreground, showing the imapsync progress as it goes
+func (si *SimpleInterface) executeTransfer(data ui.FormValues) {
+	job := &TransferJob{
+		SourceHost:  data[i18n.T("form.source_host")],
+		SourceEmail: data[i18n.T("form.source_email")],
+		SourcePass:  data[i18n.T("form.source_password")],
+		DestHost:    data[i18n.T("form.dest_host")],
+		DestEmail:   data[i18n.T("form.dest_email")],
+		DestPass:    data[i18n.T("form.dest_password")],
+		Options: TransferOptions{
+			Folders:        splitList(data[i18n.T("form.folders")]),
+			ExcludeFolders: splitList(data[i18n.T("form.exclude_folders")]),
+			NoSSL:          !data.Bool(i18n.T("form.ssl")),
+			DryRun:         data.Bool(i18n.T("form.dry_run")),
+		},
+	}
+	// The form has checked the filter
+	job.Filter, _ = ParseFilter(data[i18n.T("form.filter")])
 
-	// Simülasyon: Gerçek transfer fonksiyonunu burada çağırabilirsin
-	si.tui.ShowProgress(0, 100, "IMAPSYNC")
-	for i := 1; i <= 100; i += 10 {
-		time.Sleep(100 * time.Millisecond)
-		si.tui.ShowProgress(i, 100, "IMAPSYNC")
+	si.tui.PrintInfo(i18n.T("transfer.running"))
+	si.addLog("info", fmt.Sprintf("Starting mail transfer %s -> %s", job.SourceEmail, job.DestEmail))
+
+	// The inline bar needs a newline when the run stops before it is full
+	barOpen := false
+	duration, err := runTransfer(context.Background(), si.perfManager, job, transferHooks{
+		info:    si.tui.PrintInfo,
+		warning: si.tui.PrintWarning,
+		progress: func(p transferProgress) {
+			description := "IMAPSYNC"
+			if p.Folder != "" {
+				description += " " + p.Folder
+			}
+			if p.MessagesTotal > 0 {
+				si.tui.ShowProgress(p.MessagesDone, p.MessagesTotal, description)
+				barOpen = p.MessagesDone < p.MessagesTotal
+			} else {
+				si.tui.ShowProgress(int(p.Percent), 100, description)
+				barOpen = p.Percent < 100
+			}
+		},
+	})
+	if barOpen && si.tui.InlineProgress() {
+		fmt.Println()
 	}
 
-	si.tui.PrintSuccess("Mail transfer completed successfully!")
-	si.addLog("success", "Mail transfer completed successfully!")
+	switch {
+	case err != nil:
+		si.tui.PrintError(i18n.T("transfer.failed"))
+		si.tui.PrintError(err.Error())
+		si.addLog("error", "Mail transfer failed: "+err.Error())
+	case job.Options.DryRun:
+		si.tui.PrintSuccess(i18n.T("transfer.dry_run_done"))
+		si.tui.PrintInfo(i18n.T("transfer.duration", duration.Round(time.Second)))
+		si.addLog("success", "Mail transfer dry run completed")
+	default:
+		si.tui.PrintSuccess(i18n.T("transfer.completed"))
+		si.tui.PrintInfo(i18n.T("transfer.duration", duration.Round(time.Second)))
+		si.addLog("success", "Mail transfer completed successfully!")
+	}
 	si.tui.WaitForKey()
 }
 
 // addTransferJob adds a new transfer job
-func (si *SimpleInterface) addTransferJob(data map[string]string) {
+func (si *SimpleInterface) addTransferJob(data ui.FormValues) {
 	job := &TransferJob{
-		SourceHost:  data["Source IMAP Host"],
-		SourceEmail: data["Source Email"],
-		SourcePass:  data["Source Password"],
-		DestHost:    data["Destination IMAP Host"],
-		DestEmail:   data["Destination Email"],
-		DestPass:    data["Destination Password"],
+		SourceHost:  data[i18n.T("form.source_host")],
+		SourceEmail: data[i18n.T("form.source_email")],
+		SourcePass:  data[i18n.T("form.source_password")],
+		DestHost:    data[i18n.T("form.dest_host")],
+		DestEmail:   data[i18n.T("form.dest_email")],
+		DestPass:    data[i18n.T("form.dest_password")],
+
+		DeltaSchedule: data[i18n.T("form.delta_cron")],
+		Tags:          splitList(data[i18n.T("form.tags")]),
+		BatchID:       data[i18n.T("form.batch_id_optional")],
+		DependsOn:     splitList(data[i18n.T("form.depends_on")]),
+		Priority:      data.Int(i18n.T("form.priority")),
+		Options: TransferOptions{
+			DryRun:      data.Bool(i18n.T("form.dry_run")),
+			JustFolders: data.Bool(i18n.T("form.just_folders")),
+		},
 	}
+	// The form has checked the date and the filter
+	job.CutoverAt, _ = ParseCutoverDate(data[i18n.T("form.cutover_date")])
+	job.Filter, _ = ParseFilter(data[i18n.T("form.filter")])
 
 	if err := si.parallelMgr.AddJob(job); err != nil {
-		si.tui.PrintError("Failed to add job: " + err.Error())
+		si.tui.PrintError(i18n.T("jobs.add_failed", err))
 		si.addLog("error", "Failed to add transfer job: "+err.Error())
 	} else {
-		si.tui.PrintSuccess("Job added successfully!")
+		si.tui.PrintSuccess(i18n.T("jobs.add_done"))
 		si.addLog("success", "Transfer job added successfully")
 	}
 	si.tui.WaitForKey()
 }
 
-// startAllJobs starts all pending jobs
+// startAllJobs starts all pending jobs in the background and opens the dashboard
 func (si *SimpleInterface) startAllJobs() {
-	content := "Starting all pending transfer jobs...\n\n"
-	content += "This will begin transferring all queued jobs in parallel.\n"
-	content += "You can monitor progress in the 'View Job Status' menu."
+	content := i18n.T("jobs.start_all_heading") + "\n\n"
+	content += i18n.T("jobs.start_all_intro", i18n.T("menu.parallel.dashboard"))
 
-	choice := si.tui.ShowModal("Start All Jobs", content, []string{"Start", "Cancel"})
+	choice := si.tui.ShowModal(i18n.T("menu.parallel.start"), content, []string{i18n.T("button.start"), i18n.T("button.cancel")})
 	if choice == 0 {
-		si.addLog("info", "Starting all parallel transfer jobs")
+		if !si.parallelMgr.Admitting() {
+			si.tui.PrintWarning(i18n.T("jobs.admission_closed"))
+		}
+		si.startJobsInBackground()
+		si.showDashboard()
+	}
+}
+
+// startJobsInBackground starts the pending jobs without blocking the interface
+// and returns a status message. Outside a maintenance window it leaves the jobs
+// to the scheduler.
+func (si *SimpleInterface) startJobsInBackground() string {
+	if !si.parallelMgr.Admitting() {
+		return i18n.T("jobs.admission_closed")
+	}
+	if si.parallelMgr.IsBatchRunning() {
+		return i18n.T("dashboard.already_running")
+	}
+
+	si.addLog("info", "Starting all parallel transfer jobs")
+	go func() {
 		si.parallelMgr.StartAllJobs()
-		si.tui.PrintSuccess("All jobs completed!")
 		si.addLog("success", "All parallel transfer jobs completed")
-		si.tui.WaitForKey()
+	}()
+	return i18n.T("dashboard.starting")
+}
+
+// showDashboard shows the live job dashboard
+func (si *SimpleInterface) showDashboard() {
+	actions := []ui.DashboardAction{
+		{Key: '\r', Label: i18n.T("dashboard.action.details"), Run: si.dashboardDetails},
+		{Key: 'c', Label: i18n.T("dashboard.action.cancel"), Run: si.dashboardCancel},
+		{Key: 'p', Label: i18n.T("dashboard.action.pause"), Run: si.dashboardPause},
+		{Key: 'r', Label: i18n.T("dashboard.action.resume"), Run: si.dashboardResume},
+		{Key: 'l', Label: i18n.T("dashboard.action.log"), Run: si.dashboardLog},
+		{Key: 'd', Label: i18n.T("dashboard.action.dry_run"), Run: si.dashboardPreview},
+		{Key: 's', Label: i18n.T("dashboard.action.start"), Run: func(ui.DashboardRow) string {
+			return si.startJobsInBackground()
+		}},
+	}
+	si.tui.ShowDashboard(i18n.T("menu.parallel.dashboard"), si.dashboardRows, actions)
+}
+
+// dashboardRows converts the job progress to dashboard rows
+func (si *SimpleInterface) dashboardRows() []ui.DashboardRow {
+	snapshot := si.parallelMgr.ProgressSnapshot()
+	rows := make([]ui.DashboardRow, len(snapshot))
+	for i, p := range snapshot {
+		row := ui.DashboardRow{
+			ID:     p.ID,
+			Status: string(p.Status),
+			Speed:  p.Speed,
+			ETA:    p.ETA,
+			Folder: p.Folder,
+			Detail: fmt.Sprintf("%s → %s · %.2f MB", p.SourceEmail, p.DestEmail, float64(p.BytesTransferred)/(1024*1024)),
+		}
+		// Count messages when imapsync reports them, percent otherwise
+		if p.MessagesTotal > 0 {
+			row.Current, row.Total = p.MessagesDone, p.MessagesTotal
+		} else {
+			row.Current, row.Total = int(p.Progress), 100
+		}
+		if p.BatchID != "" {
+			row.Detail += " · " + i18n.T("dashboard.detail_batch", p.BatchID)
+		}
+		rows[i] = row
+	}
+	return rows
+}
+
+// dashboardDetails shows everything known about the selected job
+func (si *SimpleInterface) dashboardDetails(row ui.DashboardRow) string {
+	if row.ID == "" {
+		return i18n.T("dashboard.no_selection")
+	}
+	si.showJobStatus(row.ID)
+	return ""
+}
+
+// dashboardCancel cancels the selected job after confirmation
+func (si *SimpleInterface) dashboardCancel(row ui.DashboardRow) string {
+	if row.ID == "" {
+		return i18n.T("dashboard.no_selection")
+	}
+	if si.tui.ShowModal(i18n.T("menu.parallel.cancel"), i18n.T("dashboard.cancel_confirm", row.ID), []string{i18n.T("button.yes"), i18n.T("button.no")}) != 0 {
+		return ""
+	}
+	if err := si.parallelMgr.CancelJob(row.ID); err != nil {
+		return i18n.T("jobs.cancel_failed", err)
+	}
+	si.addLog("info", "Cancelled job "+row.ID)
+	return i18n.T("dashboard.cancelled", row.ID)
+}
+
+// dashboardPause pauses the selected job
+func (si *SimpleInterface) dashboardPause(row ui.DashboardRow) string {
+	if row.ID == "" {
+		return i18n.T("dashboard.no_selection")
+	}
+	if err := si.parallelMgr.PauseJob(row.ID); err != nil {
+		return i18n.T("dashboard.pause_failed", err)
+	}
+	return i18n.T("dashboard.pausing", row.ID)
+}
+
+// dashboardResume resumes the selected job, starting a batch if none is running
+func (si *SimpleInterface) dashboardResume(row ui.DashboardRow) string {
+	if row.ID == "" {
+		return i18n.T("dashboard.no_selection")
 	}
+	if err := si.parallelMgr.ResumeJob(row.ID); err != nil {
+		return i18n.T("dashboard.resume_failed", err)
+	}
+	si.startJobsInBackground()
+	return i18n.T("dashboard.resumed", row.ID)
 }
 
-// showJobStatus displays job status
-func (si *SimpleInterface) showJobStatus() {
-	jobs := si.parallelMgr.GetAllJobs()
+// dashboardLog shows the tail of the selected job's log
+func (si *SimpleInterface) dashboardLog(row ui.DashboardRow) string {
+	var logFile string
+	for _, p := range si.parallelMgr.ProgressSnapshot() {
+		if p.ID == row.ID {
+			logFile = p.LogFile
+		}
+	}
+	if logFile == "" {
+		return i18n.T("dashboard.no_log")
+	}
+
+	lines, err := TailFile(logFile, 100)
+	if err != nil {
+		return i18n.T("logs.read_failed", err)
+	}
+	si.tui.ShowModal(i18n.T("dashboard.log_title", row.ID), strings.Join(lines, "\n"), []string{i18n.T("button.ok")})
+	return ""
+}
+
+// dashboardPreview dry-runs the selected job and shows its plan
+func (si *SimpleInterface) dashboardPreview(row ui.DashboardRow) string {
+	if row.ID == "" {
+		return i18n.T("dashboard.no_selection")
+	}
+	si.tui.ShowLoading(i18n.T("plan.running"))
+	plan, err := si.parallelMgr.PreviewJob(context.Background(), row.ID, false)
+	var plans []*TransferPlan
+	if plan != nil {
+		plans = append(plans, plan)
+	}
+	si.showPlans(row.ID, plans, err)
+	return ""
+}
+
+// showPlans shows the plans of a dry run and offers to save them as JSON
+func (si *SimpleInterface) showPlans(name string, plans []*TransferPlan, err error) {
+	var sb strings.Builder
+	if err != nil {
+		sb.WriteString(i18n.T("plan.failed", err) + "\n\n")
+	}
+	for _, plan := range plans {
+		sb.WriteString(formatPlan(plan) + "\n")
+	}
+	if len(plans) == 0 {
+		si.tui.ShowModal(i18n.T("plan.title"), sb.String(), []string{i18n.T("button.ok")})
+		return
+	}
+
+	if si.tui.ShowModal(i18n.T("plan.title"), sb.String(), []string{i18n.T("plan.save"), i18n.T("button.ok")}) != 0 {
+		return
+	}
+	path, err := SavePlans(name, plans)
+	if err != nil {
+		si.tui.PrintError(i18n.T("plan.save_failed", err))
+	} else {
+		si.tui.PrintSuccess(i18n.T("plan.saved", path))
+		si.addLog("info", "Saved plan to "+path)
+	}
+	si.tui.WaitForKey()
+}
 
-	if len(jobs) == 0 {
-		content := "No transfer jobs found.\n\nAdd some jobs using 'Add Transfer Job'."
-		si.tui.ShowModal("Job Status", content, []string{"OK"})
+// showJobStatus displays the status of one job
+func (si *SimpleInterface) showJobStatus(jobID string) {
+	job, exists := si.parallelMgr.GetJobStatus(jobID)
+	if !exists {
+		si.tui.ShowModal(i18n.T("job.title"), i18n.T("job.not_found", jobID), []string{i18n.T("button.ok")})
 		return
 	}
 
-	content := "Current Job Status:\n\n"
-	for id, job := range jobs {
-		content += fmt.Sprintf("ID: %s\n", id)
-		content += fmt.Sprintf("From: %s\n", job.SourceEmail)
-		content += fmt.Sprintf("To: %s\n", job.DestEmail)
-		content += fmt.Sprintf("Status: %s\n", string(job.Status))
-		content += fmt.Sprintf("Progress: %.1f%%\n", job.Progress)
-		content += "---\n"
+	content := i18n.T("job.id", jobID) + "\n"
+	content += i18n.T("job.from", job.SourceEmail) + "\n"
+	content += i18n.T("job.to", job.Destination()) + "\n"
+	content += i18n.T("job.status", ui.StatusLabel(string(job.Status))) + "\n"
+	content += i18n.T("job.progress", job.Progress) + "\n"
+	if job.Priority != 0 {
+		content += i18n.T("job.priority", job.Priority) + "\n"
+	}
+	if job.BatchID != "" {
+		content += i18n.T("job.batch", job.BatchID) + "\n"
+	}
+	if len(job.DependsOn) > 0 {
+		content += i18n.T("job.depends_on", strings.Join(job.DependsOn, ", ")) + "\n"
+	}
+	if !job.Filter.IsZero() {
+		content += i18n.T("job.filter", job.Filter) + "\n"
+	}
+	if job.FailureReason != FailureNone {
+		content += i18n.T("job.reason", job.FailureReason) + "\n"
+	}
+	if attempts := si.parallelMgr.JobAttempts(jobID); len(attempts) > 0 {
+		content += "\n" + i18n.T("job.attempts", len(attempts)) + "\n"
+		for _, a := range attempts {
+			content += formatAttempt(a) + "\n"
+		}
+	}
+	if job.Plan != nil {
+		content += "\n" + i18n.T("plan.title") + ":\n" + formatPlan(job.Plan)
 	}
 
-	si.tui.ShowModal("Job Status", content, []string{"OK"})
+	si.tui.ShowModal(i18n.T("job.title"), content, []string{i18n.T("button.ok")})
 }
 
 // showJobSummary displays job summary
 func (si *SimpleInterface) showJobSummary() {
 	summary := si.parallelMgr.GetJobSummary()
 
-	content := "Transfer Job Summary:\n\n"
-	content += fmt.Sprintf("Pending: %d\n", summary[StatusPending])
-	content += fmt.Sprintf("Running: %d\n", summary[StatusRunning])
-	content += fmt.Sprintf("Completed: %d\n", summary[StatusCompleted])
-	content += fmt.Sprintf("Failed: %d\n", summary[StatusFailed])
-	content += fmt.Sprintf("Cancelled: %d\n", summary[StatusCancelled])
+	content := i18n.T("summary.heading") + ":\n\n"
+	content += summaryLines(summary)
 
-	si.tui.ShowModal("Job Summary", content, []string{"OK"})
+	if batchIDs := si.parallelMgr.BatchIDs(); len(batchIDs) > 0 {
+		content += "\n" + i18n.T("batches.title") + ":\n"
+		for _, batchID := range batchIDs {
+			content += fmt.Sprintf("%s: %s\n", batchID, formatSummary(si.parallelMgr.GetBatchSummary(batchID)))
+		}
+	}
+
+	si.tui.ShowModal(i18n.T("summary.title"), content, []string{i18n.T("button.ok")})
 }
 
 // showPerformanceStats displays performance statistics
@@ -325,63 +897,189 @@ func (si *SimpleInterface) showPerformanceStats() {
 	stats := si.perfManager.GetStats()
 	memoryUsage := si.perfManager.MemoryUsage()
 
-	content := "Performance Statistics:\n\n"
-	content += fmt.Sprintf("Total Transfers: %d\n", stats.TotalTransfers)
-	content += fmt.Sprintf("Successful: %d\n", stats.SuccessfulTransfers)
-	content += fmt.Sprintf("Failed: %d\n", stats.FailedTransfers)
+	content := i18n.T("stats.title") + ":\n\n"
+	content += i18n.T("stats.total_transfers", stats.TotalTransfers) + "\n"
+	content += i18n.T("stats.successful", stats.SuccessfulTransfers) + "\n"
+	content += i18n.T("stats.failed", stats.FailedTransfers) + "\n"
 
 	if stats.TotalTransfers > 0 {
 		successRate := float64(stats.SuccessfulTransfers) / float64(stats.TotalTransfers) * 100
-		content += fmt.Sprintf("Success Rate: %.2f%%\n", successRate)
+		content += i18n.T("stats.success_rate", successRate) + "\n"
+	}
+
+	content += i18n.T("stats.total_data", float64(stats.TotalBytes)/(1024*1024)) + "\n"
+	content += i18n.T("stats.average_speed", stats.AverageSpeed/1024) + "\n"
+	content += i18n.T("stats.memory", memoryUsage) + "\n"
+	content += i18n.T("stats.uptime", time.Since(stats.StartTime).Round(time.Second)) + "\n"
+	content += i18n.T("stats.concurrency_limit", stats.ConcurrencyLimit) + "\n"
+
+	cache := si.perfManager.CacheStats()
+	content += i18n.T("stats.cache_items", cache.Entries, cache.MaxEntries) + "\n"
+	content += i18n.T("stats.cache_hit_rate", cache.HitRate(), cache.Hits, cache.Misses, cache.Evictions) + "\n"
+
+	if len(stats.ConcurrencyChanges) > 0 {
+		content += "\n" + i18n.T("stats.concurrency_changes") + "\n"
+		start := len(stats.ConcurrencyChanges) - 5
+		if start < 0 {
+			start = 0
+		}
+		for _, d := range stats.ConcurrencyChanges[start:] {
+			content += d.String() + "\n"
+		}
+	}
+
+	si.tui.ShowModal(i18n.T("stats.title"), content, []string{i18n.T("button.ok")})
+}
+
+// showSettings displays the settings menu
+func (si *SimpleInterface) showSettings() {
+	items := []string{
+		"🌐 " + i18n.T("settings.language") + ": " + i18n.Name(i18n.Language()),
 	}
 
-	content += fmt.Sprintf("Total Data: %.2f MB\n", float64(stats.TotalBytes)/(1024*1024))
-	content += fmt.Sprintf("Average Speed: %.2f KB/s\n", stats.AverageSpeed/1024)
-	content += fmt.Sprintf("Memory Usage: %.2f MB\n", memoryUsage)
-	content += fmt.Sprintf("Uptime: %s\n", time.Since(stats.StartTime).Round(time.Second))
+	switch si.tui.ShowMenu(i18n.T("menu.main.settings"), items) {
+	case 0:
+		si.showLanguageMenu()
+	}
+}
 
-	si.tui.ShowModal("Performance Statistics", content, []string{"OK"})
+// showLanguageMenu lets the user switch the interface language for this session
+func (si *SimpleInterface) showLanguageMenu() {
+	langs := i18n.Languages()
+	items := make([]string, len(langs))
+	for i, lang := range langs {
+		items[i] = fmt.Sprintf("%s (%s)", i18n.Name(lang), lang)
+		if lang == i18n.Language() {
+			items[i] += " ✓"
+		}
+	}
+
+	choice := si.tui.ShowMenu(i18n.T("settings.language"), items)
+	if choice < 0 {
+		return
+	}
+	if err := i18n.SetLanguage(langs[choice]); err != nil {
+		si.tui.PrintError(err.Error())
+		si.tui.WaitForKey()
+		return
+	}
+	si.addLog("info", "Language switched to "+langs[choice])
+	si.tui.PrintSuccess(i18n.T("settings.language_set", i18n.Name(langs[choice])))
+	si.tui.PrintInfo(i18n.T("settings.language_hint", langs[choice]))
+	si.tui.WaitForKey()
 }
 
 // showDeveloperInfo displays developer information
 func (si *SimpleInterface) showDeveloperInfo() {
-	content := "Developer Information:\n\n"
-	content += "👨‍💻 Developer: Erencan Uçar\n"
+	content := i18n.T("developer.heading") + ":\n\n"
+	content += "👨‍💻 " + i18n.T("developer.name", "Erencan Uçar") + "\n"
 	content += "🌐 GitHub: https://github.com/erencanucarr\n"
 	content += "💼 LinkedIn: https://www.linkedin.com/in/erencanucarr/\n\n"
-	content += "📧 Zero Dependency IMAPSYNC CLI\n"
-	content += "🚀 Built with Go (Zero External Dependencies)\n"
-	content += "🎨 Modern TUI Interface"
+	content += "📧 " + i18n.T("developer.tagline") + "\n"
+	content += "🚀 " + i18n.T("developer.built_with") + "\n"
+	content += "🎨 " + i18n.T("developer.tui")
 
-	si.tui.ShowModal("Developer Info", content, []string{"OK"})
+	si.tui.ShowModal(i18n.T("menu.main.developer"), content, []string{i18n.T("button.ok")})
 }
 
-// showLogs displays the application logs
+// showLogs displays the persisted application and job logs
 func (si *SimpleInterface) showLogs() {
-	if len(si.logs) == 0 {
-		si.tui.ShowModal("History/Logs", "No log records yet.", []string{"OK"})
+	items := []string{
+		"📜 " + i18n.T("logs.app"),
+		"📂 " + i18n.T("logs.jobs"),
+	}
+
+	switch si.tui.ShowMenu(i18n.T("menu.main.logs"), items) {
+	case 0:
+		si.showAppLog()
+	case 1:
+		si.showJobLogs()
+	}
+}
+
+// showAppLog displays the most recent entries of the application log
+func (si *SimpleInterface) showAppLog() {
+	path := AppLogPath()
+	if path == "" {
+		si.tui.ShowModal(i18n.T("menu.main.logs"), i18n.T("logs.disabled"), []string{i18n.T("button.ok")})
+		return
+	}
+
+	records, err := ReadLogRecords(path, 200)
+	if err != nil || len(records) == 0 {
+		si.tui.ShowModal(i18n.T("menu.main.logs"), i18n.T("logs.empty"), []string{i18n.T("button.ok")})
 		return
 	}
 
 	var sb strings.Builder
-	sb.WriteString("Application Logs:\n\n")
+	sb.WriteString(i18n.T("logs.app_heading") + ":\n\n")
+
+	for _, rec := range records {
+		logType := rec.Level.String()
+		if t, ok := rec.Fields["type"].(string); ok {
+			logType = strings.ToUpper(t)
+		}
+
+		line := fmt.Sprintf("[%s] %s: %s",
+			rec.Time.Format("2006-01-02 15:04:05"),
+			logType,
+			rec.Message)
+		if jobID, ok := rec.Fields[FieldJobID].(string); ok {
+			line += " (" + jobID + ")"
+		}
+		sb.WriteString(line + "\n")
+	}
+
+	si.tui.ShowModal(i18n.T("menu.main.logs"), sb.String(), []string{i18n.T("button.ok")})
+}
+
+// showJobLogs lets the user pick a job log and shows its tail
+func (si *SimpleInterface) showJobLogs() {
+	paths, err := ListJobLogs()
+	if err != nil || len(paths) == 0 {
+		si.tui.ShowModal(i18n.T("logs.jobs"), i18n.T("logs.jobs_empty"), []string{i18n.T("button.ok")})
+		return
+	}
 
-	for _, log := range si.logs {
-		line := fmt.Sprintf("[%s] %s: %s\n",
-			log.Time.Format("2006-01-02 15:04:05"),
-			strings.ToUpper(log.Type),
-			log.Message)
-		sb.WriteString(line)
+	if len(paths) > 20 {
+		paths = paths[:20]
+	}
+	items := make([]string, len(paths))
+	for i, path := range paths {
+		items[i] = strings.TrimSuffix(filepath.Base(path), ".log")
 	}
 
-	si.tui.ShowModal("History/Logs", sb.String(), []string{"OK"})
+	choice := si.tui.ShowMenu(i18n.T("logs.jobs"), items)
+	if choice < 0 {
+		return
+	}
+
+	lines, err := TailFile(paths[choice], 100)
+	if err != nil {
+		si.tui.ShowModal(i18n.T("logs.jobs"), i18n.T("logs.read_failed", err), []string{i18n.T("button.ok")})
+		return
+	}
+
+	si.tui.ShowModal(items[choice], strings.Join(lines, "\n"), []string{i18n.T("button.ok")})
 }
 
 // StartSimpleInterface starts the simple interface
 func StartSimpleInterface() {
 	si := NewSimpleInterface()
-	si.tui.PrintInfo("Welcome to IMAPSYNC! 🚀")
+	// Log lines would scribble over the full-screen interface; they still
+	// reach History/Logs through the persisted log
+	if err := si.tui.Start(); err == nil {
+		MuteConsoleLogs(true)
+	}
+	si.tui.PrintInfo(i18n.T("app.welcome"))
 	si.addLog("info", "IMAPSYNC application started")
 	si.tui.WaitForKey()
 	si.Run()
+	si.tui.Close()
+	MuteConsoleLogs(false)
+	if si.scheduler != nil {
+		si.scheduler.Stop()
+	}
+	si.parallelMgr.Close()
+	si.perfManager.Close()
 }
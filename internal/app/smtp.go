@@ -0,0 +1,323 @@
+package app
+
+import (
+	"bytes"
+	"crypto/tls"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+)
+
+//go:embed templates/mail
+var mailTemplates embed.FS
+
+const (
+	templateMailboxMoved = "mailbox_moved.tmpl"
+	templateBatchSummary = "batch_summary.tmpl"
+)
+
+// SMTPConfig describes the mail server used for migration summaries
+type SMTPConfig struct {
+	Host               string   `json:"host"`
+	Port               int      `json:"port"`
+	Username           string   `json:"username"` // Empty disables AUTH
+	Password           string   `json:"password"`
+	From               string   `json:"from"`
+	AdminAddresses     []string `json:"admin_addresses"` // Receive the batch summary
+	NotifyUsers        bool     `json:"notify_users"`    // Tell end users their mailbox has moved
+	Locale             string   `json:"locale"`          // Template locale, e.g. "en" or "tr"
+	RequireTLS         bool     `json:"require_tls"`     // Fail when the server does not offer STARTTLS
+	InsecureSkipVerify bool     `json:"insecure_skip_verify"`
+	Timeout            Duration `json:"timeout"`
+}
+
+// DefaultSMTPConfig returns default SMTP settings
+func DefaultSMTPConfig() *SMTPConfig {
+	return &SMTPConfig{
+		Port:        587,
+		NotifyUsers: true,
+		Locale:      "en",
+		RequireTLS:  true,
+		Timeout:     Duration{30 * time.Second},
+	}
+}
+
+// UnmarshalJSON fills unspecified fields with their defaults
+func (c *SMTPConfig) UnmarshalJSON(data []byte) error {
+	type plain SMTPConfig
+	cfg := plain(*DefaultSMTPConfig())
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return err
+	}
+	*c = SMTPConfig(cfg)
+	return nil
+}
+
+// mailMessage is a rendered message waiting to be sent
+type mailMessage struct {
+	To      []string
+	Subject string
+	Body    string
+}
+
+// userMailData is passed to the mailbox moved template
+type userMailData struct {
+	Job *JobInfo
+}
+
+// batchMailData is passed to the batch summary template
+type batchMailData struct {
+	Time       time.Time
+	Batch      *BatchInfo
+	Completed  int
+	Failed     int
+	Cancelled  int
+	FailedJobs []JobInfo
+}
+
+// SMTPNotifier emails end users when their mailbox is done and admins when a batch finishes
+type SMTPNotifier struct {
+	config *SMTPConfig
+	logger *Logger
+	queue  chan mailMessage
+	wg     sync.WaitGroup
+	once   sync.Once
+}
+
+// NewSMTPNotifier creates an SMTP notifier and starts its delivery goroutine
+func NewSMTPNotifier(config *SMTPConfig) *SMTPNotifier {
+	defaults := DefaultSMTPConfig()
+	if config == nil {
+		config = defaults
+	}
+	if config.Port == 0 {
+		config.Port = defaults.Port
+	}
+	if config.Locale == "" {
+		config.Locale = defaults.Locale
+	}
+	if config.Timeout.Duration <= 0 {
+		config.Timeout = defaults.Timeout
+	}
+
+	logger := NewLogger()
+
+	sn := &SMTPNotifier{
+		config: config,
+		logger: logger,
+		queue:  make(chan mailMessage, 256),
+	}
+
+	sn.wg.Add(1)
+	go sn.run()
+
+	return sn
+}
+
+// Notify renders and queues the messages for an event
+func (sn *SMTPNotifier) Notify(event JobEvent) {
+	var msg mailMessage
+	var err error
+
+	switch {
+	case event.Type == EventJobCompleted && sn.config.NotifyUsers && event.Job != nil && event.Job.DestEmail != "" && !event.Job.DryRun:
+		msg, err = renderMail(sn.config.Locale, templateMailboxMoved, userMailData{Job: event.Job})
+		msg.To = []string{event.Job.DestEmail}
+	case event.Type == EventBatchFinished && len(sn.config.AdminAddresses) > 0 && event.Batch != nil:
+		msg, err = renderMail(sn.config.Locale, templateBatchSummary, newBatchMailData(event))
+		msg.To = sn.config.AdminAddresses
+	default:
+		return
+	}
+
+	if err != nil {
+		sn.logger.Error("SMTP: failed to render %s message: %v", event.Type, err)
+		return
+	}
+
+	select {
+	case sn.queue <- msg:
+	default:
+		sn.logger.Error("SMTP: delivery queue full, dropping message to %s", strings.Join(msg.To, ", "))
+	}
+}
+
+// Close stops accepting messages and waits for queued messages to be sent
+func (sn *SMTPNotifier) Close() error {
+	sn.once.Do(func() {
+		close(sn.queue)
+	})
+	sn.wg.Wait()
+	return nil
+}
+
+// run sends queued messages in order
+func (sn *SMTPNotifier) run() {
+	defer sn.wg.Done()
+
+	for msg := range sn.queue {
+		if err := sn.Send(msg.To, msg.Subject, msg.Body); err != nil {
+			sn.logger.Error("SMTP: failed to send %q to %s: %v", msg.Subject, strings.Join(msg.To, ", "), err)
+		}
+	}
+}
+
+// Send delivers a plain text message using STARTTLS and AUTH when available
+func (sn *SMTPNotifier) Send(to []string, subject, body string) error {
+	addr := net.JoinHostPort(sn.config.Host, strconv.Itoa(sn.config.Port))
+
+	conn, err := net.DialTimeout("tcp", addr, sn.config.Timeout.Duration)
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", addr, err)
+	}
+	conn.SetDeadline(time.Now().Add(sn.config.Timeout.Duration))
+
+	client, err := smtp.NewClient(conn, sn.config.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to start SMTP session: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		tlsConfig := &tls.Config{
+			ServerName:         sn.config.Host,
+			InsecureSkipVerify: sn.config.InsecureSkipVerify,
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("STARTTLS failed: %w", err)
+		}
+	} else if sn.config.RequireTLS {
+		return fmt.Errorf("server %s does not support STARTTLS", addr)
+	}
+
+	if sn.config.Username != "" {
+		auth := smtp.PlainAuth("", sn.config.Username, sn.config.Password, sn.config.Host)
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("authentication failed: %w", err)
+		}
+	}
+
+	// The envelope takes the bare address of "Name <address>"
+	sender := sn.config.From
+	if addr, err := mail.ParseAddress(sender); err == nil {
+		sender = addr.Address
+	}
+	if err := client.Mail(sender); err != nil {
+		return fmt.Errorf("MAIL FROM rejected: %w", err)
+	}
+	for _, rcpt := range to {
+		if err := client.Rcpt(rcpt); err != nil {
+			return fmt.Errorf("RCPT TO %s rejected: %w", rcpt, err)
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("DATA rejected: %w", err)
+	}
+	if _, err := w.Write(buildMessage(sn.config.From, to, subject, body)); err != nil {
+		w.Close()
+		return fmt.Errorf("failed to write message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("message rejected: %w", err)
+	}
+
+	return client.Quit()
+}
+
+// newBatchMailData counts the outcome of a finished batch
+func newBatchMailData(event JobEvent) batchMailData {
+	data := batchMailData{
+		Time:      event.Time,
+		Batch:     event.Batch,
+		Completed: event.Batch.Summary[StatusCompleted],
+		Failed:    event.Batch.Summary[StatusFailed],
+		Cancelled: event.Batch.Summary[StatusCancelled],
+	}
+	for _, job := range event.Batch.Jobs {
+		if job.Status == StatusFailed {
+			data.FailedJobs = append(data.FailedJobs, job)
+		}
+	}
+	return data
+}
+
+// renderMail executes a mail template for the locale, falling back to English.
+// The first line of a template is "Subject: ..." and the rest is the body.
+func renderMail(locale, name string, data interface{}) (mailMessage, error) {
+	var msg mailMessage
+
+	tmpl, err := loadMailTemplate(locale, name)
+	if err != nil {
+		return msg, err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return msg, err
+	}
+
+	subject, body, _ := strings.Cut(buf.String(), "\n")
+	msg.Subject = strings.TrimSpace(strings.TrimPrefix(subject, "Subject:"))
+	msg.Body = body
+	return msg, nil
+}
+
+// loadMailTemplate parses the first template found along the locale fallback chain
+func loadMailTemplate(locale, name string) (*template.Template, error) {
+	candidates := []string{locale}
+	if base, _, ok := strings.Cut(locale, "-"); ok {
+		candidates = append(candidates, base)
+	}
+	candidates = append(candidates, "en")
+
+	for _, candidate := range candidates {
+		path := "templates/mail/" + strings.ToLower(candidate) + "/" + name
+		if tmpl, err := template.ParseFS(mailTemplates, path); err == nil {
+			return tmpl, nil
+		}
+	}
+	return nil, fmt.Errorf("mail template %s not found", name)
+}
+
+// headerValue removes line breaks so a value cannot start another header
+func headerValue(s string) string {
+	return strings.Join(strings.FieldsFunc(s, func(r rune) bool { return r == '\r' || r == '\n' }), " ")
+}
+
+// buildMessage assembles RFC 5322 headers and a UTF-8 body with CRLF line
+// endings. Subjects are rendered from job data, so header values are stripped
+// of line breaks.
+func buildMessage(from string, to []string, subject, body string) []byte {
+	var buf bytes.Buffer
+
+	recipients := make([]string, len(to))
+	for i, rcpt := range to {
+		recipients[i] = headerValue(rcpt)
+	}
+
+	buf.WriteString("From: " + headerValue(from) + "\r\n")
+	buf.WriteString("To: " + strings.Join(recipients, ", ") + "\r\n")
+	buf.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", headerValue(subject)) + "\r\n")
+	buf.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	buf.WriteString("\r\n")
+
+	body = strings.ReplaceAll(body, "\r\n", "\n")
+	buf.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+
+	return buf.Bytes()
+}
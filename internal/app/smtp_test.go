@@ -0,0 +1,375 @@
+package app
+
+import (
+	"bufio"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"math/big"
+	"mime"
+	"net"
+	"net/mail"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// sinkMessage is a message received by smtpSink
+type sinkMessage struct {
+	From     string
+	To       []string
+	Data     string
+	TLS      bool   // The message was sent after STARTTLS
+	AuthUser string // User name of AUTH PLAIN, empty without AUTH
+}
+
+// smtpSink is a local SMTP server that records the messages it receives
+type smtpSink struct {
+	listener  net.Listener
+	tlsConfig *tls.Config
+	offerTLS  bool
+
+	mu       sync.Mutex
+	messages []sinkMessage
+	commands []string
+	received chan struct{}
+}
+
+// newSMTPSink starts a sink on a random local port
+func newSMTPSink(t *testing.T, offerTLS bool) *smtpSink {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sink := &smtpSink{
+		listener:  l,
+		tlsConfig: &tls.Config{Certificates: []tls.Certificate{selfSignedCert(t)}},
+		offerTLS:  offerTLS,
+		received:  make(chan struct{}, 16),
+	}
+	t.Cleanup(func() { l.Close() })
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go sink.serve(conn)
+		}
+	}()
+	return sink
+}
+
+// port returns the port the sink listens on
+func (s *smtpSink) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+// serve speaks just enough SMTP for net/smtp: EHLO, STARTTLS, AUTH PLAIN,
+// MAIL, RCPT, DATA and QUIT
+func (s *smtpSink) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+	var msg sinkMessage
+	reply("220 sink ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		verb := strings.ToUpper(strings.Fields(line + " ")[0])
+		s.mu.Lock()
+		s.commands = append(s.commands, verb)
+		s.mu.Unlock()
+
+		switch verb {
+		case "EHLO":
+			reply("250-sink")
+			if s.offerTLS && !msg.TLS {
+				reply("250-STARTTLS")
+			}
+			reply("250 AUTH PLAIN")
+		case "STARTTLS":
+			reply("220 Ready to start TLS")
+			tlsConn := tls.Server(conn, s.tlsConfig)
+			if err := tlsConn.Handshake(); err != nil {
+				return
+			}
+			conn, r = tlsConn, bufio.NewReader(tlsConn)
+			msg.TLS = true
+		case "AUTH":
+			fields := strings.Fields(line)
+			decoded, _ := base64.StdEncoding.DecodeString(fields[len(fields)-1])
+			parts := strings.Split(string(decoded), "\x00")
+			if len(parts) == 3 && parts[2] == "secret" {
+				msg.AuthUser = parts[1]
+				reply("235 Authenticated")
+			} else {
+				reply("535 Authentication failed")
+			}
+		case "MAIL":
+			msg.From = strings.Trim(strings.TrimPrefix(line[len("MAIL"):], " FROM:"), "<>")
+			reply("250 OK")
+		case "RCPT":
+			msg.To = append(msg.To, strings.Trim(strings.TrimPrefix(line[len("RCPT"):], " TO:"), "<>"))
+			reply("250 OK")
+		case "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(l, "."))
+			}
+			msg.Data = data.String()
+			s.mu.Lock()
+			s.messages = append(s.messages, msg)
+			s.mu.Unlock()
+			s.received <- struct{}{}
+			reply("250 Queued")
+		case "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("502 Not implemented")
+		}
+	}
+}
+
+// commandList returns the verbs received so far
+func (s *smtpSink) commandList() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.commands...)
+}
+
+// wait returns the next received message
+func (s *smtpSink) wait(t *testing.T) sinkMessage {
+	t.Helper()
+	select {
+	case <-s.received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no message received")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.messages[len(s.messages)-1]
+}
+
+// selfSignedCert creates a certificate for 127.0.0.1
+func selfSignedCert(t *testing.T) tls.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "sink"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+// parseSinkMessage parses the headers and body of a received message
+func parseSinkMessage(t *testing.T, data string) *mail.Message {
+	t.Helper()
+	m, err := mail.ReadMessage(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("invalid message: %v\n%s", err, data)
+	}
+	return m
+}
+
+// decodedSubject returns the Subject header without its MIME encoding
+func decodedSubject(t *testing.T, m *mail.Message) string {
+	t.Helper()
+	subject, err := new(mime.WordDecoder).DecodeHeader(m.Header.Get("Subject"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return subject
+}
+
+// newSinkNotifier creates a notifier that delivers to sink
+func newSinkNotifier(sink *smtpSink, config SMTPConfig) *SMTPNotifier {
+	config.Host = "127.0.0.1"
+	config.Port = sink.port()
+	config.From = "Migrations <migrations@example.com>"
+	config.InsecureSkipVerify = true
+	return NewSMTPNotifier(&config)
+}
+
+func TestSMTPNotifierUserMail(t *testing.T) {
+	sink := newSMTPSink(t, true)
+	cfg := *DefaultSMTPConfig()
+	cfg.Username, cfg.Password = "migrations", "secret"
+	sn := newSinkNotifier(sink, cfg)
+	defer sn.Close()
+
+	sn.Notify(JobEvent{Type: EventJobCompleted, Time: time.Now(), Job: &JobInfo{
+		ID: "job1", SourceEmail: "jdoe@old.example.com", DestEmail: "jdoe@new.example.com", Status: StatusCompleted,
+	}})
+	got := sink.wait(t)
+
+	if !got.TLS {
+		t.Error("message was sent without STARTTLS")
+	}
+	if got.AuthUser != "migrations" {
+		t.Errorf("AUTH user = %q, want migrations", got.AuthUser)
+	}
+	if cmds := sink.commandList(); strings.Join(cmds[:4], " ") != "EHLO STARTTLS EHLO AUTH" {
+		t.Errorf("session started with %v, want AUTH only after STARTTLS", cmds)
+	}
+	if got.From != "migrations@example.com" {
+		t.Errorf("MAIL FROM = %q", got.From)
+	}
+	if len(got.To) != 1 || got.To[0] != "jdoe@new.example.com" {
+		t.Errorf("RCPT TO = %v, want the destination mailbox", got.To)
+	}
+
+	m := parseSinkMessage(t, got.Data)
+	if from := m.Header.Get("From"); from != "Migrations <migrations@example.com>" {
+		t.Errorf("From header = %q", from)
+	}
+	if to := m.Header.Get("To"); to != "jdoe@new.example.com" {
+		t.Errorf("To header = %q", to)
+	}
+	if subject := decodedSubject(t, m); subject != "Your mailbox jdoe@old.example.com has moved" {
+		t.Errorf("Subject = %q", subject)
+	}
+	if ct := m.Header.Get("Content-Type"); ct != "text/plain; charset=utf-8" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	if m.Header.Get("Date") == "" || m.Header.Get("MIME-Version") != "1.0" {
+		t.Error("Date or MIME-Version header missing")
+	}
+	if !strings.Contains(got.Data, "\r\n") || strings.Contains(strings.ReplaceAll(got.Data, "\r\n", ""), "\n") {
+		t.Error("message lines do not all end with CRLF")
+	}
+}
+
+func TestSMTPNotifierAdminSummaryLocale(t *testing.T) {
+	sink := newSMTPSink(t, true)
+	cfg := *DefaultSMTPConfig()
+	cfg.Locale = "tr"
+	cfg.AdminAddresses = []string{"it@example.com", "ops@example.com"}
+	sn := newSinkNotifier(sink, cfg)
+	defer sn.Close()
+
+	sn.Notify(JobEvent{Type: EventBatchFinished, Time: time.Now(), Batch: &BatchInfo{
+		Total:   2,
+		Summary: map[TransferStatus]int{StatusCompleted: 1, StatusFailed: 1},
+		Jobs: []JobInfo{
+			{ID: "job1", SourceEmail: "a@example.com", Status: StatusCompleted},
+			{ID: "job2", SourceEmail: "b@example.com", Status: StatusFailed, Error: "auth failed"},
+		},
+	}})
+	got := sink.wait(t)
+
+	if len(got.To) != 2 || got.To[0] != "it@example.com" || got.To[1] != "ops@example.com" {
+		t.Errorf("RCPT TO = %v, want both admins", got.To)
+	}
+	if got.AuthUser != "" {
+		t.Errorf("AUTH sent without a user name: %q", got.AuthUser)
+	}
+
+	m := parseSinkMessage(t, got.Data)
+	if to := m.Header.Get("To"); to != "it@example.com, ops@example.com" {
+		t.Errorf("To header = %q", to)
+	}
+	if subject := decodedSubject(t, m); subject != "Taşıma grubu tamamlandı: 1/2 posta kutusu tamamlandı" {
+		t.Errorf("Subject = %q, want the Turkish template", subject)
+	}
+	if !strings.Contains(got.Data, "b@example.com") {
+		t.Error("summary does not list the failed job")
+	}
+}
+
+func TestSMTPNotifierRequireTLS(t *testing.T) {
+	sink := newSMTPSink(t, false)
+	sn := newSinkNotifier(sink, *DefaultSMTPConfig())
+	defer sn.Close()
+
+	err := sn.Send([]string{"user@example.com"}, "Subject", "Body")
+	if err == nil || !strings.Contains(err.Error(), "STARTTLS") {
+		t.Fatalf("Send without STARTTLS returned %v, want a STARTTLS error", err)
+	}
+
+	cfg := *DefaultSMTPConfig()
+	cfg.RequireTLS = false
+	plain := newSinkNotifier(sink, cfg)
+	defer plain.Close()
+	if err := plain.Send([]string{"user@example.com"}, "Subject", "Body"); err != nil {
+		t.Fatal(err)
+	}
+	if got := sink.wait(t); got.TLS {
+		t.Error("sink reports TLS although it does not offer STARTTLS")
+	}
+}
+
+func TestBuildMessageStripsLineBreaks(t *testing.T) {
+	data := string(buildMessage("from@example.com", []string{"to@example.com\r\nBcc: evil@example.com"},
+		"Hello\r\nBcc: evil@example.com", "Body\n"))
+
+	m := parseSinkMessage(t, data)
+	if bcc := m.Header.Get("Bcc"); bcc != "" {
+		t.Fatalf("injected Bcc header %q", bcc)
+	}
+	if subject := decodedSubject(t, m); strings.ContainsAny(subject, "\r\n") {
+		t.Errorf("Subject still contains a line break: %q", subject)
+	}
+	if to := m.Header.Get("To"); strings.ContainsAny(to, "\r\n") {
+		t.Errorf("To still contains a line break: %q", to)
+	}
+}
+
+func TestSMTPNotifierSkipsDryRuns(t *testing.T) {
+	sink := newSMTPSink(t, true)
+	sn := newSinkNotifier(sink, *DefaultSMTPConfig())
+	defer sn.Close()
+
+	dry := &JobInfo{ID: "dry", SourceEmail: "dry@example.com", DestEmail: "dry@new.example.com", DryRun: true}
+	sn.Notify(JobEvent{Type: EventJobPlanned, Time: time.Now(), Job: dry})
+	sn.Notify(JobEvent{Type: EventJobCompleted, Time: time.Now(), Job: dry})
+	sn.Notify(JobEvent{Type: EventJobCompleted, Time: time.Now(), Job: &JobInfo{
+		ID: "real", SourceEmail: "real@example.com", DestEmail: "real@new.example.com",
+	}})
+
+	if got := sink.wait(t); len(got.To) != 1 || got.To[0] != "real@new.example.com" {
+		t.Fatalf("first message went to %v, want only the real migration to be announced", got.To)
+	}
+}
+
+func TestEventForDryRun(t *testing.T) {
+	if event, _ := eventForStatus(StatusCompleted, true); event != EventJobPlanned {
+		t.Errorf("completed dry run announced as %s, want %s", event, EventJobPlanned)
+	}
+	if event, _ := eventForStatus(StatusCompleted, false); event != EventJobCompleted {
+		t.Errorf("completed job announced as %s, want %s", event, EventJobCompleted)
+	}
+	if event, _ := eventForStatus(StatusFailed, true); event != EventJobFailed {
+		t.Errorf("failed dry run announced as %s, want %s", event, EventJobFailed)
+	}
+}
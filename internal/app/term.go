@@ -1,19 +1,20 @@
 package app
 
 import (
-	"bufio"
+	"errors"
 	"fmt"
-	"os"
-	"strings"
+
+	"imapsync/internal/i18n"
+	"imapsync/internal/ui"
 )
 
-// ReadPassword reads a password from stdin without echoing
+// ReadPassword reads a password from stdin without echoing. When echo cannot
+// be turned off it warns and returns an empty password instead of showing it.
 func ReadPassword() (string, error) {
-	fmt.Print("Password: ")
-	reader := bufio.NewReader(os.Stdin)
-	password, err := reader.ReadString('\n')
-	if err != nil {
-		return "", err
+	password, err := ui.ReadPassword()
+	if errors.Is(err, ui.ErrEchoUnsupported) {
+		fmt.Println()
+		fmt.Println(ui.Yellow(i18n.T("error.warning")), err)
 	}
-	return strings.TrimSpace(password), nil
+	return password, err
 }
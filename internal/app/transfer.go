@@ -2,175 +2,283 @@ package app
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"fmt"
-	"os"
 	"os/exec"
 	"regexp"
 	"strconv"
 	"strings"
 	"time"
 
+	"imapsync/internal/i18n"
 	"imapsync/internal/ui"
 )
 
+var (
+	// percentRe matches a progress percentage, e.g. "42.5%"
+	percentRe = regexp.MustCompile(`([0-9]{1,3}(?:\.[0-9]+)?)%`)
+
+	// ratioRe matches any other counter of done and total items
+	ratioRe = regexp.MustCompile(`(\d+)/(\d+)`)
+)
+
+// transferProgress is the state of a running transfer, parsed from the imapsync output
+type transferProgress struct {
+	Percent       float64
+	Folder        string
+	MessagesDone  int
+	MessagesTotal int
+	Bytes         int64
+}
+
+// transferHooks show the steps of runTransfer; nil hooks are skipped
+type transferHooks struct {
+	info     func(message string)
+	warning  func(message string)
+	progress func(p transferProgress)
+}
+
 // TransferMail runs imapsync and shows a progress bar.
-// It parses stdout looking for "Transferred:" lines to update progress.
+// It parses stdout looking for progress lines to update it.
 func TransferMail() {
-	fmt.Println(ui.Cyan("Starting mail transfer..."))
+	fmt.Println(ui.Cyan(i18n.T("transfer.starting")))
 
 	// Initialize performance manager
 	perfManager := NewPerformanceManager(nil)
+	perfManager.SetRetryPolicies(CurrentConfig().Retry)
+	if err := perfManager.ConfigureCache(&CurrentConfig().Cache); err != nil {
+		fmt.Println(ui.Yellow(i18n.T("error.cache_not_loaded")), err)
+	}
+	defer perfManager.Close()
 	defer perfManager.PrintStats()
 
-	reader := bufio.NewReader(os.Stdin)
-	fmt.Print("Source IMAP host: ")
+	reader := ui.Stdin()
+	fmt.Print(i18n.T("prompt.source_host") + " ")
 	srcHost, _ := reader.ReadString('\n')
 	srcHost = strings.TrimSpace(srcHost)
 
-	fmt.Print("Source email: ")
+	fmt.Print(i18n.T("prompt.source_email") + " ")
 	srcEmail, _ := reader.ReadString('\n')
 	srcEmail = strings.TrimSpace(srcEmail)
 
-	fmt.Print("Source password: ")
+	fmt.Print(i18n.T("prompt.source_password") + " ")
 	srcPass, _ := ReadPassword()
 	fmt.Println()
 
-	fmt.Print("Destination IMAP host: ")
+	fmt.Print(i18n.T("prompt.dest_host") + " ")
 	dstHost, _ := reader.ReadString('\n')
 	dstHost = strings.TrimSpace(dstHost)
 
-	fmt.Print("Destination email: ")
+	fmt.Print(i18n.T("prompt.dest_email") + " ")
 	dstEmail, _ := reader.ReadString('\n')
 	dstEmail = strings.TrimSpace(dstEmail)
 
-	fmt.Print("Destination password: ")
+	fmt.Print(i18n.T("prompt.dest_password") + " ")
 	dstPass, _ := ReadPassword()
 	fmt.Println()
 
-	// Check cache for previous successful transfers
-	cacheKey := fmt.Sprintf("%s_%s_%s", srcEmail, dstEmail, srcHost)
-	if cachedData, found := perfManager.GetCachedData(cacheKey); found {
-		fmt.Println(ui.Yellow("Found cached transfer data for this combination"))
-		fmt.Printf("Last successful transfer: %v\n", cachedData)
+	job := &TransferJob{
+		SourceHost:  srcHost,
+		SourceEmail: srcEmail,
+		SourcePass:  srcPass,
+		DestHost:    dstHost,
+		DestEmail:   dstEmail,
+		DestPass:    dstPass,
 	}
 
-	fmt.Println(ui.Cyan("Testing credentials..."))
-
-	// Use retry mechanism for credential testing
-	ctx := context.Background()
-	err := perfManager.RetryWithBackoff(ctx, func() error {
-		testCmd := exec.Command("imapsync", "--justlogin", "--host1", srcHost, "--ssl1", "--user1", srcEmail, "--password1", srcPass, "--host2", dstHost, "--ssl2", "--user2", dstEmail, "--password2", dstPass)
-		return testCmd.Run()
+	var bar *ProgressBar
+	duration, err := runTransfer(context.Background(), perfManager, job, transferHooks{
+		info: func(message string) {
+			fmt.Println(ui.Cyan(message))
+		},
+		warning: func(message string) {
+			fmt.Println(ui.Yellow(message))
+		},
+		progress: func(p transferProgress) {
+			if bar == nil {
+				bar = NewProgressBar(100)
+				bar.SetDescription("IMAPSYNC")
+			}
+			bar.Set(int(p.Percent))
+		},
 	})
 
+	if bar != nil {
+		if err == nil {
+			bar.Finish()
+		}
+		if !ui.CurrentTheme().Plain {
+			fmt.Println() // newline after bar
+		}
+	}
+
 	if err != nil {
-		fmt.Println(ui.Red("Error:"), err)
+		fmt.Println(ui.Red(i18n.T("transfer.failed")))
+		fmt.Println(ui.Red(i18n.T("error.prefix")), err)
 		return
 	}
 
+	fmt.Println(ui.Green(i18n.T("transfer.completed")))
+	fmt.Println(i18n.T("transfer.duration", duration.Round(time.Second)))
+}
+
+// runTransfer runs a single transfer: it tests both logins with retries, takes
+// a pooled connection, runs imapsync and records the outcome in the statistics
+// and the transfer cache. A dry run leaves the statistics and cache untouched.
+func runTransfer(ctx context.Context, pm *PerformanceManager, job *TransferJob, hooks transferHooks) (time.Duration, error) {
+	info := func(message string) {
+		if hooks.info != nil {
+			hooks.info(message)
+		}
+	}
+	warning := func(message string) {
+		if hooks.warning != nil {
+			hooks.warning(message)
+		}
+	}
+
+	// Check cache for previous successful transfers
+	cacheKey := fmt.Sprintf("%s_%s_%s", job.SourceEmail, job.DestEmail, job.SourceHost)
+	if record, found := pm.LastTransfer(cacheKey); found {
+		info(i18n.T("transfer.cached"))
+		info(i18n.T("transfer.last_success", record))
+	}
+
+	info(i18n.T("transfer.testing_credentials"))
+	if err := pm.RetryWithBackoff(ctx, func() error { return checkLogins(ctx, job) }); err != nil {
+		return 0, fmt.Errorf("credential check failed: %w", err)
+	}
+
 	// Acquire connection from pool
-	if err := perfManager.AcquireConnection(ctx); err != nil {
-		fmt.Println(ui.Red("Failed to acquire connection from pool"), err)
-		return
+	if err := pm.AcquireConnection(ctx); err != nil {
+		return 0, fmt.Errorf("failed to acquire connection from pool: %w", err)
 	}
-	defer perfManager.ReleaseConnection()
+	defer pm.ReleaseConnection()
 
 	// Check memory usage before starting transfer
-	if !perfManager.CheckMemoryLimit() {
-		fmt.Println(ui.Yellow("Memory usage high, optimizing..."))
-		perfManager.OptimizeMemory()
-	}
-
-	args := []string{
-		"--host1", srcHost, "--ssl1",
-		"--user1", srcEmail, "--password1", srcPass,
-		"--host2", dstHost, "--ssl2",
-		"--user2", dstEmail, "--password2", dstPass,
-		"--exclude", "^Junk\\ E-Mail",
-		"--exclude", "^Deleted\\ Items",
-		"--exclude", "^Deleted",
-		"--exclude", "^Trash",
-		"--regextrans2", "s#^Sent$#Sent Items#",
-		"--regextrans2", "s#^Spam$#Junk E-Mail#",
-		"--useuid",
-		"--usecache",
-		"--tmpdir", "./tmp",
-		"--syncinternaldates",
-		"--progress",
+	if !pm.CheckMemoryLimit() {
+		warning(i18n.T("transfer.memory_high"))
+		pm.OptimizeMemory()
+	}
+
+	// A single transfer gets the whole global cap, still bounded by the per-job and host caps
+	var limit BandwidthLimit
+	if bm, err := NewBandwidthManager(&CurrentConfig().Bandwidth); err != nil {
+		warning(i18n.T("error.bandwidth_disabled") + " " + err.Error())
+	} else {
+		limit = bm.Allocate(job, time.Now())
+	}
+
+	if job.Options.DryRun {
+		info(i18n.T("transfer.dry_run"))
 	}
 
 	startTime := time.Now()
-	cmd := exec.Command("imapsync", args...)
+	bytesTransferred, err := runImapsyncOnce(ctx, imapsyncArgs(job, "./tmp", limit), hooks.progress)
+	duration := time.Since(startTime)
+
+	if job.Options.DryRun {
+		return duration, err
+	}
+	if err != nil {
+		pm.UpdateStats(false, bytesTransferred)
+		return duration, err
+	}
+
+	pm.UpdateStats(true, bytesTransferred)
+	pm.RecordTransfer(cacheKey, TransferRecord{
+		Timestamp: time.Now(),
+		Duration:  duration,
+		Bytes:     bytesTransferred,
+	})
+	return duration, nil
+}
+
+// checkLogins runs imapsync --justlogin for a job and classifies a failure
+// from its output. Archive jobs only log in to the source.
+func checkLogins(ctx context.Context, job *TransferJob) error {
+	if job.IsArchive() {
+		return checkArchiveLogin(ctx, job)
+	}
+
+	output, err := exec.CommandContext(ctx, "imapsync", imapsyncLoginArgs(job)...).CombinedOutput()
+	if err == nil {
+		return nil
+	}
+
+	var detector failureDetector
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		detector.Observe(scanner.Text())
+	}
+	return detector.Classify(err)
+}
+
+// runImapsyncOnce runs imapsync in the foreground, reporting progress for each
+// line that changes it, and returns the bytes copied. A failed run is
+// classified from the output.
+func runImapsyncOnce(ctx context.Context, args []string, progress func(p transferProgress)) (int64, error) {
+	cmd := exec.CommandContext(ctx, "imapsync", args...)
 
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
-		fmt.Println(ui.Red("Error:"), err)
-		return
+		return 0, fmt.Errorf("failed to create stdout pipe: %w", err)
 	}
+	// Error messages go to stderr; read them with the rest to explain failures
+	cmd.Stderr = cmd.Stdout
 
 	if err := cmd.Start(); err != nil {
-		fmt.Println(ui.Red("Error:"), err)
-		return
+		return 0, fmt.Errorf("failed to start imapsync: %w", err)
 	}
 
-	bar := NewProgressBar(100)
-	bar.SetDescription("IMAPSYNC")
-
+	var p transferProgress
+	var detector failureDetector
 	scanner := bufio.NewScanner(stdout)
-	percentRe := regexp.MustCompile(`([0-9]{1,3}(?:\.[0-9]+)?)%`)
-	ratioRe := regexp.MustCompile(`(?i)(\d+)/(\d+)`)
 	for scanner.Scan() {
 		line := scanner.Text()
+		detector.Observe(line)
 
+		changed := true
+		folder := folderRe.FindStringSubmatch(line)
 		if m := percentRe.FindStringSubmatch(line); len(m) == 2 {
-			p, _ := strconv.ParseFloat(m[1], 64)
-			bar.Set(int(p))
-			continue
-		}
-		if m := ratioRe.FindStringSubmatch(line); len(m) == 3 {
+			p.Percent, _ = strconv.ParseFloat(m[1], 64)
+		} else if m := messagesLeftRe.FindStringSubmatch(line); len(m) == 3 {
+			left, _ := strconv.Atoi(m[1])
+			p.MessagesTotal, _ = strconv.Atoi(m[2])
+			p.MessagesDone = p.MessagesTotal - left
+			if p.MessagesTotal > 0 {
+				p.Percent = float64(p.MessagesDone) / float64(p.MessagesTotal) * 100
+			}
+		} else if m := ratioRe.FindStringSubmatch(line); len(m) == 3 && folder == nil {
 			current, _ := strconv.Atoi(m[1])
 			total, _ := strconv.Atoi(m[2])
 			if total > 0 {
-				bar.Set(int(float64(current) / float64(total) * 100))
+				p.Percent = float64(current) / float64(total) * 100
 			}
+		} else {
+			changed = false
 		}
-	}
 
-	var bytesTransferred int64
-	var transferSuccess bool
+		if len(folder) == 2 {
+			p.Folder, changed = folder[1], true
+		}
+		if m := copiedRe.FindStringSubmatch(line); len(m) == 2 {
+			size, _ := strconv.ParseInt(m[1], 10, 64)
+			p.Bytes += size
+			changed = true
+		}
 
-	if err := cmd.Wait(); err != nil {
-		fmt.Println() // newline after bar
-		fmt.Println(ui.Red("Mail transfer failed."))
-		fmt.Println(ui.Red("Error:"), err)
-		transferSuccess = false
-	} else {
-		transferSuccess = true
+		if changed && progress != nil {
+			progress(p)
+		}
 	}
 
-	bar.Finish()
-	fmt.Println() // newline after bar
-
-	// Calculate transfer statistics
-	duration := time.Since(startTime)
-	if transferSuccess {
-		// Estimate bytes transferred (this would be more accurate with actual parsing)
-		bytesTransferred = 1024 * 1024 // 1MB estimate
-		perfManager.UpdateStats(true, bytesTransferred)
-
-		// Cache successful transfer
-		transferInfo := map[string]interface{}{
-			"timestamp": time.Now(),
-			"duration":  duration,
-			"bytes":     bytesTransferred,
+	if err := cmd.Wait(); err != nil {
+		if ctx.Err() != nil {
+			return p.Bytes, ctx.Err()
 		}
-		perfManager.SetCachedData(cacheKey, transferInfo)
-
-		fmt.Println(ui.Green("Mail transfer completed successfully!"))
-		fmt.Printf("Transfer completed in %s\n", duration.Round(time.Second))
-	} else {
-		perfManager.UpdateStats(false, 0)
-		fmt.Println(ui.Red("Mail transfer failed."))
+		return p.Bytes, detector.Classify(err)
 	}
+	return p.Bytes, nil
 }
@@ -0,0 +1,325 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// WebhookConfig describes a webhook endpoint
+type WebhookConfig struct {
+	URL            string         `json:"url"`
+	Secret         string         `json:"secret"`           // HMAC-SHA256 signing key
+	Events         []JobEventType `json:"events"`           // Empty means all events
+	Timeout        Duration       `json:"timeout"`          // Per-request timeout
+	RetryAttempts  int            `json:"retry_attempts"`   // Deliveries before giving up
+	RetryDelay     Duration       `json:"retry_delay"`      // Initial delay, doubled per attempt
+	DeadLetterFile string         `json:"dead_letter_file"` // Undeliverable payloads are appended here
+	CloseTimeout   Duration       `json:"close_timeout"`    // How long Close waits for queued deliveries
+}
+
+// DefaultWebhookConfig returns default webhook delivery settings
+func DefaultWebhookConfig() *WebhookConfig {
+	return &WebhookConfig{
+		Timeout:        Duration{10 * time.Second},
+		RetryAttempts:  5,
+		RetryDelay:     Duration{2 * time.Second},
+		DeadLetterFile: "webhook_dead_letter.jsonl",
+		CloseTimeout:   Duration{30 * time.Second},
+	}
+}
+
+// wants reports whether the endpoint is subscribed to the event type
+func (wc *WebhookConfig) wants(eventType JobEventType) bool {
+	if len(wc.Events) == 0 {
+		return true
+	}
+	for _, e := range wc.Events {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// deadLetter is a single line of the dead-letter file
+type deadLetter struct {
+	Time     time.Time       `json:"time"`
+	URL      string          `json:"url"`
+	Attempts int             `json:"attempts"`
+	Error    string          `json:"error"`
+	Payload  json.RawMessage `json:"payload"`
+}
+
+// WebhookNotifier posts signed JSON payloads to a webhook endpoint
+type WebhookNotifier struct {
+	config *WebhookConfig
+	client *http.Client
+	logger *Logger
+	queue  chan JobEvent
+	wg     sync.WaitGroup
+	once   sync.Once
+
+	// ctx is cancelled when Close gives up waiting; deliveries then stop and
+	// the remaining events go to the dead-letter file
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu      sync.Mutex    // guards dropped
+	dropped []JobEvent    // Events turned away by the full queue, written as dead letters by run
+	wake    chan struct{} // Tells run that events were dropped
+}
+
+// NewWebhookNotifier creates a webhook notifier and starts its delivery goroutine
+func NewWebhookNotifier(config *WebhookConfig) *WebhookNotifier {
+	defaults := DefaultWebhookConfig()
+	if config == nil {
+		config = defaults
+	}
+	if config.Timeout.Duration <= 0 {
+		config.Timeout = defaults.Timeout
+	}
+	if config.RetryAttempts <= 0 {
+		config.RetryAttempts = defaults.RetryAttempts
+	}
+	if config.RetryDelay.Duration <= 0 {
+		config.RetryDelay = defaults.RetryDelay
+	}
+	if config.DeadLetterFile == "" {
+		config.DeadLetterFile = defaults.DeadLetterFile
+	}
+	if config.CloseTimeout.Duration <= 0 {
+		config.CloseTimeout = defaults.CloseTimeout
+	}
+
+	logger := NewLogger()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	wn := &WebhookNotifier{
+		ctx:    ctx,
+		cancel: cancel,
+		config: config,
+		client: &http.Client{Timeout: config.Timeout.Duration},
+		logger: logger,
+		queue:  make(chan JobEvent, 256),
+		wake:   make(chan struct{}, 1),
+	}
+
+	wn.wg.Add(1)
+	go wn.run()
+
+	return wn
+}
+
+// Notify queues an event for delivery. It never blocks: when the queue is
+// full the event is handed to the delivery goroutine as a dead letter.
+func (wn *WebhookNotifier) Notify(event JobEvent) {
+	if !wn.config.wants(event.Type) {
+		return
+	}
+
+	select {
+	case wn.queue <- event:
+	default:
+		wn.mu.Lock()
+		wn.dropped = append(wn.dropped, event)
+		wn.mu.Unlock()
+
+		select {
+		case wn.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Close stops accepting events and waits for queued deliveries to finish.
+// After CloseTimeout it aborts the delivery in progress and writes it and the
+// remaining events to the dead-letter file.
+func (wn *WebhookNotifier) Close() error {
+	wn.once.Do(func() {
+		close(wn.queue)
+	})
+
+	done := make(chan struct{})
+	go func() {
+		wn.wg.Wait()
+		close(done)
+	}()
+
+	timer := time.NewTimer(wn.config.CloseTimeout.Duration)
+	defer timer.Stop()
+	select {
+	case <-done:
+		wn.cancel()
+		return nil
+	case <-timer.C:
+	}
+
+	wn.cancel()
+	<-done
+	return fmt.Errorf("webhook %s: deliveries still pending after %s were written to %s", wn.config.URL, wn.config.CloseTimeout.Duration, wn.config.DeadLetterFile)
+}
+
+// run delivers queued events in order and writes dropped events to the dead-letter file
+func (wn *WebhookNotifier) run() {
+	defer wn.wg.Done()
+
+	for {
+		select {
+		case event, ok := <-wn.queue:
+			wn.writeDropped()
+			if !ok {
+				return
+			}
+			payload, err := json.Marshal(event)
+			if err != nil {
+				wn.logger.Error("Webhook %s: failed to encode event: %v", wn.config.URL, err)
+				continue
+			}
+			if wn.ctx.Err() != nil {
+				wn.writeDeadLetter(payload, 0, errWebhookClosed)
+				continue
+			}
+			wn.deliver(payload, event.Type)
+		case <-wn.wake:
+			wn.writeDropped()
+		}
+	}
+}
+
+// writeDropped writes the events turned away by the full queue to the dead-letter file
+func (wn *WebhookNotifier) writeDropped() {
+	wn.mu.Lock()
+	dropped := wn.dropped
+	wn.dropped = nil
+	wn.mu.Unlock()
+
+	for _, event := range dropped {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			wn.logger.Error("Webhook %s: failed to encode event: %v", wn.config.URL, err)
+			continue
+		}
+		wn.writeDeadLetter(payload, 0, fmt.Errorf("delivery queue full"))
+	}
+}
+
+// errWebhookClosed is recorded for events dead-lettered because Close timed out
+var errWebhookClosed = errors.New("notifier closed before delivery")
+
+// deliver posts a payload, retrying with exponential backoff. It gives up
+// early when Close times out.
+func (wn *WebhookNotifier) deliver(payload []byte, eventType JobEventType) {
+	var lastErr error
+	delay := wn.config.RetryDelay.Duration
+
+	for attempt := 1; attempt <= wn.config.RetryAttempts; attempt++ {
+		if lastErr = wn.post(payload, eventType); lastErr == nil {
+			return
+		}
+		if wn.ctx.Err() != nil {
+			wn.writeDeadLetter(payload, attempt, fmt.Errorf("%w: %v", errWebhookClosed, lastErr))
+			return
+		}
+
+		wn.logger.Warn("Webhook %s attempt %d failed: %v", wn.config.URL, attempt, lastErr)
+		if attempt < wn.config.RetryAttempts {
+			select {
+			case <-time.After(delay):
+			case <-wn.ctx.Done():
+				wn.writeDeadLetter(payload, attempt, fmt.Errorf("%w: %v", errWebhookClosed, lastErr))
+				return
+			}
+			delay *= 2
+		}
+	}
+
+	wn.writeDeadLetter(payload, wn.config.RetryAttempts, lastErr)
+}
+
+// post sends a single signed request
+func (wn *WebhookNotifier) post(payload []byte, eventType JobEventType) error {
+	req, err := http.NewRequestWithContext(wn.ctx, http.MethodPost, wn.config.URL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "imapsync-cli")
+	req.Header.Set("X-Imapsync-Event", string(eventType))
+	if wn.config.Secret != "" {
+		// The timestamp is signed too, so receivers can reject replayed deliveries
+		timestamp := time.Now().Unix()
+		req.Header.Set("X-Imapsync-Timestamp", strconv.FormatInt(timestamp, 10))
+		req.Header.Set("X-Imapsync-Signature", "sha256="+SignPayload(wn.config.Secret, timestamp, payload))
+	}
+
+	resp, err := wn.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+// writeDeadLetter appends an undeliverable payload to the dead-letter file.
+// Only the delivery goroutine calls it.
+func (wn *WebhookNotifier) writeDeadLetter(payload []byte, attempts int, cause error) {
+	entry := deadLetter{
+		Time:     time.Now(),
+		URL:      wn.config.URL,
+		Attempts: attempts,
+		Payload:  payload,
+	}
+	if cause != nil {
+		entry.Error = cause.Error()
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		wn.logger.Error("Webhook %s: failed to encode dead letter: %v", wn.config.URL, err)
+		return
+	}
+
+	if dir := filepath.Dir(wn.config.DeadLetterFile); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			wn.logger.Error("Webhook %s: failed to create dead-letter directory: %v", wn.config.URL, err)
+		}
+	}
+	f, err := os.OpenFile(wn.config.DeadLetterFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		wn.logger.Error("Webhook %s: failed to open dead-letter file: %v", wn.config.URL, err)
+		return
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		wn.logger.Error("Webhook %s: failed to write dead letter to %s: %v", wn.config.URL, wn.config.DeadLetterFile, err)
+		return
+	}
+	wn.logger.Error("Webhook %s: gave up after %d attempts, payload written to %s", wn.config.URL, attempts, wn.config.DeadLetterFile)
+}
+
+// SignPayload returns the hex-encoded HMAC-SHA256 of "<timestamp>.<payload>"
+// using secret, where timestamp is in Unix seconds
+func SignPayload(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
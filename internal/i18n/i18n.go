@@ -0,0 +1,263 @@
+// Package i18n translates user interface strings. Message catalogs are JSON
+// files embedded from locales/, one per language, mapping a dotted key to
+// either a string or an object of plural forms:
+//
+//	{
+//	  "menu.exit": "Exit",
+//	  "jobs.cancelled": { "one": "Cancelled %d job", "other": "Cancelled %d jobs" }
+//	}
+//
+// Strings are fmt format strings. A key missing from the active language is
+// looked up along the fallback chain, e.g. "tr-TR" → "tr" → "en", and the key
+// itself is shown when no catalog has it.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+)
+
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+// DefaultLanguage is the last entry of every fallback chain
+const DefaultLanguage = "en"
+
+// message is a catalog entry: a single string or plural forms keyed by category
+type message struct {
+	text   string
+	plural map[string]string
+}
+
+// UnmarshalJSON accepts a string or an object of plural forms
+func (m *message) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &m.text); err == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, &m.plural); err != nil {
+		return fmt.Errorf("message must be a string or an object of plural forms: %w", err)
+	}
+	if _, ok := m.plural["other"]; !ok {
+		return fmt.Errorf("plural message has no \"other\" form")
+	}
+	return nil
+}
+
+// catalog maps message keys to messages for one language
+type catalog map[string]message
+
+// pluralRules pick the plural category of a count per language, following the
+// CLDR rules for cardinals. Languages without an entry use the English rule.
+var pluralRules = map[string]func(n int) string{
+	"en": pluralOneOther,
+	"tr": pluralOneOther,
+}
+
+// pluralOneOther is the rule of languages with a singular and a plural form
+func pluralOneOther(n int) string {
+	if n == 1 {
+		return "one"
+	}
+	return "other"
+}
+
+var (
+	mu       sync.RWMutex
+	catalogs = mustLoadCatalogs()
+	language = DefaultLanguage
+	chain    = []string{DefaultLanguage}
+)
+
+// mustLoadCatalogs parses the embedded catalogs. They ship with the binary, so
+// a malformed one is a build mistake.
+func mustLoadCatalogs() map[string]catalog {
+	files, err := localeFiles.ReadDir("locales")
+	if err != nil {
+		panic(err)
+	}
+
+	loaded := make(map[string]catalog, len(files))
+	for _, file := range files {
+		data, err := localeFiles.ReadFile("locales/" + file.Name())
+		if err != nil {
+			panic(err)
+		}
+		var c catalog
+		if err := json.Unmarshal(data, &c); err != nil {
+			panic(fmt.Sprintf("i18n: catalog %s: %v", file.Name(), err))
+		}
+		loaded[strings.TrimSuffix(file.Name(), path.Ext(file.Name()))] = c
+	}
+	return loaded
+}
+
+// normalize turns a language tag or locale such as "tr_TR.UTF-8" into "tr-TR"
+func normalize(tag string) string {
+	tag, _, _ = strings.Cut(tag, ".")
+	tag, _, _ = strings.Cut(tag, "@")
+	tag = strings.ReplaceAll(strings.TrimSpace(tag), "_", "-")
+
+	base, region, ok := strings.Cut(tag, "-")
+	if !ok {
+		return strings.ToLower(base)
+	}
+	return strings.ToLower(base) + "-" + strings.ToUpper(region)
+}
+
+// fallbackChain lists the catalogs consulted for a tag, most specific first
+func fallbackChain(tag string) []string {
+	var candidates []string
+	add := func(lang string) {
+		if _, ok := catalogs[lang]; ok && !contains(candidates, lang) {
+			candidates = append(candidates, lang)
+		}
+	}
+
+	tag = normalize(tag)
+	add(tag)
+	if base, _, ok := strings.Cut(tag, "-"); ok {
+		add(base)
+	}
+	add(DefaultLanguage)
+	return candidates
+}
+
+// contains reports whether list holds s
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// match returns the catalog that best fits tag, without falling back to the
+// default language
+func match(tag string) (string, bool) {
+	best := fallbackChain(tag)[0]
+	tag = normalize(tag)
+	base, _, _ := strings.Cut(tag, "-")
+	if best == tag || best == base {
+		return best, true
+	}
+	return "", false
+}
+
+// SetLanguage switches the language of all translated strings. Tags such as
+// "tr", "tr-TR" and "tr_TR.UTF-8" select the Turkish catalog.
+func SetLanguage(tag string) error {
+	lang, ok := match(tag)
+	if !ok {
+		return fmt.Errorf("unsupported language %q (available: %s)", tag, strings.Join(Languages(), ", "))
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	language = lang
+	chain = fallbackChain(tag)
+	return nil
+}
+
+// Language returns the active language
+func Language() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return language
+}
+
+// Languages returns the languages that have a catalog, sorted
+func Languages() []string {
+	langs := make([]string, 0, len(catalogs))
+	for lang := range catalogs {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// Name returns the name of a language in that language, e.g. "Türkçe"
+func Name(lang string) string {
+	if m, ok := catalogs[lang]["language.name"]; ok {
+		return m.text
+	}
+	return lang
+}
+
+// Detect returns the supported language named by the LC_ALL, LC_MESSAGES or
+// LANG environment variables, or an empty string when none is supported
+func Detect() string {
+	for _, env := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		value := os.Getenv(env)
+		if value == "" {
+			continue
+		}
+		// The first variable set decides, as for the C library
+		lang, _ := match(value)
+		return lang
+	}
+	return ""
+}
+
+// lookup finds a message along the fallback chain of the active language
+func lookup(key string) (message, string, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	for _, lang := range chain {
+		if m, ok := catalogs[lang][key]; ok {
+			return m, lang, true
+		}
+	}
+	return message{}, "", false
+}
+
+// T returns the translation of key, formatted with args when any are given
+func T(key string, args ...interface{}) string {
+	m, _, ok := lookup(key)
+	if !ok {
+		return key
+	}
+	text := m.text
+	if m.plural != nil {
+		text = m.plural["other"]
+	}
+	return format(text, args)
+}
+
+// N returns the plural form of key for the count n, formatted with args. The
+// count is not added to args; pass it again when the message shows it.
+func N(key string, n int, args ...interface{}) string {
+	m, lang, ok := lookup(key)
+	if !ok {
+		return key
+	}
+	if m.plural == nil {
+		return format(m.text, args)
+	}
+
+	rule, ok := pluralRules[lang]
+	if !ok {
+		rule = pluralOneOther
+	}
+	text, ok := m.plural[rule(n)]
+	if !ok {
+		text = m.plural["other"]
+	}
+	return format(text, args)
+}
+
+// format applies args to a format string; without args the text is returned
+// unchanged, so messages need no escaping of "%"
+func format(text string, args []interface{}) string {
+	if len(args) == 0 {
+		return text
+	}
+	return fmt.Sprintf(text, args...)
+}
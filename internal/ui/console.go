@@ -1,50 +1,56 @@
 package ui
 
+const colorReset = "\033[0m"
+
+// Foreground colors of the active palette and text attributes. A Style
+// combines at most one color with any attributes.
 const (
-	colorReset  = "\033[0m"
-	colorCyan   = "\033[36m"
-	colorGreen  = "\033[32m"
-	colorYellow = "\033[33m"
-	colorRed    = "\033[31m"
-	colorBlue   = "\033[34m"
-	colorPurple = "\033[35m"
-	colorWhite  = "\033[37m"
-	colorBold   = "\033[1m"
-	colorDim    = "\033[2m"
+	fgCyan Style = iota + 1
+	fgGreen
+	fgYellow
+	fgRed
+	fgBlue
+	fgPurple
+	fgWhite
+
+	fgMask      Style = 0x0f
+	attrBold    Style = 1 << 4
+	attrDim     Style = 1 << 5
+	attrReverse Style = 1 << 6
 )
 
 func Cyan(text string) string {
-	return colorCyan + text + colorReset
+	return fgCyan.Render(text)
 }
 
 func Green(text string) string {
-	return colorGreen + text + colorReset
+	return fgGreen.Render(text)
 }
 
 func Yellow(text string) string {
-	return colorYellow + text + colorReset
+	return fgYellow.Render(text)
 }
 
 func Red(text string) string {
-	return colorRed + text + colorReset
+	return fgRed.Render(text)
 }
 
 func Blue(text string) string {
-	return colorBlue + text + colorReset
+	return fgBlue.Render(text)
 }
 
 func Purple(text string) string {
-	return colorPurple + text + colorReset
+	return fgPurple.Render(text)
 }
 
 func White(text string) string {
-	return colorWhite + text + colorReset
+	return fgWhite.Render(text)
 }
 
 func Bold(text string) string {
-	return colorBold + text + colorReset
+	return attrBold.Render(text)
 }
 
 func Dim(text string) string {
-	return colorDim + text + colorReset
+	return attrDim.Render(text)
 }
@@ -0,0 +1,303 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"imapsync/internal/i18n"
+)
+
+// DashboardRow is one job in the live dashboard
+type DashboardRow struct {
+	ID      string
+	Status  string
+	Current int // Work done, e.g. messages copied
+	Total   int
+	Speed   float64 // Bytes per second
+	ETA     time.Duration
+	Folder  string
+	Detail  string // Shown under the table while the row is selected
+}
+
+// DashboardAction binds a key to an operation on the selected row. The key
+// '\r' binds Enter. Run returns a message for the status line; it receives an
+// empty row when no job is shown.
+type DashboardAction struct {
+	Key   rune
+	Label string
+	Run   func(row DashboardRow) string
+}
+
+// dashboardSorts are the orders the dashboard cycles through
+var dashboardSorts = []string{"added", "status", "progress", "speed", "eta"}
+
+// dashboardRefresh is how often the dashboard redraws without input
+const dashboardRefresh = 500 * time.Millisecond
+
+// dashboard holds the view state of ShowDashboard
+type dashboard struct {
+	sort      int
+	status    string // Only rows with this status are shown; empty shows all
+	query     string // Only rows whose ID, folder or detail contain it are shown
+	searching bool
+	selected  string // ID of the selected row, kept across refreshes
+	message   string
+	height    int // Rows of the table, for paging
+}
+
+// ShowDashboard shows a live table of jobs until the user leaves it. rows is
+// called on every refresh, so the jobs keep running in the background. Without
+// full-screen mode a single snapshot is printed.
+func (tui *SimpleTUI) ShowDashboard(title string, rows func() []DashboardRow, actions []DashboardAction) {
+	if !tui.FullScreen() {
+		tui.ShowBanner()
+		fmt.Println(Bold(Yellow("  📋 " + title)))
+		fmt.Println(Dim("  " + strings.Repeat("─", 70)))
+		for _, row := range rows() {
+			tui.ShowLiveProgress(row.ID, row.Current, max(row.Total, 1), row.Speed, row.ETA)
+			fmt.Printf(" %s %s\n", White(StatusLabel(row.Status)), Dim(row.Folder))
+		}
+		fmt.Println()
+		tui.WaitForKey()
+		return
+	}
+
+	d := &dashboard{}
+	list := &List{}
+	for {
+		all := rows()
+		shown := d.filter(all)
+		tui.drawDashboard(title, d, list, all, shown, actions)
+
+		key := tui.screen.PollKey(dashboardRefresh)
+		if key.Code == KeyNone || key.Code == KeyResize {
+			continue
+		}
+		if d.searching {
+			d.editQuery(key)
+			continue
+		}
+
+		var row DashboardRow
+		if list.Selected >= 0 && list.Selected < len(shown) {
+			row = shown[list.Selected]
+		}
+		switch {
+		case list.HandleKey(key, d.height):
+			if list.Selected >= 0 && list.Selected < len(shown) {
+				d.selected = shown[list.Selected].ID
+			}
+		case key.Code == KeyEsc, key.Code == KeyCtrlC, key.Code == KeyRune && key.Rune == 'q':
+			return
+		case key.Code == KeyRune && key.Rune == 'o':
+			d.sort = (d.sort + 1) % len(dashboardSorts)
+		case key.Code == KeyRune && key.Rune == 'f':
+			d.status = nextStatus(all, d.status)
+		case key.Code == KeyRune && key.Rune == '/':
+			d.searching = true
+		default:
+			for _, action := range actions {
+				if (key.Code == KeyEnter && action.Key == '\r') || (key.Code == KeyRune && key.Rune == action.Key) {
+					d.message = action.Run(row)
+					break
+				}
+			}
+		}
+	}
+}
+
+// editQuery applies a key while the search query is being typed
+func (d *dashboard) editQuery(key Key) {
+	switch key.Code {
+	case KeyEnter:
+		d.searching = false
+	case KeyEsc, KeyCtrlC:
+		d.searching = false
+		d.query = ""
+	case KeyBackspace:
+		if runes := []rune(d.query); len(runes) > 0 {
+			d.query = string(runes[:len(runes)-1])
+		}
+	case KeyRune:
+		d.query += string(key.Rune)
+	}
+}
+
+// filter returns the rows matching the status filter and query, sorted
+func (d *dashboard) filter(rows []DashboardRow) []DashboardRow {
+	query := strings.ToLower(d.query)
+	var shown []DashboardRow
+	for _, row := range rows {
+		if d.status != "" && row.Status != d.status {
+			continue
+		}
+		if query != "" && !strings.Contains(strings.ToLower(row.ID+" "+row.Folder+" "+row.Detail), query) {
+			continue
+		}
+		shown = append(shown, row)
+	}
+
+	sort.SliceStable(shown, func(i, j int) bool {
+		a, b := shown[i], shown[j]
+		switch dashboardSorts[d.sort] {
+		case "status":
+			return a.Status < b.Status
+		case "progress":
+			return a.percentage() > b.percentage()
+		case "speed":
+			return a.Speed > b.Speed
+		case "eta":
+			// Unknown ETAs go last
+			if (a.ETA == 0) != (b.ETA == 0) {
+				return b.ETA == 0
+			}
+			return a.ETA < b.ETA
+		}
+		return false
+	})
+	return shown
+}
+
+// percentage returns how much of the row's work is done
+func (r DashboardRow) percentage() float64 {
+	if r.Total <= 0 {
+		return 0
+	}
+	return float64(r.Current) / float64(r.Total) * 100
+}
+
+// nextStatus cycles the status filter through the statuses present in rows
+func nextStatus(rows []DashboardRow, current string) string {
+	seen := make(map[string]bool)
+	var statuses []string
+	for _, row := range rows {
+		if !seen[row.Status] {
+			seen[row.Status] = true
+			statuses = append(statuses, row.Status)
+		}
+	}
+	sort.Strings(statuses)
+
+	for i, status := range statuses {
+		if status == current {
+			if i+1 < len(statuses) {
+				return statuses[i+1]
+			}
+			return ""
+		}
+	}
+	if current == "" && len(statuses) > 0 {
+		return statuses[0]
+	}
+	return ""
+}
+
+// drawDashboard renders the dashboard table and status lines
+func (tui *SimpleTUI) drawDashboard(title string, d *dashboard, list *List, all, shown []DashboardRow, actions []DashboardAction) {
+	s := tui.screen
+	hints := i18n.T("dashboard.hint.select")
+	for _, action := range actions {
+		key := string(action.Key)
+		if action.Key == '\r' {
+			key = "Enter"
+		}
+		hints += " · " + key + " " + action.Label
+	}
+	hints += " · " + i18n.T("dashboard.hint.keys")
+	top, bottom := tui.frame(title, hints)
+	width, _ := s.Size()
+
+	filter := i18n.T("dashboard.filter.all")
+	if d.status != "" {
+		filter = StatusLabel(d.status)
+	}
+	info := i18n.N("dashboard.info", len(all), i18n.T("dashboard.sort."+dashboardSorts[d.sort]), filter, len(shown), len(all))
+	if d.searching || d.query != "" {
+		info += " · " + i18n.T("dashboard.search", d.query)
+	}
+	s.SetString(2, top, Truncate(info, width-4), StyleInfo)
+	if d.searching {
+		s.ShowCursor(2+StringWidth(info), top)
+	}
+
+	// Columns: ID, status, live progress, folder
+	idWidth := 6
+	for _, row := range all {
+		idWidth = max(idWidth, min(StringWidth(row.ID), 24))
+	}
+	barWidth := min(20, max(width-idWidth-80, 8))
+	header := fmt.Sprintf("%-*s %-12s %s", idWidth, i18n.T("dashboard.column.job"), i18n.T("dashboard.column.status"), i18n.T("dashboard.column.progress"))
+	s.SetString(2, top+2, Truncate(header, width-4), StyleBold)
+
+	// Two rows under the table hold the selected job's detail and the last message
+	tableTop := top + 3
+	tableHeight := max(bottom-tableTop-3, 1)
+	d.height = tableHeight
+
+	list.Items = list.Items[:0]
+	list.Selected = -1
+	for i, row := range shown {
+		line := fmt.Sprintf("%-*s %-12s %s", idWidth, Truncate(row.ID, idWidth), StatusLabel(row.Status),
+			liveProgressText(row.Current, row.Total, row.Speed, row.ETA, barWidth))
+		if row.Folder != "" {
+			line += "  " + row.Folder
+		}
+		list.Items = append(list.Items, line)
+		if row.ID == d.selected {
+			list.Selected = i
+		}
+	}
+	if len(shown) > 0 && list.Selected < 0 {
+		list.Selected = 0
+		d.selected = shown[0].ID
+	}
+	if len(shown) == 0 {
+		s.SetString(2, tableTop, i18n.T("dashboard.empty"), StyleDim)
+	}
+	list.Draw(s, 2, tableTop, width-4, tableHeight, func(i int) Style {
+		return statusStyle(shown[i].Status)
+	})
+
+	if list.Selected >= 0 {
+		s.SetString(2, bottom-2, Truncate(shown[list.Selected].Detail, width-4), StyleDim)
+	}
+	if d.message != "" {
+		s.SetString(2, bottom-1, Truncate(d.message, width-4), StyleWarning)
+	}
+	s.Flush()
+}
+
+// statusStyle colors a job status the way the status views do
+func statusStyle(status string) Style {
+	switch status {
+	case "running":
+		return StyleInfo
+	case "completed":
+		return StyleSuccess
+	case "failed", "cancelled":
+		return StyleError
+	case "pending", "paused", "waiting":
+		return StyleWarning
+	}
+	return StyleNormal
+}
+
+// StatusLabel translates a job status such as "running" for display
+func StatusLabel(status string) string {
+	return i18n.T("status." + status)
+}
+
+// liveProgressText formats a progress bar with counts, speed and ETA
+func liveProgressText(current, total int, speed float64, eta time.Duration, barWidth int) string {
+	percentage := 0.0
+	if total > 0 {
+		percentage = float64(current) / float64(total) * 100
+	}
+	text := fmt.Sprintf("[%s] %5.1f%% (%d/%d) %.2f KB/s", progressBar(percentage, barWidth), percentage, current, total, speed/1024)
+	if eta > 0 {
+		text += " " + i18n.T("ui.progress.eta") + ": " + eta.Round(time.Second).String()
+	}
+	return text
+}
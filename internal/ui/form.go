@@ -0,0 +1,211 @@
+package ui
+
+import (
+	"errors"
+	"net"
+	"net/mail"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"imapsync/internal/i18n"
+)
+
+// FieldKind is the kind of value a form field holds. It picks the icon, how
+// the value is entered and the built-in validation.
+type FieldKind int
+
+const (
+	FieldText     FieldKind = iota // Free text
+	FieldPassword                  // Secret text, masked and kept as typed
+	FieldHost                      // Host name or address with an optional port, e.g. "imap.example.com:993"
+	FieldEmail                     // Email address
+	FieldNumber                    // Integer
+	FieldBool                      // Yes or no
+	FieldSelect                    // One of Options
+)
+
+// FormField describes a field of a form
+type FormField struct {
+	Label    string
+	Kind     FieldKind
+	Default  string                   // Value of an empty field; "true" or "false" for bool fields
+	Required bool                     // An empty field without a default is an error
+	Options  []string                 // Choices of a select field
+	Validate func(value string) error // Extra check of a non-empty value, after the kind's own
+}
+
+// FormValues holds the values of a submitted form keyed by field label. Values
+// are normalized: trimmed, numbers without leading zeros, "true" or "false"
+// for bool fields and the option as listed for select fields.
+type FormValues map[string]string
+
+// Bool returns the value of a bool field
+func (v FormValues) Bool(label string) bool {
+	b, _ := strconv.ParseBool(v[label])
+	return b
+}
+
+// Int returns the value of a number field, or 0 when it is empty
+func (v FormValues) Int(label string) int {
+	n, _ := strconv.Atoi(v[label])
+	return n
+}
+
+// parse validates the input of a field and returns its normalized value
+func (f FormField) parse(input string) (string, error) {
+	if f.Kind != FieldPassword {
+		input = strings.TrimSpace(input)
+	}
+	if input == "" {
+		if f.Default == "" && f.Required {
+			return "", errors.New(i18n.T("ui.form.error.required"))
+		}
+		return f.Default, nil
+	}
+
+	value := input
+	switch f.Kind {
+	case FieldHost:
+		if err := validHost(input); err != nil {
+			return "", err
+		}
+	case FieldEmail:
+		if addr, err := mail.ParseAddress(input); err != nil || addr.Address != input {
+			return "", errors.New(i18n.T("ui.form.error.email"))
+		}
+	case FieldNumber:
+		n, err := strconv.Atoi(input)
+		if err != nil {
+			return "", errors.New(i18n.T("ui.form.error.number"))
+		}
+		value = strconv.Itoa(n)
+	case FieldBool:
+		b, ok := parseYesNo(input)
+		if !ok {
+			return "", errors.New(i18n.T("ui.form.error.bool"))
+		}
+		value = strconv.FormatBool(b)
+	case FieldSelect:
+		option, ok := f.option(input)
+		if !ok {
+			return "", errors.New(i18n.T("ui.form.error.select", strings.Join(f.Options, ", ")))
+		}
+		value = option
+	}
+
+	if f.Validate != nil {
+		if err := f.Validate(value); err != nil {
+			return "", err
+		}
+	}
+	return value, nil
+}
+
+// option finds a select option by its name, ignoring case, or by its number
+func (f FormField) option(input string) (string, bool) {
+	for _, option := range f.Options {
+		if strings.EqualFold(option, input) {
+			return option, true
+		}
+	}
+	if n, err := strconv.Atoi(input); err == nil && n >= 1 && n <= len(f.Options) {
+		return f.Options[n-1], true
+	}
+	return "", false
+}
+
+// choices lists the values a bool or select field cycles through in full-screen
+// mode. An optional field without a default may also be left empty.
+func (f FormField) choices() []string {
+	choices := f.Options
+	if f.Kind == FieldBool {
+		choices = []string{"true", "false"}
+	}
+	if !f.Required && f.Default == "" {
+		choices = append([]string{""}, choices...)
+	}
+	return choices
+}
+
+// display returns how a normalized value is shown
+func (f FormField) display(value string) string {
+	switch {
+	case f.Kind == FieldBool && value == "true":
+		return i18n.T("button.yes")
+	case f.Kind == FieldBool && value == "false":
+		return i18n.T("button.no")
+	case value == "" && (f.Kind == FieldBool || f.Kind == FieldSelect):
+		return "-"
+	}
+	return value
+}
+
+// parseYesNo reads a yes/no answer in English or the active language; any
+// prefix such as "y" counts
+func parseYesNo(answer string) (bool, bool) {
+	answer = strings.ToLower(answer)
+	for _, yes := range []string{"yes", "true", strings.ToLower(i18n.T("button.yes"))} {
+		if strings.HasPrefix(yes, answer) {
+			return true, true
+		}
+	}
+	for _, no := range []string{"no", "false", strings.ToLower(i18n.T("button.no"))} {
+		if strings.HasPrefix(no, answer) {
+			return false, true
+		}
+	}
+	return false, false
+}
+
+// validHost checks a host name, IPv4 or IPv6 address with an optional port
+func validHost(value string) error {
+	host := value
+	if h, port, err := net.SplitHostPort(value); err == nil {
+		if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+			return errors.New(i18n.T("ui.form.error.port"))
+		}
+		host = h
+	} else if strings.Count(value, ":") == 1 {
+		return errors.New(i18n.T("ui.form.error.port"))
+	}
+
+	if net.ParseIP(host) != nil {
+		return nil
+	}
+	for _, label := range strings.Split(host, ".") {
+		if label == "" || strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+			return errors.New(i18n.T("ui.form.error.host"))
+		}
+		for _, r := range label {
+			if r != '-' && r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+				return errors.New(i18n.T("ui.form.error.host"))
+			}
+		}
+	}
+	return nil
+}
+
+// fieldIcon picks the icon shown next to a form field
+func fieldIcon(field FormField) (string, Style) {
+	icon, style := "📝", StyleNormal
+	switch field.Kind {
+	case FieldEmail:
+		icon, style = "📧", StyleAccent
+	case FieldPassword:
+		icon, style = "🔒", StyleError
+	case FieldHost:
+		icon, style = "🌐", StyleSuccess
+	case FieldNumber:
+		icon, style = "🔢", StyleWarning
+	case FieldBool:
+		icon, style = "🔘", StyleInfo
+	case FieldSelect:
+		icon, style = "📋", StyleInfo
+	}
+	// ASCII mode drops emoji; keep a marker in front of the label
+	if CurrentTheme().ASCII {
+		icon = ">"
+	}
+	return icon, style
+}
@@ -0,0 +1,196 @@
+package ui
+
+import (
+	"os"
+	"time"
+	"unicode/utf8"
+)
+
+// KeyCode identifies a key read in full-screen mode
+type KeyCode int
+
+const (
+	KeyNone KeyCode = iota // No key arrived before the timeout
+	KeyRune
+	KeyEnter
+	KeyEsc
+	KeyBackspace
+	KeyDelete
+	KeyTab
+	KeyBacktab
+	KeyUp
+	KeyDown
+	KeyLeft
+	KeyRight
+	KeyHome
+	KeyEnd
+	KeyPgUp
+	KeyPgDn
+	KeyCtrlC
+	KeyResize // The terminal was resized; the screen buffers were reset
+)
+
+// Key is a decoded key press. Rune is set for KeyRune.
+type Key struct {
+	Code KeyCode
+	Rune rune
+}
+
+// escapeTimeout is how long to wait for the rest of an escape sequence before
+// treating the escape byte as the Esc key
+const escapeTimeout = 50 * time.Millisecond
+
+// ReadKey blocks until a key is pressed or the terminal is resized
+func (s *Screen) ReadKey() Key {
+	for {
+		if key := s.PollKey(time.Second); key.Code != KeyNone {
+			return key
+		}
+	}
+}
+
+// PollKey waits up to timeout for a key. It returns KeyNone when nothing was
+// pressed, so callers can redraw live content between keys.
+func (s *Screen) PollKey(timeout time.Duration) Key {
+	b, ok := s.readByte(timeout)
+	if !ok {
+		if s.checkResize() {
+			return Key{Code: KeyResize}
+		}
+		return Key{}
+	}
+
+	switch {
+	case b == 0x1b:
+		return s.readEscape()
+	case b == '\r' || b == '\n':
+		return Key{Code: KeyEnter}
+	case b == 0x7f || b == 0x08:
+		return Key{Code: KeyBackspace}
+	case b == '\t':
+		return Key{Code: KeyTab}
+	case b == 0x03:
+		return Key{Code: KeyCtrlC}
+	case b < 0x20:
+		return Key{}
+	case b < utf8.RuneSelf:
+		return Key{Code: KeyRune, Rune: rune(b)}
+	}
+
+	// Collect the remaining bytes of a UTF-8 sequence
+	buf := []byte{b}
+	for !utf8.FullRune(buf) && len(buf) < utf8.UTFMax {
+		next, ok := s.readByte(escapeTimeout)
+		if !ok {
+			break
+		}
+		buf = append(buf, next)
+	}
+	r, _ := utf8.DecodeRune(buf)
+	return Key{Code: KeyRune, Rune: r}
+}
+
+// readEscape decodes the bytes following an escape
+func (s *Screen) readEscape() Key {
+	b, ok := s.readByte(escapeTimeout)
+	if !ok {
+		return Key{Code: KeyEsc}
+	}
+	if b != '[' && b != 'O' {
+		return Key{Code: KeyEsc}
+	}
+
+	// CSI and SS3 sequences end with a byte in the range 0x40-0x7e
+	var params []byte
+	for {
+		c, ok := s.readByte(escapeTimeout)
+		if !ok {
+			return Key{Code: KeyEsc}
+		}
+		if c >= 0x40 && c <= 0x7e {
+			return decodeSequence(string(params), c)
+		}
+		params = append(params, c)
+	}
+}
+
+// decodeSequence maps an escape sequence to a key
+func decodeSequence(params string, final byte) Key {
+	switch final {
+	case 'A':
+		return Key{Code: KeyUp}
+	case 'B':
+		return Key{Code: KeyDown}
+	case 'C':
+		return Key{Code: KeyRight}
+	case 'D':
+		return Key{Code: KeyLeft}
+	case 'H':
+		return Key{Code: KeyHome}
+	case 'F':
+		return Key{Code: KeyEnd}
+	case 'Z':
+		return Key{Code: KeyBacktab}
+	case '~':
+		switch params {
+		case "1", "7":
+			return Key{Code: KeyHome}
+		case "4", "8":
+			return Key{Code: KeyEnd}
+		case "3":
+			return Key{Code: KeyDelete}
+		case "5":
+			return Key{Code: KeyPgUp}
+		case "6":
+			return Key{Code: KeyPgDn}
+		}
+	}
+	return Key{}
+}
+
+// readByte returns the next input byte, waiting up to timeout. Bytes already
+// buffered by the shared stdin reader are consumed first.
+func (s *Screen) readByte(timeout time.Duration) (byte, bool) {
+	if stdin.Buffered() > 0 {
+		b, err := stdin.ReadByte()
+		return b, err == nil
+	}
+	if len(s.pending) > 0 {
+		b := s.pending[0]
+		s.pending = s.pending[1:]
+		return b, true
+	}
+
+	deadline := time.Now().Add(timeout)
+	buf := make([]byte, 64)
+	for {
+		// Each read returns after at most 100ms in timed raw mode
+		n, err := readTimed(int(os.Stdin.Fd()), buf)
+		if n > 0 {
+			s.pending = append(s.pending, buf[1:n]...)
+			return buf[0], true
+		}
+		if err != nil || time.Now().After(deadline) || s.resizePending() {
+			return 0, false
+		}
+	}
+}
+
+// resizePending reports whether a resize signal is waiting
+func (s *Screen) resizePending() bool {
+	return len(s.resize) > 0
+}
+
+// checkResize consumes a pending resize signal and resets the buffers
+func (s *Screen) checkResize() bool {
+	select {
+	case <-s.resize:
+	default:
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.updateSize()
+	return true
+}
@@ -0,0 +1,420 @@
+package ui
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+const (
+	enterAltScreen = "\033[?1049h"
+	leaveAltScreen = "\033[?1049l"
+	hideCursor     = "\033[?25l"
+	showCursor     = "\033[?25h"
+	clearScreen    = "\033[2J"
+)
+
+// ErrNotTerminal is returned when full-screen mode needs a terminal on both stdin and stdout
+var ErrNotTerminal = errors.New("standard input and output must be a terminal")
+
+// ErrDumbTerminal is returned when TERM is "dumb": the terminal cannot move the cursor
+var ErrDumbTerminal = errors.New("terminal does not support cursor movement")
+
+// Style is how a cell or a piece of text is drawn: a color of the active
+// theme's palette and text attributes
+type Style uint16
+
+// Styles used by the full-screen widgets
+const (
+	StyleNormal   Style = 0
+	StyleBold     Style = attrBold
+	StyleDim      Style = attrDim
+	StyleTitle    Style = attrBold | fgYellow
+	StyleBanner   Style = attrBold | fgCyan
+	StyleSelected Style = attrBold | attrReverse
+	StyleInfo     Style = fgCyan
+	StyleSuccess  Style = fgGreen
+	StyleWarning  Style = fgYellow
+	StyleError    Style = fgRed
+	StyleAccent   Style = fgBlue
+)
+
+// code returns the escape sequence of the style in the active theme. Without
+// colors only the attributes remain.
+func (st Style) code() string {
+	t := CurrentTheme()
+	p := palettes[t.Palette]
+
+	var code string
+	if st&attrBold != 0 {
+		code += "\033[1m"
+	}
+	if st&attrDim != 0 && p.dim {
+		code += "\033[2m"
+	}
+	if st&attrReverse != 0 {
+		code += "\033[7m"
+	}
+	if fg := st & fgMask; fg != 0 {
+		code += p.colors[fg].code(t.Colors)
+	}
+	return code
+}
+
+// Render returns text in the style for line-by-line output. Without colors the
+// text is returned as is, so captured output holds no escape codes.
+func (st Style) Render(text string) string {
+	text = glyphs(text)
+	if st == StyleNormal || CurrentTheme().Colors == ColorNone {
+		return text
+	}
+	return st.code() + text + colorReset
+}
+
+// cell is one screen column. A wide character occupies its cell and marks the
+// next one as a continuation.
+type cell struct {
+	text  string
+	style Style
+	cont  bool
+}
+
+// Screen is a double-buffered full-screen terminal. Drawing goes to the back
+// buffer; Flush writes only the rows that differ from what is displayed.
+type Screen struct {
+	mu     sync.Mutex
+	width  int
+	height int
+	back   [][]cell
+	front  [][]cell // What the terminal shows; nil rows are redrawn
+	out    *bufio.Writer
+
+	cursorX, cursorY int
+	cursorVisible    bool
+
+	active  bool
+	restore func()
+	resize  chan os.Signal
+	pending []byte // Bytes read but not yet decoded into keys
+}
+
+// NewScreen creates a screen for the current terminal. It fails when stdin or
+// stdout is not a terminal.
+func NewScreen() (*Screen, error) {
+	if !IsTerminal() || !isTerminal(int(os.Stdout.Fd())) {
+		return nil, ErrNotTerminal
+	}
+	if os.Getenv("TERM") == "dumb" {
+		return nil, ErrDumbTerminal
+	}
+	return &Screen{out: bufio.NewWriterSize(os.Stdout, 32*1024)}, nil
+}
+
+// Start switches to the alternate screen buffer and raw input
+func (s *Screen) Start() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.active {
+		return nil
+	}
+	restore, err := enableRawMode(true)
+	if err != nil {
+		return fmt.Errorf("enter raw mode: %w", err)
+	}
+	s.restore = restore
+
+	rawMu.Lock()
+	altScreen = true
+	rawMu.Unlock()
+	s.out.WriteString(enterAltScreen + hideCursor + clearScreen)
+	s.out.Flush()
+
+	s.resize = make(chan os.Signal, 1)
+	notifyResize(s.resize)
+	s.active = true
+	s.updateSize()
+	return nil
+}
+
+// Stop leaves the alternate screen buffer and restores the terminal
+func (s *Screen) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.active {
+		return
+	}
+	s.active = false
+	signal.Stop(s.resize)
+	s.resize = nil
+
+	rawMu.Lock()
+	altScreen = false
+	rawMu.Unlock()
+	s.out.WriteString(colorReset + showCursor + leaveAltScreen)
+	s.out.Flush()
+	s.restore()
+}
+
+// Active reports whether the screen is started
+func (s *Screen) Active() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.active
+}
+
+// Size returns the screen width and height in cells
+func (s *Screen) Size() (width, height int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.width, s.height
+}
+
+// updateSize reads the terminal size and resets both buffers. Callers must hold s.mu.
+func (s *Screen) updateSize() {
+	width, height, ok := terminalSize(int(os.Stdout.Fd()))
+	if !ok {
+		width, height = 80, 24
+	}
+	s.width, s.height = width, height
+	s.back = make([][]cell, height)
+	s.front = make([][]cell, height)
+	for y := range s.back {
+		s.back[y] = make([]cell, width)
+	}
+	s.out.WriteString(clearScreen)
+}
+
+// Clear blanks the back buffer
+func (s *Screen) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, row := range s.back {
+		for x := range row {
+			row[x] = cell{}
+		}
+	}
+	s.cursorVisible = false
+}
+
+// SetString draws text at x, y, clipped to the screen width. It returns the
+// column after the last cell drawn.
+func (s *Screen) SetString(x, y int, text string, style Style) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if y < 0 || y >= s.height {
+		return x
+	}
+	text = glyphs(text)
+	row := s.back[y]
+	last := -1 // Cell of the previous character, for attaching zero-width runes
+	joined := false
+	for _, r := range text {
+		w := RuneWidth(r)
+		if w == 0 || joined {
+			// Joiner sequences such as 👨‍💻 draw as a single character
+			if last >= 0 {
+				row[last].text += string(r)
+			}
+			joined = r == zeroWidthJoiner
+			continue
+		}
+		joined = false
+		if r == '\t' || r < 0x20 {
+			r, w = ' ', 1
+		}
+		if x < 0 {
+			x += w
+			continue
+		}
+		if x+w > s.width {
+			break
+		}
+		// Never leave half of a wide character behind
+		if row[x].cont && x > 0 {
+			row[x-1] = cell{text: " ", style: row[x-1].style}
+		}
+		if x+w < s.width && row[x+w].cont {
+			row[x+w] = cell{text: " ", style: row[x+w].style}
+		}
+		row[x] = cell{text: string(r), style: style}
+		if w == 2 {
+			row[x+1] = cell{style: style, cont: true}
+		}
+		last = x
+		x += w
+	}
+	return x
+}
+
+// Fill paints a rectangle with spaces in the given style
+func (s *Screen) Fill(x, y, width, height int, style Style) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for row := y; row < y+height && row < s.height; row++ {
+		if row < 0 {
+			continue
+		}
+		for col := x; col < x+width && col < s.width; col++ {
+			if col >= 0 {
+				s.back[row][col] = cell{text: " ", style: style}
+			}
+		}
+	}
+}
+
+// ShowCursor places the terminal cursor at x, y after the next Flush
+func (s *Screen) ShowCursor(x, y int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cursorX, s.cursorY, s.cursorVisible = x, y, true
+}
+
+// Invalidate forces the next Flush to redraw every row
+func (s *Screen) Invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for y := range s.front {
+		s.front[y] = nil
+	}
+	s.out.WriteString(clearScreen)
+}
+
+// Flush writes the rows that changed since the last Flush to the terminal
+func (s *Screen) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.active {
+		return nil
+	}
+
+	s.out.WriteString(hideCursor)
+	for y, row := range s.back {
+		if rowsEqual(row, s.front[y]) {
+			continue
+		}
+		fmt.Fprintf(s.out, "\033[%d;1H%s", y+1, colorReset)
+		current := StyleNormal
+		for _, c := range row {
+			if c.cont {
+				continue
+			}
+			if c.style != current {
+				s.out.WriteString(colorReset + c.style.code())
+				current = c.style
+			}
+			if c.text == "" {
+				s.out.WriteByte(' ')
+			} else {
+				s.out.WriteString(c.text)
+			}
+		}
+		s.out.WriteString(colorReset)
+		s.front[y] = append(s.front[y][:0], row...)
+	}
+	if s.cursorVisible {
+		fmt.Fprintf(s.out, "\033[%d;%dH%s", s.cursorY+1, s.cursorX+1, showCursor)
+	}
+	return s.out.Flush()
+}
+
+// rowsEqual reports whether two buffer rows display the same content
+func rowsEqual(a, b []cell) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// StringWidth returns the number of cells text occupies in the active theme
+func StringWidth(text string) int {
+	text = glyphs(text)
+	width := 0
+	joined := false
+	for _, r := range text {
+		if !joined {
+			width += RuneWidth(r)
+		}
+		joined = r == zeroWidthJoiner
+	}
+	return width
+}
+
+// Truncate shortens text to at most width cells, marking the cut with an ellipsis
+func Truncate(text string, width int) string {
+	text = glyphs(text)
+	if StringWidth(text) <= width {
+		return text
+	}
+	if width <= 0 {
+		return ""
+	}
+	var sb strings.Builder
+	used := 0
+	for _, r := range text {
+		w := RuneWidth(r)
+		if used+w > width-1 {
+			break
+		}
+		sb.WriteRune(r)
+		used += w
+	}
+	sb.WriteString(glyphs("…"))
+	return sb.String()
+}
+
+// zeroWidthJoiner glues emoji into one character
+const zeroWidthJoiner = 0x200D
+
+// wideRanges lists code points most terminals draw two cells wide: CJK and emoji
+var wideRanges = []struct{ lo, hi rune }{
+	{0x1100, 0x115F}, {0x231A, 0x231B}, {0x23E9, 0x23EC}, {0x23F0, 0x23F0},
+	{0x23F3, 0x23F3}, {0x25FD, 0x25FE}, {0x2614, 0x2615}, {0x2648, 0x2653},
+	{0x267F, 0x267F}, {0x2693, 0x2693}, {0x26A1, 0x26A1}, {0x26AA, 0x26AB},
+	{0x26BD, 0x26BE}, {0x26C4, 0x26C5}, {0x26CE, 0x26CE}, {0x26D4, 0x26D4},
+	{0x26EA, 0x26EA}, {0x26F2, 0x26F3}, {0x26F5, 0x26F5}, {0x26FA, 0x26FA},
+	{0x26FD, 0x26FD}, {0x2705, 0x2705}, {0x270A, 0x270B}, {0x2728, 0x2728},
+	{0x274C, 0x274C}, {0x274E, 0x274E}, {0x2753, 0x2755}, {0x2757, 0x2757},
+	{0x2795, 0x2797}, {0x27B0, 0x27B0}, {0x27BF, 0x27BF}, {0x2B1B, 0x2B1C},
+	{0x2B50, 0x2B50}, {0x2B55, 0x2B55}, {0x2E80, 0x303E}, {0x3041, 0x33FF},
+	{0x3400, 0x4DBF}, {0x4E00, 0x9FFF}, {0xA000, 0xA4CF}, {0xAC00, 0xD7A3},
+	{0xF900, 0xFAFF}, {0xFE30, 0xFE4F}, {0xFF00, 0xFF60}, {0xFFE0, 0xFFE6},
+	{0x1F004, 0x1F004}, {0x1F0CF, 0x1F0CF}, {0x1F18E, 0x1F18E}, {0x1F191, 0x1F19A},
+	{0x1F200, 0x1F64F}, {0x1F680, 0x1F6FF}, {0x1F7E0, 0x1F7EB}, {0x1F900, 0x1F9FF},
+	{0x1FA70, 0x1FAFF}, {0x20000, 0x3FFFD},
+}
+
+// RuneWidth returns the number of cells r occupies: 0 for combining marks,
+// joiners and variation selectors, 2 for wide characters and 1 otherwise.
+func RuneWidth(r rune) int {
+	switch {
+	case r == zeroWidthJoiner, r == 0xFE0E, r == 0xFE0F:
+		return 0
+	case unicode.In(r, unicode.Mn, unicode.Me):
+		return 0
+	}
+	for _, rng := range wideRanges {
+		if r < rng.lo {
+			break
+		}
+		if r <= rng.hi {
+			return 2
+		}
+	}
+	return 1
+}
@@ -2,31 +2,77 @@ package ui
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
-	"os"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
+
+	"imapsync/internal/i18n"
 )
 
-// SimpleTUI represents a simple TUI application
+// SimpleTUI represents a simple TUI application. After Start it runs full
+// screen with arrow-key navigation; otherwise, or when the terminal does not
+// support it, it falls back to numbered prompts.
 type SimpleTUI struct {
 	reader *bufio.Reader
+	screen *Screen // Set while running full screen
+	notes  []note  // Messages printed since the last WaitForKey
+	title  string  // Title of the last full-screen frame
+
+	progressStep string // Last step ShowProgress printed without a terminal
 }
 
 // NewSimpleTUI creates a new simple TUI
 func NewSimpleTUI() *SimpleTUI {
 	return &SimpleTUI{
-		reader: bufio.NewReader(os.Stdin),
+		reader: Stdin(),
+	}
+}
+
+// Start switches to full-screen mode. It fails, leaving the numbered prompts
+// in place, when standard input or output is not a terminal.
+func (tui *SimpleTUI) Start() error {
+	screen, err := NewScreen()
+	if err != nil {
+		return err
+	}
+	if err := screen.Start(); err != nil {
+		return err
 	}
+	tui.screen = screen
+	return nil
+}
+
+// Close leaves full-screen mode and prints any messages not yet shown
+func (tui *SimpleTUI) Close() {
+	if tui.screen == nil {
+		return
+	}
+	tui.screen.Stop()
+	tui.screen = nil
+	for _, n := range tui.notes {
+		fmt.Printf("  %s %s\n", glyphs(n.icon), glyphs(n.text))
+	}
+	tui.notes = nil
+}
+
+// FullScreen reports whether the interface is running full screen
+func (tui *SimpleTUI) FullScreen() bool {
+	return tui.screen != nil
 }
 
 // ShowBanner displays a beautiful banner
 func (tui *SimpleTUI) ShowBanner() {
+	if tui.FullScreen() {
+		return
+	}
 	tui.ClearScreen()
 	fmt.Println("")
 	fmt.Println(Cyan(Bold("         IMAPSYNC")))
 	fmt.Println("")
-	fmt.Println(Blue("  📧 Transfer emails between IMAP servers efficiently"))
+	fmt.Println(Blue("  📧 " + i18n.T("ui.banner.subtitle")))
 	fmt.Println("")
 	fmt.Println(Dim("  " + strings.Repeat("═", 50)))
 	fmt.Println("")
@@ -34,6 +80,9 @@ func (tui *SimpleTUI) ShowBanner() {
 
 // ShowMenu displays a beautiful menu
 func (tui *SimpleTUI) ShowMenu(title string, items []string) int {
+	if tui.FullScreen() {
+		return tui.menuScreen(title, items)
+	}
 	tui.ShowBanner()
 	fmt.Println(Bold(Yellow("  📋 " + title)))
 	fmt.Println(Dim("  " + strings.Repeat("─", 70)))
@@ -62,26 +111,28 @@ func (tui *SimpleTUI) ShowMenu(title string, items []string) int {
 		}
 		fmt.Printf("  %s %2d. %s\n", color(icon), i+1, White(item))
 	}
-	fmt.Printf("  %s %2d. %s\n", Red("🚪"), len(items)+1, Red("Exit"))
+	fmt.Printf("  %s %2d. %s\n", Red("🚪"), len(items)+1, Red(i18n.T("ui.menu.exit")))
 	fmt.Println("")
 	fmt.Println(Dim("  " + strings.Repeat("─", 70)))
 
 	for {
-		fmt.Print(Bold(Yellow("  🎯 Enter your choice (1-" + fmt.Sprintf("%d", len(items)+1) + "): ")))
-		var choice int
-		fmt.Scanln(&choice)
+		fmt.Print(Bold(Yellow("  🎯 " + i18n.T("ui.prompt.choice_range", len(items)+1) + " ")))
+		choice := tui.readChoice()
 		if choice == len(items)+1 {
 			return -1 // Exit
 		}
 		if choice > 0 && choice <= len(items) {
 			return choice - 1
 		}
-		fmt.Println(Red("  ❌ Invalid choice. Please try again."))
+		fmt.Println(Red("  ❌ " + i18n.T("ui.error.invalid_choice")))
 	}
 }
 
 // ShowModal displays a beautiful modal
 func (tui *SimpleTUI) ShowModal(title, content string, buttons []string) int {
+	if tui.FullScreen() {
+		return tui.modalScreen(title, content, buttons)
+	}
 	tui.ShowBanner()
 	fmt.Println(Bold(Yellow("  📋 " + title)))
 	fmt.Println(Dim("  " + strings.Repeat("─", 70)))
@@ -95,9 +146,10 @@ func (tui *SimpleTUI) ShowModal(title, content string, buttons []string) int {
 	for i, button := range buttons {
 		icon := "🔘"
 		color := White
-		if strings.Contains(strings.ToLower(button), "ok") || strings.Contains(strings.ToLower(button), "yes") {
+		switch buttonKind(button) {
+		case "confirm":
 			icon, color = "✅", Green
-		} else if strings.Contains(strings.ToLower(button), "cancel") || strings.Contains(strings.ToLower(button), "no") {
+		case "cancel":
 			icon, color = "❌", Red
 		}
 		if i > 0 {
@@ -108,78 +160,150 @@ func (tui *SimpleTUI) ShowModal(title, content string, buttons []string) int {
 	fmt.Println()
 	fmt.Println("")
 	for {
-		fmt.Print(Bold(Yellow("  🎯 Enter your choice: ")))
-		var choice int
-		fmt.Scanln(&choice)
+		fmt.Print(Bold(Yellow("  🎯 " + i18n.T("ui.prompt.choice") + " ")))
+		choice := tui.readChoice()
 		if choice == 0 {
 			return -1 // Cancel
 		}
 		if choice > 0 && choice <= len(buttons) {
 			return choice - 1
 		}
-		fmt.Println(Red("  ❌ Invalid choice. Please try again."))
+		fmt.Println(Red("  ❌ " + i18n.T("ui.error.invalid_choice")))
 	}
 }
 
-// ShowForm displays a beautiful form
-func (tui *SimpleTUI) ShowForm(title string, fields []string) map[string]string {
+// ShowForm displays a beautiful form. Every value is read as a full line and
+// checked against its field; an invalid value is reported under the field and
+// asked again. It returns nil if the user cancels it.
+func (tui *SimpleTUI) ShowForm(title string, fields []FormField) FormValues {
+	if tui.FullScreen() {
+		return tui.formScreen(title, fields)
+	}
 	tui.ShowBanner()
 	fmt.Println(Bold(Yellow("  📝 " + title)))
 	fmt.Println(Dim("  " + strings.Repeat("─", 70)))
 	fmt.Println("")
-	result := make(map[string]string)
+	result := make(FormValues, len(fields))
 	for _, field := range fields {
-		icon := "📝"
-		color := White
-		if strings.Contains(strings.ToLower(field), "email") {
-			icon, color = "📧", Blue
-		} else if strings.Contains(strings.ToLower(field), "password") {
-			icon, color = "🔒", Red
-		} else if strings.Contains(strings.ToLower(field), "server") {
-			icon, color = "🌐", Green
-		} else if strings.Contains(strings.ToLower(field), "port") {
-			icon, color = "🔌", Yellow
+		icon, style := fieldIcon(field)
+		hint := ""
+		switch {
+		case field.Kind == FieldBool:
+			hint = fmt.Sprintf(" (%s/%s)", i18n.T("button.yes"), i18n.T("button.no"))
+		case field.Kind == FieldSelect:
+			hint = " (" + strings.Join(field.Options, ", ") + ")"
+		}
+		if field.Default != "" {
+			hint += " [" + field.display(field.Default) + "]"
+		}
+		fmt.Printf("  %s %s:%s\n", style.Render(icon), White(field.Label), Dim(hint))
+
+		for {
+			fmt.Print(Cyan("  └─ "))
+			var input string
+			var err error
+			if field.Kind == FieldPassword {
+				input, err = ReadPassword()
+				fmt.Println()
+			} else {
+				input, err = ReadLine()
+			}
+			if errors.Is(err, ErrEchoUnsupported) {
+				fmt.Printf("     %s %s\n", Red("❌"), Red(err.Error()))
+			}
+			if err != nil {
+				return nil
+			}
+
+			value, err := field.parse(input)
+			if err != nil {
+				fmt.Printf("     %s %s\n", Red("❌"), Red(err.Error()))
+				continue
+			}
+			result[field.Label] = value
+			break
 		}
-		fmt.Printf("  %s %s:\n", color(icon), White(field))
-		fmt.Print(Cyan("  └─ "))
-		var value string
-		fmt.Scanln(&value)
-		result[field] = value
 		fmt.Println("")
 	}
 	fmt.Println(Dim("  " + strings.Repeat("─", 70)))
 	return result
 }
 
+// readChoice reads a menu number; anything else reads as 0
+func (tui *SimpleTUI) readChoice() int {
+	line, _ := ReadLine()
+	choice, err := strconv.Atoi(strings.TrimSpace(line))
+	if err != nil {
+		return 0
+	}
+	return choice
+}
+
 // ClearScreen clears the screen
 func (tui *SimpleTUI) ClearScreen() {
+	if tui.FullScreen() {
+		tui.screen.Clear()
+		return
+	}
+	if CurrentTheme().Plain {
+		return
+	}
 	fmt.Print("\033[2J")
 	fmt.Print("\033[H")
 }
 
 // PrintSuccess prints a success message
 func (tui *SimpleTUI) PrintSuccess(message string) {
+	if tui.FullScreen() {
+		tui.notes = append(tui.notes, note{"✅", message, StyleSuccess})
+		return
+	}
 	fmt.Printf("  %s %s\n", Green("✅"), Green(message))
 }
 
 // PrintError prints an error message
 func (tui *SimpleTUI) PrintError(message string) {
+	if tui.FullScreen() {
+		tui.notes = append(tui.notes, note{"❌", message, StyleError})
+		return
+	}
 	fmt.Printf("  %s %s\n", Red("❌"), Red(message))
 }
 
 // PrintInfo prints an info message
 func (tui *SimpleTUI) PrintInfo(message string) {
+	if tui.FullScreen() {
+		tui.notes = append(tui.notes, note{"ℹ️", message, StyleInfo})
+		return
+	}
 	fmt.Printf("  %s %s\n", Cyan("ℹ️"), Cyan(message))
 }
 
 // PrintWarning prints a warning message
 func (tui *SimpleTUI) PrintWarning(message string) {
+	if tui.FullScreen() {
+		tui.notes = append(tui.notes, note{"⚠️", message, StyleWarning})
+		return
+	}
 	fmt.Printf("  %s %s\n", Yellow("⚠️"), Yellow(message))
 }
 
 // ShowProgress displays a progress bar
 func (tui *SimpleTUI) ShowProgress(current, total int, description string) {
 	percentage := float64(current) / float64(total) * 100
+	if tui.FullScreen() {
+		tui.progressScreen(fmt.Sprintf("📊 %s [%s] %.1f%% (%d/%d)", description, progressBar(percentage, 40), percentage, current, total))
+		return
+	}
+	if CurrentTheme().Plain {
+		// A file or pipe cannot redraw a line; print one line per 10% step
+		step := fmt.Sprintf("%s %d", description, int(percentage)/10)
+		if step != tui.progressStep {
+			tui.progressStep = step
+			fmt.Printf("  %s %.1f%% (%d/%d)\n", description, percentage, current, total)
+		}
+		return
+	}
 	barWidth := 50
 	filled := int(float64(barWidth) * percentage / 100)
 	bar := Green(strings.Repeat("█", filled)) + Dim(strings.Repeat("░", barWidth-filled))
@@ -189,9 +313,26 @@ func (tui *SimpleTUI) ShowProgress(current, total int, description string) {
 	}
 }
 
+// InlineProgress reports whether ShowProgress redraws a single line, which
+// needs a newline when the progress stops before it is full
+func (tui *SimpleTUI) InlineProgress() bool {
+	return !tui.FullScreen() && !CurrentTheme().Plain
+}
+
 // ShowLoading displays a loading animation
 func (tui *SimpleTUI) ShowLoading(message string) {
 	frames := []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+	if tui.FullScreen() {
+		for i := 0; i < 10; i++ {
+			tui.progressScreen(frames[i%len(frames)] + " " + message)
+			time.Sleep(100 * time.Millisecond)
+		}
+		return
+	}
+	if CurrentTheme().Plain {
+		fmt.Println("  " + message)
+		return
+	}
 	for i := 0; i < 10; i++ {
 		fmt.Printf("\r  %s %s", Cyan(frames[i%len(frames)]), White(message))
 		time.Sleep(100 * time.Millisecond)
@@ -201,17 +342,37 @@ func (tui *SimpleTUI) ShowLoading(message string) {
 
 // WaitForKey waits for user to press Enter
 func (tui *SimpleTUI) WaitForKey() {
-	fmt.Print(Yellow("  ⏸️  Press Enter to continue..."))
+	if tui.FullScreen() {
+		tui.waitScreen()
+		return
+	}
+	fmt.Print(Yellow("  ⏸️  " + i18n.T("ui.press_enter")))
 	tui.reader.ReadString('\n')
 }
 
 // ShowRealTimeStats displays real-time statistics
 func (tui *SimpleTUI) ShowRealTimeStats(stats map[string]interface{}) {
+	if tui.FullScreen() {
+		top, bottom := tui.frame(i18n.T("ui.stats.title"), i18n.T("ui.stats.hint"))
+		keys := make([]string, 0, len(stats))
+		for key := range stats {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for i, key := range keys {
+			if top+i >= bottom {
+				break
+			}
+			tui.screen.SetString(2, top+i, fmt.Sprintf("%s: %v", key, stats[key]), StyleNormal)
+		}
+		tui.screen.Flush()
+		return
+	}
 	tui.ClearScreen()
 	fmt.Println("")
 	fmt.Println(Cyan(Bold("         IMAPSYNC")))
 	fmt.Println("")
-	fmt.Println(Bold(Yellow("  📊 Real-Time Statistics")))
+	fmt.Println(Bold(Yellow("  📊 " + i18n.T("ui.stats.title"))))
 	fmt.Println(Dim("  " + strings.Repeat("─", 70)))
 	fmt.Println("")
 
@@ -244,24 +405,35 @@ func (tui *SimpleTUI) ShowRealTimeStats(stats map[string]interface{}) {
 
 	fmt.Println("")
 	fmt.Println(Dim("  " + strings.Repeat("─", 70)))
-	fmt.Println(Yellow("  🔄 Press Enter to refresh, 'q' to quit"))
+	fmt.Println(Yellow("  🔄 " + i18n.T("ui.stats.hint")))
 }
 
 // ShowLiveProgress displays live progress with real-time updates
 func (tui *SimpleTUI) ShowLiveProgress(jobID string, current, total int, speed float64, eta time.Duration) {
 	percentage := float64(current) / float64(total) * 100
+	if tui.FullScreen() {
+		tui.progressScreen("📊 " + i18n.T("ui.progress.job", jobID) + " " + liveProgressText(current, total, speed, eta, 40))
+		return
+	}
 	barWidth := 50
 	filled := int(float64(barWidth) * percentage / 100)
 	bar := Green(strings.Repeat("█", filled)) + Dim(strings.Repeat("░", barWidth-filled))
 
-	fmt.Printf("\r  %s Job %s [%s] %.1f%% (%d/%d) %.2f KB/s ETA: %s",
+	// Without a terminal every call starts a new line instead of redrawing
+	start := "\r"
+	if CurrentTheme().Plain {
+		start = ""
+	}
+	fmt.Printf("%s  %s %s [%s] %.1f%% (%d/%d) %.2f KB/s %s: %s",
+		start,
 		Blue("📊"),
-		White(jobID),
+		White(i18n.T("ui.progress.job", jobID)),
 		bar,
 		percentage,
 		current,
 		total,
 		speed/1024,
+		i18n.T("ui.progress.eta"),
 		eta.Round(time.Second))
 
 	if current >= total {
@@ -0,0 +1,182 @@
+package ui
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"unicode/utf8"
+)
+
+// ErrInterrupted is returned when the user presses Ctrl+C during raw input
+var ErrInterrupted = errors.New("interrupted")
+
+// stdin is the single buffered reader for standard input. Every prompt must
+// read through it; a second reader would swallow input buffered by the first.
+var stdin = bufio.NewReader(os.Stdin)
+
+// Stdin returns the shared standard input reader
+func Stdin() *bufio.Reader {
+	return stdin
+}
+
+// ReadLine reads a full line from standard input without the line ending
+func ReadLine() (string, error) {
+	line, err := stdin.ReadString('\n')
+	if err == io.EOF && line != "" {
+		err = nil
+	}
+	return strings.TrimRight(line, "\r\n"), err
+}
+
+var (
+	rawMu      sync.Mutex
+	rawState   *termState // Terminal state to restore, nil when not in raw mode
+	rawSignals chan os.Signal
+	altScreen  bool // The alternate screen buffer is active
+)
+
+// EnableRawMode switches standard input to raw mode: no echo, no line
+// buffering and Ctrl+C delivered as a key. The returned function restores the
+// previous state. The terminal is also restored if the process receives
+// SIGTERM or SIGHUP, and RestoreTerminal can be deferred to cover panics.
+func EnableRawMode() (restore func(), err error) {
+	return enableRawMode(false)
+}
+
+// enableRawMode enters raw mode. With timed set, reads return after 100ms
+// without input so callers can poll for keys.
+func enableRawMode(timed bool) (restore func(), err error) {
+	rawMu.Lock()
+	defer rawMu.Unlock()
+
+	if rawState != nil {
+		return func() {}, nil
+	}
+
+	fd := int(os.Stdin.Fd())
+	state, err := makeRaw(fd, timed)
+	if err != nil {
+		return nil, err
+	}
+	rawState = state
+
+	rawSignals = make(chan os.Signal, 1)
+	signal.Notify(rawSignals, syscall.SIGTERM, syscall.SIGHUP)
+	go func(signals chan os.Signal) {
+		sig, ok := <-signals
+		if !ok {
+			return
+		}
+		RestoreTerminal()
+		// Let the default handler terminate the process
+		signal.Reset(sig)
+		if p, err := os.FindProcess(os.Getpid()); err == nil {
+			p.Signal(sig)
+		}
+	}(rawSignals)
+
+	return RestoreTerminal, nil
+}
+
+// RestoreTerminal leaves the alternate screen and raw mode if they are active.
+// It is safe to call at any time.
+func RestoreTerminal() {
+	rawMu.Lock()
+	defer rawMu.Unlock()
+
+	if altScreen {
+		os.Stdout.WriteString(showCursor + leaveAltScreen)
+		altScreen = false
+	}
+	if rawState == nil {
+		return
+	}
+	restoreTerm(int(os.Stdin.Fd()), rawState)
+	rawState = nil
+
+	signal.Stop(rawSignals)
+	close(rawSignals)
+	rawSignals = nil
+}
+
+// IsTerminal reports whether standard input is a terminal
+func IsTerminal() bool {
+	return isTerminal(int(os.Stdin.Fd()))
+}
+
+// ErrEchoUnsupported is returned by ReadPassword when standard input is a
+// terminal whose echo cannot be turned off
+var ErrEchoUnsupported = errors.New("cannot turn off terminal echo to read a password")
+
+// ReadPassword reads a line from standard input without echoing it.
+// Backspace deletes the last character and Ctrl+C aborts with ErrInterrupted.
+// When standard input is not a terminal the line is read as is. A terminal
+// whose echo cannot be disabled gives ErrEchoUnsupported rather than showing
+// the password.
+func ReadPassword() (string, error) {
+	if !IsTerminal() {
+		if stdinIsCharDevice() {
+			return "", ErrEchoUnsupported
+		}
+		return ReadLine()
+	}
+
+	restore, err := EnableRawMode()
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrEchoUnsupported, err)
+	}
+	defer restore()
+
+	var password []byte
+	for {
+		b, err := stdin.ReadByte()
+		if err != nil {
+			return "", err
+		}
+
+		switch b {
+		case '\r', '\n':
+			return string(password), nil
+		case 0x03: // Ctrl+C
+			restore()
+			fmt.Println()
+			interrupt()
+			return "", ErrInterrupted
+		case 0x04: // Ctrl+D
+			if len(password) == 0 {
+				return "", io.EOF
+			}
+		case 0x7f, 0x08: // Backspace
+			if len(password) > 0 {
+				_, size := utf8.DecodeLastRune(password)
+				password = password[:len(password)-size]
+			}
+		case 0x15: // Ctrl+U clears the line
+			password = password[:0]
+		default:
+			if b >= 0x20 || b == '\t' {
+				password = append(password, b)
+			}
+		}
+	}
+}
+
+// stdinIsCharDevice reports whether standard input is a character device,
+// which catches terminals that isTerminal cannot detect on this platform
+func stdinIsCharDevice() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// interrupt delivers SIGINT to the process so Ctrl+C behaves as it does outside raw mode
+func interrupt() {
+	if p, err := os.FindProcess(os.Getpid()); err == nil {
+		p.Signal(os.Interrupt)
+	}
+}
@@ -0,0 +1,11 @@
+//go:build darwin || freebsd
+
+package ui
+
+import "syscall"
+
+// Requests that read and apply the termios of a terminal
+const (
+	ioctlGetTermios = syscall.TIOCGETA
+	ioctlSetTermios = syscall.TIOCSETA
+)
@@ -0,0 +1,9 @@
+package ui
+
+import "syscall"
+
+// Requests that read and apply the termios of a terminal
+const (
+	ioctlGetTermios = syscall.TCGETS
+	ioctlSetTermios = syscall.TCSETS
+)
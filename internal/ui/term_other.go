@@ -0,0 +1,43 @@
+//go:build !linux && !darwin && !freebsd && !windows
+
+package ui
+
+import (
+	"errors"
+	"os"
+)
+
+// termState is a saved terminal configuration
+type termState struct{}
+
+// errRawUnsupported is returned where raw mode is not implemented
+var errRawUnsupported = errors.New("raw terminal mode is not supported on this platform")
+
+// isTerminal reports whether fd refers to a terminal. Without raw mode
+// support input is always read line by line.
+func isTerminal(fd int) bool {
+	return false
+}
+
+// makeRaw is not supported on this platform
+func makeRaw(fd int, timed bool) (*termState, error) {
+	return nil, errRawUnsupported
+}
+
+// restoreTerm is not supported on this platform
+func restoreTerm(fd int, state *termState) error {
+	return errRawUnsupported
+}
+
+// readTimed is not supported on this platform
+func readTimed(fd int, p []byte) (int, error) {
+	return 0, errRawUnsupported
+}
+
+// terminalSize is not supported on this platform
+func terminalSize(fd int) (cols, rows int, ok bool) {
+	return 0, 0, false
+}
+
+// notifyResize is not supported on this platform
+func notifyResize(ch chan os.Signal) {}
@@ -0,0 +1,90 @@
+//go:build linux || darwin || freebsd
+
+package ui
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+	"unsafe"
+)
+
+// termState is a saved terminal configuration
+type termState struct {
+	termios syscall.Termios
+}
+
+// ioctl gets or sets the termios of fd
+func ioctl(fd int, request uintptr, t *syscall.Termios) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), request, uintptr(unsafe.Pointer(t)))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// isTerminal reports whether fd refers to a terminal
+func isTerminal(fd int) bool {
+	var t syscall.Termios
+	return ioctl(fd, ioctlGetTermios, &t) == nil
+}
+
+// makeRaw disables echo, canonical mode and signal keys on fd and returns the previous state.
+// Output processing stays on so "\n" still starts a new line. With timed set,
+// a read returns no data after 100ms instead of blocking.
+func makeRaw(fd int, timed bool) (*termState, error) {
+	var old syscall.Termios
+	if err := ioctl(fd, ioctlGetTermios, &old); err != nil {
+		return nil, err
+	}
+
+	raw := old
+	raw.Iflag &^= syscall.ICRNL | syscall.IXON | syscall.BRKINT | syscall.INPCK | syscall.ISTRIP
+	raw.Lflag &^= syscall.ECHO | syscall.ICANON | syscall.ISIG | syscall.IEXTEN
+	raw.Cflag |= syscall.CS8
+	raw.Cc[syscall.VMIN] = 1
+	raw.Cc[syscall.VTIME] = 0
+	if timed {
+		raw.Cc[syscall.VMIN] = 0
+		raw.Cc[syscall.VTIME] = 1
+	}
+
+	if err := ioctl(fd, ioctlSetTermios, &raw); err != nil {
+		return nil, err
+	}
+	return &termState{termios: old}, nil
+}
+
+// restoreTerm applies a saved state to fd
+func restoreTerm(fd int, state *termState) error {
+	return ioctl(fd, ioctlSetTermios, &state.termios)
+}
+
+// readTimed reads from fd in timed raw mode; it returns 0 bytes when no input arrived
+func readTimed(fd int, p []byte) (int, error) {
+	n, err := syscall.Read(fd, p)
+	if err == syscall.EINTR || err == syscall.EAGAIN {
+		return 0, nil
+	}
+	return n, err
+}
+
+// winsize is the kernel's struct winsize
+type winsize struct {
+	rows, cols, xpixel, ypixel uint16
+}
+
+// terminalSize returns the size of the terminal on fd
+func terminalSize(fd int) (cols, rows int, ok bool) {
+	var ws winsize
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), uintptr(syscall.TIOCGWINSZ), uintptr(unsafe.Pointer(&ws)))
+	if errno != 0 || ws.cols == 0 || ws.rows == 0 {
+		return 0, 0, false
+	}
+	return int(ws.cols), int(ws.rows), true
+}
+
+// notifyResize delivers SIGWINCH to ch
+func notifyResize(ch chan os.Signal) {
+	signal.Notify(ch, syscall.SIGWINCH)
+}
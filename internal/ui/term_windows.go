@@ -0,0 +1,202 @@
+package ui
+
+import (
+	"os"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+// Console mode flags, see SetConsoleMode
+const (
+	enableProcessedInput            = 0x0001
+	enableLineInput                 = 0x0002
+	enableEchoInput                 = 0x0004
+	enableWindowInput               = 0x0008
+	enableVirtualTerminalInput      = 0x0200
+	enableVirtualTerminalProcessing = 0x0004
+)
+
+// Input record event types, see INPUT_RECORD
+const (
+	keyEvent              = 0x0001
+	windowBufferSizeEvent = 0x0004
+)
+
+var (
+	kernel32                       = syscall.NewLazyDLL("kernel32.dll")
+	procSetConsoleMode             = kernel32.NewProc("SetConsoleMode")
+	procGetConsoleScreenBufferInfo = kernel32.NewProc("GetConsoleScreenBufferInfo")
+	procPeekConsoleInput           = kernel32.NewProc("PeekConsoleInputW")
+	procReadConsoleInput           = kernel32.NewProc("ReadConsoleInputW")
+)
+
+// termState is a saved console configuration. The output mode is changed as
+// well so escape sequences are interpreted instead of printed.
+type termState struct {
+	inMode, outMode uint32
+	outSet          bool
+}
+
+// inputRecord is the console's INPUT_RECORD. For key events the union holds
+// bKeyDown, wRepeatCount, wVirtualKeyCode, wVirtualScanCode, uChar and
+// dwControlKeyState.
+type inputRecord struct {
+	eventType uint16
+	_         uint16
+	event     [16]byte
+}
+
+// keyChar returns the character of a key press, 0 for releases and other events
+func (r *inputRecord) keyChar() uint16 {
+	if r.eventType != keyEvent || *(*int32)(unsafe.Pointer(&r.event[0])) == 0 {
+		return 0
+	}
+	return *(*uint16)(unsafe.Pointer(&r.event[10]))
+}
+
+// coord and smallRect are the console's COORD and SMALL_RECT
+type coord struct{ x, y int16 }
+
+type smallRect struct{ left, top, right, bottom int16 }
+
+// consoleScreenBufferInfo is the console's CONSOLE_SCREEN_BUFFER_INFO
+type consoleScreenBufferInfo struct {
+	size              coord
+	cursorPosition    coord
+	attributes        uint16
+	window            smallRect
+	maximumWindowSize coord
+}
+
+var (
+	resizeMu sync.Mutex
+	resizeCh chan os.Signal // Receives console buffer size events, nil when nobody listens
+)
+
+// setConsoleMode applies mode to the console handle fd
+func setConsoleMode(fd int, mode uint32) error {
+	r, _, err := procSetConsoleMode.Call(uintptr(fd), uintptr(mode))
+	if r == 0 {
+		return err
+	}
+	return nil
+}
+
+// isTerminal reports whether fd refers to a console
+func isTerminal(fd int) bool {
+	var mode uint32
+	return syscall.GetConsoleMode(syscall.Handle(fd), &mode) == nil
+}
+
+// makeRaw disables echo, line input and Ctrl+C processing on the console
+// input fd and returns the previous state. Keys arrive as VT sequences, and
+// standard output interprets them too. With timed set, readTimed returns no
+// data after 100ms instead of blocking.
+func makeRaw(fd int, timed bool) (*termState, error) {
+	var state termState
+	if err := syscall.GetConsoleMode(syscall.Handle(fd), &state.inMode); err != nil {
+		return nil, err
+	}
+
+	raw := state.inMode&^(enableProcessedInput|enableLineInput|enableEchoInput) | enableVirtualTerminalInput | enableWindowInput
+	if err := setConsoleMode(fd, raw); err != nil {
+		return nil, err
+	}
+
+	out := int(os.Stdout.Fd())
+	if syscall.GetConsoleMode(syscall.Handle(out), &state.outMode) == nil {
+		if err := setConsoleMode(out, state.outMode|enableVirtualTerminalProcessing); err != nil {
+			setConsoleMode(fd, state.inMode)
+			return nil, err
+		}
+		state.outSet = true
+	}
+	return &state, nil
+}
+
+// restoreTerm applies a saved state to fd
+func restoreTerm(fd int, state *termState) error {
+	if state.outSet {
+		setConsoleMode(int(os.Stdout.Fd()), state.outMode)
+	}
+	return setConsoleMode(fd, state.inMode)
+}
+
+// readTimed reads from the console fd, waiting at most 100ms for a key; it
+// returns 0 bytes when no input arrived. Events without a character, such as
+// key releases, focus changes and resizes, are consumed so the read that
+// follows cannot block on them.
+func readTimed(fd int, p []byte) (int, error) {
+	h := syscall.Handle(fd)
+	ev, err := syscall.WaitForSingleObject(h, 100)
+	if err != nil {
+		return 0, err
+	}
+	if ev != syscall.WAIT_OBJECT_0 {
+		return 0, nil
+	}
+
+	var records [16]inputRecord
+	var n uint32
+	if r, _, err := procPeekConsoleInput.Call(uintptr(h), uintptr(unsafe.Pointer(&records[0])), uintptr(len(records)), uintptr(unsafe.Pointer(&n))); r == 0 {
+		return 0, err
+	}
+	for _, rec := range records[:n] {
+		if rec.keyChar() != 0 {
+			var read uint32
+			err := syscall.ReadFile(h, p, &read, nil)
+			return int(read), err
+		}
+	}
+
+	// Nothing readable: drop the peeked events
+	if r, _, err := procReadConsoleInput.Call(uintptr(h), uintptr(unsafe.Pointer(&records[0])), uintptr(n), uintptr(unsafe.Pointer(&n))); r == 0 {
+		return 0, err
+	}
+	for _, rec := range records[:n] {
+		if rec.eventType == windowBufferSizeEvent {
+			signalResize()
+		}
+	}
+	return 0, nil
+}
+
+// terminalSize returns the size of the visible console window on fd
+func terminalSize(fd int) (cols, rows int, ok bool) {
+	var info consoleScreenBufferInfo
+	r, _, _ := procGetConsoleScreenBufferInfo.Call(uintptr(fd), uintptr(unsafe.Pointer(&info)))
+	if r == 0 {
+		return 0, 0, false
+	}
+	cols = int(info.window.right-info.window.left) + 1
+	rows = int(info.window.bottom-info.window.top) + 1
+	return cols, rows, cols > 0 && rows > 0
+}
+
+// notifyResize delivers console resize events to ch. Windows has no SIGWINCH,
+// so readTimed reports them as it sees them.
+func notifyResize(ch chan os.Signal) {
+	resizeMu.Lock()
+	resizeCh = ch
+	resizeMu.Unlock()
+}
+
+// resizeSignal stands in for SIGWINCH on the resize channel
+type resizeSignal struct{}
+
+func (resizeSignal) String() string { return "window size changed" }
+func (resizeSignal) Signal()        {}
+
+// signalResize notifies the resize listener without blocking
+func signalResize() {
+	resizeMu.Lock()
+	defer resizeMu.Unlock()
+	if resizeCh == nil {
+		return
+	}
+	select {
+	case resizeCh <- resizeSignal{}:
+	default:
+	}
+}
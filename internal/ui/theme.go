@@ -0,0 +1,263 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// ColorMode is how many colors the output can show
+type ColorMode int
+
+const (
+	ColorNone ColorMode = iota // No colors; full-screen mode still uses bold and reverse video
+	Color16                    // The basic ANSI colors
+	Color256                   // The xterm 256-color palette
+	ColorTrue                  // 24-bit RGB
+)
+
+// String returns the name of the mode as accepted by ThemeConfig.Color
+func (m ColorMode) String() string {
+	switch m {
+	case Color16:
+		return "16"
+	case Color256:
+		return "256"
+	case ColorTrue:
+		return "truecolor"
+	}
+	return "never"
+}
+
+// ThemeConfig overrides the theme detected from the terminal. Empty fields keep
+// the detected value.
+type ThemeConfig struct {
+	Palette string `json:"palette"` // "default" or "high-contrast"
+	Color   string `json:"color"`   // "auto", "never", "always", "16", "256" or "truecolor"
+	ASCII   bool   `json:"ascii"`   // Plain ASCII instead of emoji, box drawing and arrows
+}
+
+// Theme decides how the ui package colors and decorates its output
+type Theme struct {
+	Palette string    // Name of the palette
+	Colors  ColorMode // Colors of the output
+	ASCII   bool      // Replace emoji, box drawing and arrows with ASCII
+	Plain   bool      // No escape codes at all, e.g. when the output is a file
+}
+
+// color is a palette entry: the basic ANSI code for 16-color output and the
+// RGB value for 256-color and truecolor output
+type color struct {
+	ansi    int
+	r, g, b uint8
+}
+
+// palette maps the foreground styles to colors
+type palette struct {
+	colors map[Style]color
+	dim    bool // Dim text is drawn dim; high contrast draws it normally
+}
+
+// palettes are the available palettes by name
+var palettes = map[string]palette{
+	"default": {
+		colors: map[Style]color{
+			fgCyan:   {36, 0, 175, 215},
+			fgGreen:  {32, 95, 215, 95},
+			fgYellow: {33, 255, 215, 0},
+			fgRed:    {31, 255, 95, 95},
+			fgBlue:   {34, 95, 135, 255},
+			fgPurple: {35, 175, 135, 255},
+			fgWhite:  {37, 228, 228, 228},
+		},
+		dim: true,
+	},
+	"high-contrast": {
+		colors: map[Style]color{
+			fgCyan:   {96, 0, 255, 255},
+			fgGreen:  {92, 0, 255, 0},
+			fgYellow: {93, 255, 255, 0},
+			fgRed:    {91, 255, 85, 85},
+			fgBlue:   {96, 135, 215, 255},
+			fgPurple: {95, 255, 135, 255},
+			fgWhite:  {97, 255, 255, 255},
+		},
+	},
+}
+
+var (
+	themeMu sync.RWMutex
+	theme   = DetectTheme()
+)
+
+// DetectTheme returns the theme for the environment: no escape codes and ASCII
+// glyphs when standard output is not a terminal or TERM is "dumb", no colors
+// when NO_COLOR is set, and 256 colors or truecolor when TERM or COLORTERM
+// announce them
+func DetectTheme() Theme {
+	t := Theme{Palette: "default", Colors: detectColors()}
+	t.Plain = !stdoutIsTerminal() || os.Getenv("TERM") == "dumb"
+	t.ASCII = t.Plain
+	if t.Plain || os.Getenv("NO_COLOR") != "" {
+		t.Colors = ColorNone
+	}
+	return t
+}
+
+// stdoutIsTerminal reports whether standard output is a character device such
+// as a terminal. Unlike isTerminal it works on every platform.
+func stdoutIsTerminal() bool {
+	info, err := os.Stdout.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// detectColors returns the color depth the terminal announces
+func detectColors() ColorMode {
+	switch colorterm := strings.ToLower(os.Getenv("COLORTERM")); {
+	case colorterm == "truecolor" || colorterm == "24bit":
+		return ColorTrue
+	case strings.Contains(os.Getenv("TERM"), "256color"):
+		return Color256
+	}
+	return Color16
+}
+
+// NewTheme applies cfg to the detected theme
+func NewTheme(cfg ThemeConfig) (Theme, error) {
+	t := DetectTheme()
+	if cfg.Palette != "" {
+		if _, ok := palettes[cfg.Palette]; !ok {
+			return t, fmt.Errorf("unknown palette %q (available: default, high-contrast)", cfg.Palette)
+		}
+		t.Palette = cfg.Palette
+	}
+
+	// Forcing colors, e.g. for "less -R", also keeps the emoji and the redraws
+	switch cfg.Color {
+	case "", "auto":
+	case "never":
+		t.Colors = ColorNone
+	case "always":
+		t.Colors, t.Plain, t.ASCII = detectColors(), false, false
+	case "16":
+		t.Colors, t.Plain, t.ASCII = Color16, false, false
+	case "256":
+		t.Colors, t.Plain, t.ASCII = Color256, false, false
+	case "truecolor":
+		t.Colors, t.Plain, t.ASCII = ColorTrue, false, false
+	default:
+		return t, fmt.Errorf("unknown color mode %q (available: auto, never, always, 16, 256, truecolor)", cfg.Color)
+	}
+
+	t.ASCII = t.ASCII || cfg.ASCII
+	return t, nil
+}
+
+// SetTheme switches the theme of all further output
+func SetTheme(t Theme) {
+	if _, ok := palettes[t.Palette]; !ok {
+		t.Palette = "default"
+	}
+	themeMu.Lock()
+	defer themeMu.Unlock()
+	theme = t
+}
+
+// CurrentTheme returns the active theme
+func CurrentTheme() Theme {
+	themeMu.RLock()
+	defer themeMu.RUnlock()
+	return theme
+}
+
+// code returns the escape sequence of a palette color in a color mode
+func (c color) code(mode ColorMode) string {
+	switch mode {
+	case Color16:
+		return fmt.Sprintf("\033[%dm", c.ansi)
+	case Color256:
+		return fmt.Sprintf("\033[38;5;%dm", cubeIndex(c.r, c.g, c.b))
+	case ColorTrue:
+		return fmt.Sprintf("\033[38;2;%d;%d;%dm", c.r, c.g, c.b)
+	}
+	return ""
+}
+
+// cubeIndex returns the nearest color of the 6×6×6 cube of the 256-color palette
+func cubeIndex(r, g, b uint8) int {
+	level := func(v uint8) int {
+		if v < 48 {
+			return 0
+		}
+		if v < 115 {
+			return 1
+		}
+		return int(v-35) / 40
+	}
+	return 16 + 36*level(r) + 6*level(g) + level(b)
+}
+
+// asciiGlyphs replace symbols in ASCII mode. Other emoji are dropped.
+var asciiGlyphs = map[rune]string{
+	'✅': "[ok]", '✔': "[ok]", '❌': "[x]", '✗': "[x]", '⚠': "[!]", 'ℹ': "[i]", '✓': "*",
+	'─': "-", '━': "-", '═': "=", '│': "|", '┌': "+", '┐': "+", '└': "+", '┘': "+", '├': "+", '┤': "+",
+	'█': "#", '░': ".", '▶': ">", '◀': "<", '•': "*", '…': "...", '·': "-",
+	'↑': "^", '↓': "v", '←': "<", '→': "->",
+}
+
+// glyphs returns text with the symbols the theme cannot show replaced
+func glyphs(text string) string {
+	if !CurrentTheme().ASCII {
+		return text
+	}
+
+	var b strings.Builder
+	runes := []rune(text)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if ascii, ok := asciiGlyphs[r]; ok {
+			b.WriteString(ascii)
+			continue
+		}
+		switch {
+		case r == '\uFE0F' || r == '\uFE0E':
+			// Emoji presentation selectors
+		case r == zeroWidthJoiner:
+			// Drop the rest of a sequence such as 👨‍💻
+			i++
+		case r >= 0x2800 && r <= 0x28FF:
+			// Braille spinner frames
+			b.WriteByte('*')
+		case isEmoji(r):
+			// Decorative icons carry no information; drop the whole sequence and the space after it
+			for i+1 < len(runes) {
+				if runes[i+1] == '\uFE0F' {
+					i++
+				} else if runes[i+1] == zeroWidthJoiner {
+					i += 2
+				} else {
+					break
+				}
+			}
+			if i+1 < len(runes) && runes[i+1] == ' ' {
+				i++
+			}
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// isEmoji reports whether r is a pictograph, dingbat or other icon
+func isEmoji(r rune) bool {
+	switch {
+	case r == 0x2139: // Information source, ℹ
+		return true
+	case r >= 0x2300 && r <= 0x23FF, r >= 0x2600 && r <= 0x27BF, r >= 0x2B00 && r <= 0x2BFF, r >= 0x1F000 && r <= 0x1FAFF:
+		return true
+	}
+	return r > unicode.MaxLatin1 && unicode.Is(unicode.So, r) && RuneWidth(r) == 2
+}
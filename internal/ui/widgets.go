@@ -0,0 +1,491 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"imapsync/internal/i18n"
+)
+
+// List is a scrollable list of lines. With Selected >= 0 one item is
+// highlighted and the arrow keys move the selection; with Selected < 0 the
+// arrow keys scroll the content.
+type List struct {
+	Items    []string
+	Selected int
+	Offset   int // First visible item
+}
+
+// Draw renders the visible part of the list into the given area
+func (l *List) Draw(s *Screen, x, y, width, height int, style func(i int) Style) {
+	l.clamp(height)
+	for row := 0; row < height && l.Offset+row < len(l.Items); row++ {
+		i := l.Offset + row
+		itemStyle := StyleNormal
+		if style != nil {
+			itemStyle = style(i)
+		}
+		text := Truncate(l.Items[i], width-1)
+		if i == l.Selected {
+			text += strings.Repeat(" ", width-1-StringWidth(text))
+			itemStyle = StyleSelected
+		}
+		s.SetString(x, y+row, text, itemStyle)
+	}
+
+	// Scroll markers
+	if l.Offset > 0 {
+		s.SetString(x+width-1, y, "▲", StyleDim)
+	}
+	if l.Offset+height < len(l.Items) {
+		s.SetString(x+width-1, y+height-1, "▼", StyleDim)
+	}
+}
+
+// HandleKey applies a navigation key and reports whether it was used
+func (l *List) HandleKey(key Key, height int) bool {
+	step := 0
+	switch key.Code {
+	case KeyUp:
+		step = -1
+	case KeyDown:
+		step = 1
+	case KeyPgUp:
+		step = -max(height-1, 1)
+	case KeyPgDn:
+		step = max(height-1, 1)
+	case KeyHome:
+		step = -len(l.Items)
+	case KeyEnd:
+		step = len(l.Items)
+	case KeyRune:
+		switch key.Rune {
+		case 'k':
+			step = -1
+		case 'j':
+			step = 1
+		default:
+			return false
+		}
+	default:
+		return false
+	}
+
+	if l.Selected >= 0 {
+		l.Selected = min(max(l.Selected+step, 0), len(l.Items)-1)
+	} else {
+		l.Offset += step
+	}
+	l.clamp(height)
+	return true
+}
+
+// clamp keeps the offset in range and the selection visible
+func (l *List) clamp(height int) {
+	if l.Selected >= 0 {
+		if l.Selected < l.Offset {
+			l.Offset = l.Selected
+		}
+		if l.Selected >= l.Offset+height {
+			l.Offset = l.Selected - height + 1
+		}
+	}
+	l.Offset = min(l.Offset, len(l.Items)-height)
+	l.Offset = max(l.Offset, 0)
+}
+
+// wrapText splits text into lines no wider than width cells
+func wrapText(text string, width int) []string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		for StringWidth(line) > width && width > 0 {
+			used, cut := 0, 0
+			for i, r := range line {
+				w := RuneWidth(r)
+				if used+w > width {
+					cut = i
+					break
+				}
+				used += w
+			}
+			if cut == 0 {
+				break
+			}
+			lines = append(lines, line[:cut])
+			line = line[cut:]
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// note is a message printed while the full-screen interface is active
+type note struct {
+	icon  string
+	text  string
+	style Style
+}
+
+// frame clears the screen and draws the banner, title and key hints. It
+// returns the rows available for content: top up to, but not including, bottom.
+func (tui *SimpleTUI) frame(title, hints string) (top, bottom int) {
+	s := tui.screen
+	s.Clear()
+	width, height := s.Size()
+	rule := min(70, width-4)
+	tui.title = title
+
+	if height >= 16 {
+		s.SetString(9, 1, "IMAPSYNC", StyleBanner)
+		s.SetString(2, 2, "📧 "+i18n.T("ui.banner.subtitle"), StyleAccent)
+		s.SetString(2, 3, strings.Repeat("═", min(50, width-4)), StyleDim)
+		s.SetString(2, 5, "📋 "+title, StyleTitle)
+		s.SetString(2, 6, strings.Repeat("─", rule), StyleDim)
+		top = 8
+	} else {
+		s.SetString(2, 0, "📋 "+title, StyleTitle)
+		s.SetString(2, 1, strings.Repeat("─", rule), StyleDim)
+		top = 2
+	}
+
+	s.SetString(2, height-2, strings.Repeat("─", rule), StyleDim)
+	s.SetString(2, height-1, Truncate(hints, width-4), StyleDim)
+	return top, height - 2
+}
+
+// menuScreen is ShowMenu in full-screen mode
+func (tui *SimpleTUI) menuScreen(title string, items []string) int {
+	digits := len(strconv.Itoa(len(items) + 1))
+	labels := make([]string, len(items)+1)
+	for i, item := range items {
+		labels[i] = fmt.Sprintf("%*d. %s", digits, i+1, item)
+	}
+	labels[len(items)] = fmt.Sprintf("%*d. 🚪 %s", digits, len(items)+1, i18n.T("ui.menu.exit"))
+	list := &List{Items: labels}
+
+	for {
+		s := tui.screen
+		top, bottom := tui.frame(title, i18n.T("ui.hint.menu"))
+		width, _ := s.Size()
+		list.Draw(s, 2, top, width-4, bottom-top, func(i int) Style {
+			if i == len(items) {
+				return StyleError
+			}
+			return StyleNormal
+		})
+		s.Flush()
+
+		key := s.ReadKey()
+		switch {
+		case list.HandleKey(key, bottom-top):
+		case key.Code == KeyEnter:
+			if list.Selected == len(items) {
+				return -1
+			}
+			return list.Selected
+		case key.Code == KeyEsc, key.Code == KeyCtrlC, key.Code == KeyRune && key.Rune == 'q':
+			return -1
+		case key.Code == KeyRune && key.Rune >= '1' && key.Rune <= '9':
+			if n := int(key.Rune - '1'); n < len(labels) {
+				list.Selected = n
+			}
+		}
+	}
+}
+
+// modalScreen is ShowModal in full-screen mode
+func (tui *SimpleTUI) modalScreen(title, content string, buttons []string) int {
+	selected := 0
+	list := &List{Selected: -1}
+	lastWidth := -1
+
+	for {
+		s := tui.screen
+		width, _ := s.Size()
+		top, bottom := tui.frame(title, i18n.T("ui.hint.modal"))
+		if width != lastWidth {
+			list.Items = wrapText(strings.TrimRight(content, "\n"), width-5)
+			lastWidth = width
+		}
+
+		// Buttons sit on the last content row
+		buttonRow := bottom - 1
+		list.Draw(s, 2, top, width-4, max(buttonRow-top-1, 1), nil)
+		x := 2
+		for i, button := range buttons {
+			style := StyleNormal
+			if i == selected {
+				style = StyleSelected
+			}
+			x = s.SetString(x, buttonRow, "[ "+button+" ]", style) + 2
+		}
+		s.Flush()
+
+		key := s.ReadKey()
+		switch {
+		case list.HandleKey(key, buttonRow-top-1):
+		case key.Code == KeyLeft, key.Code == KeyBacktab:
+			selected = (selected + len(buttons) - 1) % max(len(buttons), 1)
+		case key.Code == KeyRight, key.Code == KeyTab:
+			selected = (selected + 1) % max(len(buttons), 1)
+		case key.Code == KeyEnter:
+			if len(buttons) == 0 {
+				return -1
+			}
+			return selected
+		case key.Code == KeyEsc, key.Code == KeyCtrlC, key.Code == KeyRune && key.Rune == 'q':
+			return -1
+		case key.Code == KeyRune && key.Rune >= '1' && key.Rune <= '9':
+			if n := int(key.Rune - '1'); n < len(buttons) {
+				return n
+			}
+		}
+	}
+}
+
+// formScreen is ShowForm in full-screen mode. A field is checked when the
+// focus leaves it and its error is shown below it; Enter on the last field
+// submits once all fields are valid. Esc cancels and returns nil.
+func (tui *SimpleTUI) formScreen(title string, fields []FormField) FormValues {
+	values := make([][]rune, len(fields))
+	cursor := make([]int, len(fields))
+	choice := make([]int, len(fields)) // Selected choice of bool and select fields
+	errs := make([]string, len(fields))
+	focus, offset := 0, 0
+
+	for i, field := range fields {
+		for n, c := range field.choices() {
+			if c == field.Default {
+				choice[i] = n
+			}
+		}
+	}
+	choosing := func(i int) bool {
+		return fields[i].Kind == FieldBool || fields[i].Kind == FieldSelect
+	}
+	input := func(i int) string {
+		if choosing(i) {
+			if choices := fields[i].choices(); len(choices) > 0 {
+				return choices[choice[i]]
+			}
+			return ""
+		}
+		return string(values[i])
+	}
+	check := func(i int) bool {
+		_, err := fields[i].parse(input(i))
+		errs[i] = ""
+		if err != nil {
+			errs[i] = err.Error()
+		}
+		return err == nil
+	}
+
+	for {
+		s := tui.screen
+		width, _ := s.Size()
+		top, bottom := tui.frame(title, i18n.T("ui.hint.form"))
+
+		// Each field takes a label row, an input row and a row for its error
+		visible := max((bottom-top)/3, 1)
+		if focus < offset {
+			offset = focus
+		}
+		if focus >= offset+visible {
+			offset = focus - visible + 1
+		}
+
+		for i := offset; i < len(fields) && i < offset+visible; i++ {
+			y := top + (i-offset)*3
+			icon, style := fieldIcon(fields[i])
+			labelStyle := StyleNormal
+			if i == focus {
+				labelStyle = StyleBold
+			}
+			x := s.SetString(2, y, icon, style)
+			s.SetString(x+1, y, fields[i].Label+":", labelStyle)
+
+			x = s.SetString(2, y+1, "└─ ", StyleInfo)
+			switch {
+			case choosing(i):
+				s.SetString(x, y+1, "◀ "+fields[i].display(input(i))+" ▶", labelStyle)
+			case len(values[i]) == 0 && fields[i].Default != "":
+				s.SetString(x, y+1, fields[i].Default, StyleDim)
+				if i == focus {
+					s.ShowCursor(x, y+1)
+				}
+			default:
+				text := string(values[i])
+				if fields[i].Kind == FieldPassword {
+					text = strings.Repeat("•", len(values[i]))
+				}
+				// Keep the cursor in view on long values
+				runes := []rune(text)
+				start := max(cursor[i]-(width-x-2), 0)
+				s.SetString(x, y+1, string(runes[start:]), StyleNormal)
+				if i == focus {
+					s.ShowCursor(x+StringWidth(string(runes[start:cursor[i]])), y+1)
+				}
+			}
+			if errs[i] != "" {
+				s.SetString(5, y+2, Truncate("❌ "+errs[i], width-7), StyleError)
+			}
+		}
+		s.Flush()
+
+		key := s.ReadKey()
+		value := values[focus]
+		pos := cursor[focus]
+		switch key.Code {
+		case KeyEsc, KeyCtrlC:
+			return nil
+		case KeyUp, KeyBacktab:
+			check(focus)
+			focus = (focus + len(fields) - 1) % len(fields)
+			continue
+		case KeyDown, KeyTab:
+			check(focus)
+			focus = (focus + 1) % len(fields)
+			continue
+		case KeyEnter:
+			if !check(focus) {
+				continue
+			}
+			if focus < len(fields)-1 {
+				focus++
+				continue
+			}
+			result := make(FormValues, len(fields))
+			for i := range fields {
+				if !check(i) {
+					focus = i
+					break
+				}
+				result[fields[i].Label], _ = fields[i].parse(input(i))
+			}
+			if len(result) == len(fields) {
+				return result
+			}
+			continue
+		}
+
+		if choosing(focus) {
+			n := len(fields[focus].choices())
+			switch {
+			case n == 0:
+			case key.Code == KeyLeft:
+				choice[focus] = (choice[focus] + n - 1) % n
+			case key.Code == KeyRight, key.Code == KeyRune && key.Rune == ' ':
+				choice[focus] = (choice[focus] + 1) % n
+			case key.Code == KeyRune:
+				// Jump to the next choice starting with the typed letter, e.g. "y" for Yes
+				for step := 1; step <= n; step++ {
+					c := (choice[focus] + step) % n
+					if strings.HasPrefix(strings.ToLower(fields[focus].display(fields[focus].choices()[c])), strings.ToLower(string(key.Rune))) {
+						choice[focus] = c
+						break
+					}
+				}
+			}
+			errs[focus] = ""
+			continue
+		}
+
+		switch key.Code {
+		case KeyLeft:
+			cursor[focus] = max(pos-1, 0)
+		case KeyRight:
+			cursor[focus] = min(pos+1, len(value))
+		case KeyHome:
+			cursor[focus] = 0
+		case KeyEnd:
+			cursor[focus] = len(value)
+		case KeyBackspace:
+			if pos > 0 {
+				values[focus] = append(value[:pos-1], value[pos:]...)
+				cursor[focus] = pos - 1
+				errs[focus] = ""
+			}
+		case KeyDelete:
+			if pos < len(value) {
+				values[focus] = append(value[:pos], value[pos+1:]...)
+				errs[focus] = ""
+			}
+		case KeyRune:
+			values[focus] = append(value[:pos], append([]rune{key.Rune}, value[pos:]...)...)
+			cursor[focus] = pos + 1
+			errs[focus] = ""
+		}
+	}
+}
+
+// buttonKind reports whether a modal button confirms or cancels, in English or
+// the active language. It returns an empty string for other buttons.
+func buttonKind(button string) string {
+	lower := strings.ToLower(button)
+	matches := func(keys ...string) bool {
+		for _, key := range keys {
+			if strings.Contains(lower, strings.ToLower(i18n.T(key))) {
+				return true
+			}
+		}
+		return false
+	}
+	switch {
+	case strings.Contains(lower, "ok") || strings.Contains(lower, "yes") || matches("button.ok", "button.yes"):
+		return "confirm"
+	case strings.Contains(lower, "cancel") || strings.Contains(lower, "no") || matches("button.cancel", "button.no"):
+		return "cancel"
+	}
+	return ""
+}
+
+// waitScreen is WaitForKey in full-screen mode: it shows the pending messages
+// under the last title until a key is pressed
+func (tui *SimpleTUI) waitScreen() {
+	defer func() { tui.notes = nil }()
+
+	for {
+		s := tui.screen
+		top, bottom := tui.frame(tui.title, i18n.T("ui.hint.wait"))
+		notes := tui.notes
+		if len(notes) > bottom-top {
+			notes = notes[len(notes)-(bottom-top):]
+		}
+		width, _ := s.Size()
+		for i, n := range notes {
+			s.SetString(2, top+i, Truncate(n.icon+" "+n.text, width-4), n.style)
+		}
+		s.Flush()
+
+		if key := s.ReadKey(); key.Code != KeyResize {
+			return
+		}
+	}
+}
+
+// progressScreen draws a progress bar with the pending messages above it
+func (tui *SimpleTUI) progressScreen(line string) {
+	s := tui.screen
+	top, bottom := tui.frame(tui.title, i18n.T("ui.hint.working"))
+	width, _ := s.Size()
+	y := top
+	for _, n := range tui.notes {
+		if y >= bottom-2 {
+			break
+		}
+		s.SetString(2, y, Truncate(n.icon+" "+n.text, width-4), n.style)
+		y++
+	}
+	s.SetString(2, y+1, Truncate(line, width-4), StyleNormal)
+	s.Flush()
+	// Keep the buffers in step with the terminal size while busy
+	s.checkResize()
+}
+
+// progressBar renders a bar of width cells for a percentage
+func progressBar(percentage float64, width int) string {
+	filled := min(max(int(float64(width)*percentage/100), 0), width)
+	return strings.Repeat("█", filled) + strings.Repeat("░", width-filled)
+}